@@ -0,0 +1,271 @@
+package valex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// alias is a registered chain, kept both as written and parsed.
+type alias struct {
+	chain string
+	segs  []segment
+}
+
+// AliasCycleError is returned by Alias when the new alias would expand into
+// itself, directly or through other aliases. Cycle lists the names in order,
+// starting and ending with the new alias.
+type AliasCycleError struct {
+	Cycle []string
+}
+
+func (e *AliasCycleError) Error() string {
+	return "valex: alias cycle " + strings.Join(e.Cycle, " -> ")
+}
+
+// UnknownAliasParamError is returned when an alias is used with a parameter
+// that matches none of the placeholders in its chain, usually a misspelling.
+type UnknownAliasParamError struct {
+	Alias string
+	Param string
+}
+
+func (e *UnknownAliasParamError) Error() string {
+	return fmt.Sprintf("alias %q has no placeholder $%s", e.Alias, e.Param)
+}
+
+// Alias registers name as shorthand for chain, a "val" tag value, so a field can
+// say `val:"useremail"` instead of repeating `val:"trim;lower;email;max,size=254"`
+// across structs. An alias is used like a directive and can appear anywhere in
+// a chain; at validation time it is replaced by its chain.
+//
+// A parameter value written as $name in chain is a placeholder, bound by the
+// parameter of that name where the alias is used:
+//
+//	reg.Alias("username", "trim;length,min=$min,max=$max")
+//	// Login string `val:"username,min=3,max=20"`
+//
+// A placeholder with no value at the use site fails validation with a
+// *MissingParamError naming the alias, and a parameter that matches no
+// placeholder fails with an *UnknownAliasParamError; quote the value ('$name')
+// for a literal dollar sign. Chains may use other aliases, including ones registered later,
+// but not in a cycle: Alias returns an *AliasCycleError if name would expand
+// into itself. It returns *EmptyDirectiveNameError for a blank name,
+// *DuplicateDirectiveError if name is already a directive or an alias, and an
+// error wrapping *DirectiveParseError or *ParamParseError for a malformed or
+// empty chain.
+func (r *Registry) Alias(name, chain string) error {
+	if strings.TrimSpace(name) == "" {
+		return &EmptyDirectiveNameError{}
+	}
+	if name != strings.TrimSpace(name) || strings.ContainsAny(name, ",;='|:") {
+		return fmt.Errorf("valex: invalid alias name %q", name)
+	}
+	segs, err := parseChain(chain)
+	if err != nil {
+		return fmt.Errorf("valex: alias %q: %w", name, err)
+	}
+	if len(segs) == 0 {
+		return fmt.Errorf("valex: alias %q: %w", name, &DirectiveParseError{TagValue: chain})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.nameTaken(name) {
+		return &DuplicateDirectiveError{Name: name}
+	}
+	if cycle := r.findCycle(name, segs, []string{name}, make(map[string]bool)); cycle != nil {
+		return &AliasCycleError{Cycle: cycle}
+	}
+	if r.aliases == nil {
+		r.aliases = make(map[string]*alias)
+	}
+	r.aliases[name] = &alias{chain: chain, segs: segs}
+	return nil
+}
+
+// MustAlias is like Alias but panics if registration fails.
+func (r *Registry) MustAlias(name, chain string) {
+	if err := r.Alias(name, chain); err != nil {
+		panic(err)
+	}
+}
+
+// Aliases returns the registry's aliases, mapping each name to its chain as
+// registered.
+func (r *Registry) Aliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m := make(map[string]string, len(r.aliases))
+	for name, a := range r.aliases {
+		m[name] = a.chain
+	}
+	return m
+}
+
+// ExpandTag returns tagValue with every alias replaced by its chain and its
+// placeholders bound, so tooling that inspects tags — linters, schema and
+// documentation generators — sees the directives that actually run. The result
+// is in canonical form: segments joined by ';', no spaces, and values quoted
+// only where needed. Names that are not aliases are kept as they are, whether or
+// not they are registered directives.
+func (r *Registry) ExpandTag(tagValue string) (string, error) {
+	segs, err := parseChain(tagValue)
+	if err != nil {
+		return "", chainError(err)
+	}
+	segs, err = r.expand(segs)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(segs))
+	for i, seg := range segs {
+		parts[i] = seg.String()
+	}
+	return strings.Join(parts, ";"), nil
+}
+
+// nameTaken reports whether name is a directive, an alias, or omitempty. r.mu
+// must be held.
+func (r *Registry) nameTaken(name string) bool {
+	if name == omitEmpty {
+		return true
+	}
+	_, isDirective := r.directives[name]
+	_, isAlias := r.aliases[name]
+	return isDirective || isAlias
+}
+
+// findCycle returns the path by which segs lead back to name through the
+// registered aliases, or nil. r.mu must be held.
+func (r *Registry) findCycle(name string, segs []segment, path []string, seen map[string]bool) []string {
+	for _, seg := range segs {
+		if seg.name == name {
+			return append(path, name)
+		}
+		a, ok := r.aliases[seg.name]
+		if !ok || seen[seg.name] {
+			continue
+		}
+		seen[seg.name] = true
+		if cycle := r.findCycle(name, a.segs, append(path[:len(path):len(path)], seg.name), seen); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// expand replaces alias segments with their chains, recursively, binding
+// placeholders from the alias segment's args.
+func (r *Registry) expand(segs []segment) ([]segment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.aliases) == 0 {
+		return segs, nil
+	}
+	return r.expandInto(nil, segs)
+}
+
+func (r *Registry) expandInto(out, segs []segment) ([]segment, error) {
+	for _, seg := range segs {
+		a, ok := r.aliases[seg.name]
+		if !ok {
+			out = append(out, seg)
+			continue
+		}
+		if err := a.checkArgs(seg); err != nil {
+			return nil, err
+		}
+		bound := make([]segment, 0, len(a.segs))
+		for _, s := range a.segs {
+			groups, ok := joinGroups(seg.groups, s.groups)
+			if !ok {
+				continue // in none of the groups the alias is used in
+			}
+			b := segment{groups: groups, name: s.name, args: make([]arg, len(s.args))}
+			for j, p := range s.args {
+				if p.param != "" {
+					v, ok := seg.lookup(p.param)
+					if !ok {
+						return nil, &ProcessError{
+							Stage:     StageParam,
+							Directive: seg.name,
+							Param:     p.param,
+							Cause:     &MissingParamError{Param: p.param},
+						}
+					}
+					p = arg{key: p.key, value: v}
+				}
+				b.args[j] = p
+			}
+			bound = append(bound, b)
+		}
+		var err error
+		if out, err = r.expandInto(out, bound); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// checkArgs returns a StageParam *ProcessError for the first arg of seg, a use
+// of a, that matches none of a's placeholders.
+func (a *alias) checkArgs(seg segment) error {
+	for _, u := range seg.args {
+		if !a.hasParam(u.key) {
+			return &ProcessError{
+				Stage:     StageParam,
+				Directive: seg.name,
+				Param:     u.key,
+				Cause:     &UnknownAliasParamError{Alias: seg.name, Param: u.key},
+			}
+		}
+	}
+	return nil
+}
+
+func (a *alias) hasParam(name string) bool {
+	for _, s := range a.segs {
+		for _, p := range s.args {
+			if p.param == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// joinGroups returns the groups of a segment in an alias chain whose own
+// groups are inner, where the alias is used with the groups outer: whichever
+// is set, or the groups both name. It returns false when they name none in
+// common, so the segment never applies.
+func joinGroups(outer, inner []string) ([]string, bool) {
+	if len(outer) == 0 {
+		return inner, true
+	}
+	if len(inner) == 0 {
+		return outer, true
+	}
+	var both []string
+	for _, g := range inner {
+		if inGroups(outer, g) {
+			both = append(both, g)
+		}
+	}
+	return both, len(both) > 0
+}
+
+// Alias registers an alias on the default registry. See Registry.Alias.
+func Alias(name, chain string) error {
+	return defaultRegistry.Alias(name, chain)
+}
+
+// MustAlias is like Alias but panics if registration fails.
+func MustAlias(name, chain string) {
+	defaultRegistry.MustAlias(name, chain)
+}
+
+// ExpandTag expands the aliases in tagValue against the default registry. See
+// Registry.ExpandTag.
+func ExpandTag(tagValue string) (string, error) {
+	return defaultRegistry.ExpandTag(tagValue)
+}
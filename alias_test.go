@@ -0,0 +1,201 @@
+package valex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+type lowerDirective struct{}
+
+func (*lowerDirective) Name() string                    { return "lower" }
+func (*lowerDirective) Mode() tagex.DirectiveMode       { return tagex.MutMode }
+func (*lowerDirective) Handle(s string) (string, error) { return strings.ToLower(s), nil }
+
+func aliasRegistry(t *testing.T) *valex.Registry {
+	t.Helper()
+	reg := chainRegistry(t)
+	valex.MustRegisterDirectiveTo(reg, &lowerDirective{})
+	valex.MustRegisterDirectiveTo(reg, &validators.EmailValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.MaxLengthValidator{})
+	return reg
+}
+
+type signup struct {
+	Email string `val:"useremail"`
+	Login string `val:"username,min=3,max=8"`
+}
+
+func TestAlias(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("useremail", "trim;lower;email;max,size=254")
+	reg.MustAlias("username", "trim;min,size=$min;max,size=$max")
+
+	in := &signup{Email: "  Gopher@Example.COM ", Login: " gopher "}
+	if err := reg.ValidateStruct(in); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+	if in.Email != "gopher@example.com" || in.Login != "gopher" {
+		t.Errorf("expected aliases to normalize, got %q and %q", in.Email, in.Login)
+	}
+
+	err := reg.ValidateStruct(&signup{Email: "a@b.com", Login: "gophergopher"})
+	var pe *valex.ProcessError
+	if !errors.As(err, &pe) || pe.FieldPath != "Login" || pe.Directive != "max" {
+		t.Errorf("expected max failure on Login, got %v", err)
+	}
+}
+
+func TestAliasMissingParam(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("username", "trim;min,size=$min")
+	type account struct {
+		Login string `val:"username"`
+	}
+	err := reg.ValidateStruct(&account{Login: "gopher"})
+	var missing *valex.MissingParamError
+	var pe *valex.ProcessError
+	if !errors.As(err, &missing) || !errors.As(err, &pe) || pe.Directive != "username" || pe.Param != "min" {
+		t.Errorf("expected missing min on username, got %v", err)
+	}
+}
+
+func TestAliasUnknownParam(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("username", "trim;min,size=$min")
+	type account struct {
+		Login string `val:"username,mn=3"`
+	}
+	err := reg.ValidateStruct(&account{Login: "gopher"})
+	var unknown *valex.UnknownAliasParamError
+	var pe *valex.ProcessError
+	if !errors.As(err, &unknown) || !errors.As(err, &pe) || pe.Directive != "username" || pe.Param != "mn" {
+		t.Errorf("expected unknown param mn on username, got %v", err)
+	}
+}
+
+func TestAliasNested(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("short", "max,size=$n")
+	reg.MustAlias("code", "trim;short,n=$len")
+	type item struct {
+		SKU string `val:"code,len=4;lower"`
+	}
+	in := &item{SKU: " AB12 "}
+	if err := reg.ValidateStruct(in); err != nil || in.SKU != "ab12" {
+		t.Errorf("expected valid ab12, got %q (err: %v)", in.SKU, err)
+	}
+	if err := reg.ValidateStruct(&item{SKU: "ABC123"}); err == nil {
+		t.Error("expected nested alias bound to n=4 to fail")
+	}
+}
+
+func TestAliasErrors(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("a", "trim;b")
+	reg.MustAlias("b", "c,size=1")
+
+	var cycle *valex.AliasCycleError
+	if err := reg.Alias("c", "a"); !errors.As(err, &cycle) || strings.Join(cycle.Cycle, ">") != "c>a>b>c" {
+		t.Errorf("expected cycle c>a>b>c, got %v", err)
+	}
+	if err := reg.Alias("self", "trim;self"); !errors.As(err, &cycle) {
+		t.Errorf("expected self-cycle, got %v", err)
+	}
+
+	var dup *valex.DuplicateDirectiveError
+	if err := reg.Alias("trim", "lower"); !errors.As(err, &dup) {
+		t.Errorf("expected alias clashing with a directive to fail, got %v", err)
+	}
+	if err := reg.Alias("a", "lower"); !errors.As(err, &dup) {
+		t.Errorf("expected duplicate alias to fail, got %v", err)
+	}
+	reg.MustAlias("upper", "lower")
+	if err := valex.RegisterDirectiveTo(reg, &namedDirective{name: "upper"}); !errors.As(err, &dup) {
+		t.Errorf("expected directive clashing with an alias to fail, got %v", err)
+	}
+
+	var empty *valex.EmptyDirectiveNameError
+	if err := reg.Alias(" ", "trim"); !errors.As(err, &empty) {
+		t.Errorf("expected blank alias name to fail, got %v", err)
+	}
+	var parseErr *valex.ParamParseError
+	if err := reg.Alias("bad", "min,size="); !errors.As(err, &parseErr) {
+		t.Errorf("expected malformed chain to fail, got %v", err)
+	}
+	var nameErr *valex.DirectiveParseError
+	if err := reg.Alias("blank", " ; "); !errors.As(err, &nameErr) {
+		t.Errorf("expected empty chain to fail, got %v", err)
+	}
+}
+
+type namedDirective struct{ name string }
+
+func (d *namedDirective) Name() string                  { return d.name }
+func (*namedDirective) Mode() tagex.DirectiveMode       { return tagex.EvalMode }
+func (*namedDirective) Handle(s string) (string, error) { return s, nil }
+
+func TestExpandTag(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("username", "trim; min, size=$min ;max,size=20")
+	reg.MustAlias("dollar", "oneof,values='$min'")
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"username,min=3", "trim;min,size=3;max,size=20"},
+		{"username,min='a,b';email", "trim;min,size='a,b';max,size=20;email"},
+		{"dollar", "oneof,values='$min'"},
+		{"lower", "lower"},
+	}
+	for _, tt := range tests {
+		got, err := reg.ExpandTag(tt.tag)
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandTag(%q) = %q, %v; want %q", tt.tag, got, err, tt.want)
+		}
+	}
+	if _, err := reg.ExpandTag("username"); err == nil {
+		t.Error("expected ExpandTag to report the unbound $min")
+	}
+	if got := reg.Aliases(); len(got) != 2 || got["dollar"] != "oneof,values='$min'" {
+		t.Errorf("unexpected Aliases(): %v", got)
+	}
+}
+
+// A tag passed alongside "val" still runs in the same call, with its field
+// paths unchanged and the data's hooks run once.
+type hooked struct {
+	Name   string `val:"min,size=3" check:"min,size=1"`
+	Nested struct {
+		Code string `check:"min,size=2"`
+	}
+	before int
+}
+
+func (h *hooked) Before() error { h.before++; return nil }
+
+func TestValidateStructExtraTags(t *testing.T) {
+	check := tagex.NewTag("check")
+	tagex.MustRegisterDirective(check, &validators.MinLengthValidator{})
+
+	in := &hooked{Name: "gopher"}
+	err := chainRegistry(t).ValidateStructAll(in, check)
+	fields := valex.FieldErrors(err)
+	if len(fields) != 1 || fields["Nested.Code"] == nil {
+		t.Errorf("expected one failure on Nested.Code, got %v", err)
+	}
+	if in.before != 1 {
+		t.Errorf("expected Before to run once, ran %d times", in.before)
+	}
+
+	in = &hooked{Name: "go"}
+	in.Nested.Code = "ok"
+	if err := chainRegistry(t).ValidateStruct(in, check); err == nil {
+		t.Error("expected the val tag to run after the extra tag")
+	}
+}
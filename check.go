@@ -0,0 +1,54 @@
+package valex
+
+import "reflect"
+
+// TagDirective is one directive a "val" tag runs, as resolved by CheckTag.
+type TagDirective struct {
+	Name   string            // directive name, after alias expansion
+	Args   map[string]string // parameters as written in the tag
+	Type   reflect.Type      // field type the directive applies to; nil for omitempty
+	Groups []string          // groups the segment runs in; nil if it always runs
+}
+
+// CheckTag resolves tagValue against r without a value to validate, for
+// tooling such as linters that check tags before the code runs. It parses the
+// chain, expands aliases, looks each directive up, and converts its
+// parameters, returning the first error a field carrying the tag would report
+// for any value: a malformed chain, an unknown directive, or a parameter that
+// is missing or does not convert, with the same error types ValidateStruct
+// returns. On success it returns the directives in the order they run, so the
+// caller can compare each Type with the field's. Segments of every group are
+// included; an omitempty keyword is included with a nil Type.
+func (r *Registry) CheckTag(tagValue string) ([]TagDirective, error) {
+	segs, err := parseChain(tagValue)
+	if err != nil {
+		return nil, chainError(err)
+	}
+	segs, err = r.expand(segs)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TagDirective, len(segs))
+	for i, seg := range segs {
+		if seg.name == omitEmpty {
+			out[i] = TagDirective{Name: seg.name, Args: seg.plainArgs(), Groups: seg.groups}
+			continue
+		}
+		d, err := r.directive(seg.name)
+		if err != nil {
+			return nil, err
+		}
+		args := seg.plainArgs()
+		if err := d.params(args); err != nil {
+			return nil, err
+		}
+		out[i] = TagDirective{Name: seg.name, Args: args, Type: d.typ, Groups: seg.groups}
+	}
+	return out, nil
+}
+
+// CheckTag checks tagValue against the default registry. See
+// Registry.CheckTag.
+func CheckTag(tagValue string) ([]TagDirective, error) {
+	return defaultRegistry.CheckTag(tagValue)
+}
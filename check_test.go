@@ -0,0 +1,51 @@
+package valex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestCheckTag(t *testing.T) {
+	reg := aliasRegistry(t)
+	reg.MustAlias("username", "trim;min,size=$min")
+
+	ds, err := reg.CheckTag("username,min=3;max,size=8")
+	if err != nil {
+		t.Fatalf("CheckTag: %v", err)
+	}
+	var names []string
+	for _, d := range ds {
+		names = append(names, d.Name)
+		if d.Type != reflect.TypeFor[string]() {
+			t.Errorf("%s: expected type string, got %v", d.Name, d.Type)
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"trim", "min", "max"}) {
+		t.Errorf("expected trim, min, max, got %q", names)
+	}
+	if ds[1].Args["size"] != "3" {
+		t.Errorf("expected the bound size=3, got %v", ds[1].Args)
+	}
+
+	tests := []struct {
+		tag   string
+		stage valex.Stage
+		cause any
+	}{
+		{"min,size", valex.StageParam, new(*valex.ParamParseError)},
+		{"nope", valex.StageDirective, new(*valex.UnknownDirectiveError)},
+		{"min", valex.StageParam, new(*valex.MissingParamError)},
+		{"min,size=big", valex.StageParam, new(*valex.ConversionError)},
+		{"username", valex.StageParam, new(*valex.MissingParamError)},
+	}
+	for _, tt := range tests {
+		_, err := reg.CheckTag(tt.tag)
+		var pe *valex.ProcessError
+		if !errors.As(err, &pe) || pe.Stage != tt.stage || !errors.As(err, tt.cause) {
+			t.Errorf("CheckTag(%q): expected a %v %T, got %v", tt.tag, tt.stage, tt.cause, err)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/internal/catalog"
+	"github.com/tedla-brandsema/valex/internal/load"
+)
+
+const validatorsPath = "github.com/tedla-brandsema/valex/validators"
+
+// generator writes the validation code for the struct types of one package.
+type generator struct {
+	pkg     *load.Package
+	reg     *valex.Registry
+	reaches map[types.Type]bool // memo for reachesTags
+	targets map[*types.Named]bool
+	imports map[string]bool
+	vars    bytes.Buffer // directive variable declarations
+	funcs   bytes.Buffer // methods
+}
+
+// generate returns the formatted source of a file declaring Validate and
+// ValidateAll for typeNames, or for every struct type in pkg whose values can
+// hold a "val" tag when typeNames is empty.
+func generate(pkg *load.Package, typeNames []string) ([]byte, error) {
+	if pkg.Types == nil {
+		return nil, fmt.Errorf("%s: package did not type-check", pkg.Path)
+	}
+	g := &generator{
+		pkg:     pkg,
+		reg:     catalog.Registry(),
+		reaches: make(map[types.Type]bool),
+		targets: make(map[*types.Named]bool),
+		imports: map[string]bool{"github.com/tedla-brandsema/valex": true},
+	}
+	named, err := g.selectTypes(typeNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range named {
+		g.targets[n] = true
+	}
+	for _, n := range named {
+		if err := g.genStruct(n); err != nil {
+			return nil, err
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by valexgen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg.Name)
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, path := range std {
+		fmt.Fprintf(&b, "\t%q\n", path)
+	}
+	if len(std) > 0 {
+		b.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(&b, "\t%q\n", path)
+	}
+	b.WriteString(")\n\n")
+	if g.vars.Len() > 0 {
+		fmt.Fprintf(&b, "var (\n%s)\n\n", g.vars.Bytes())
+	}
+	b.Write(g.funcs.Bytes())
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+// selectTypes resolves typeNames to struct types, in order, or finds every
+// struct type that can hold a tag, sorted by name. Same-package struct types
+// reached from a selected type must be selected too, since their methods are
+// generated with it.
+func (g *generator) selectTypes(typeNames []string) ([]*types.Named, error) {
+	scope := g.pkg.Types.Scope()
+	var named []*types.Named
+	if len(typeNames) == 0 {
+		for _, name := range scope.Names() {
+			if n, ok := g.structType(scope.Lookup(name)); ok && g.reachesTags(n) {
+				named = append(named, n)
+			}
+		}
+		if len(named) == 0 {
+			return nil, fmt.Errorf("%s: no struct types with val tags", g.pkg.Path)
+		}
+		return named, nil
+	}
+	selected := make(map[*types.Named]bool)
+	for _, name := range typeNames {
+		obj := scope.Lookup(name)
+		if obj == nil {
+			return nil, fmt.Errorf("%s: no type %s", g.pkg.Path, name)
+		}
+		n, ok := g.structType(obj)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is not a non-generic struct type", g.pkg.Path, name)
+		}
+		named = append(named, n)
+		selected[n] = true
+	}
+	for _, n := range named {
+		st := n.Underlying().(*types.Struct)
+		for i := 0; i < st.NumFields(); i++ {
+			if f := st.Field(i); f.Exported() {
+				if err := g.checkReached(n, f, f.Type(), selected); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return named, nil
+}
+
+// checkReached reports a struct type reachable from field f of n that can hold
+// a tag but is not selected.
+func (g *generator) checkReached(n *types.Named, f *types.Var, t types.Type, selected map[*types.Named]bool) error {
+	if !g.reachesTags(t) {
+		return nil
+	}
+	switch u := types.Unalias(t).(type) {
+	case *types.Named:
+		if _, ok := u.Underlying().(*types.Struct); ok {
+			if u.Obj().Pkg() == g.pkg.Types && !selected[u] {
+				return fmt.Errorf("%s: type %s, reached from %s.%s, has val tags; add it to -type", g.position(f), u.Obj().Name(), n.Obj().Name(), f.Name())
+			}
+			return nil
+		}
+		return g.checkReached(n, f, u.Underlying(), selected)
+	case *types.Pointer:
+		return g.checkReached(n, f, u.Elem(), selected)
+	case *types.Slice:
+		return g.checkReached(n, f, u.Elem(), selected)
+	case *types.Array:
+		return g.checkReached(n, f, u.Elem(), selected)
+	case *types.Map:
+		return g.checkReached(n, f, u.Elem(), selected)
+	}
+	return nil
+}
+
+// structType reports whether obj names a non-generic struct type of the
+// package.
+func (g *generator) structType(obj types.Object) (*types.Named, bool) {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || tn.IsAlias() {
+		return nil, false
+	}
+	n, ok := tn.Type().(*types.Named)
+	if !ok || n.TypeParams().Len() > 0 {
+		return nil, false
+	}
+	_, ok = n.Underlying().(*types.Struct)
+	return n, ok
+}
+
+// reachesTags reports whether a value of type t can hold an exported field
+// with a "val" tag that ValidateStruct would reach. Values that cannot are
+// skipped by the generated code.
+func (g *generator) reachesTags(t types.Type) bool {
+	t = types.Unalias(t)
+	if r, ok := g.reaches[t]; ok {
+		return r
+	}
+	g.reaches[t] = false // a cycle adds nothing
+	var r bool
+	switch u := t.(type) {
+	case *types.Named:
+		r = g.reachesTags(u.Underlying())
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			f := u.Field(i)
+			if !f.Exported() {
+				continue
+			}
+			if _, ok := reflect.StructTag(u.Tag(i)).Lookup("val"); ok || g.reachesTags(f.Type()) {
+				r = true
+				break
+			}
+		}
+	case *types.Pointer:
+		r = g.reachesTags(u.Elem())
+	case *types.Slice:
+		r = g.reachesTags(u.Elem())
+	case *types.Array:
+		r = g.reachesTags(u.Elem())
+	case *types.Map:
+		r = g.reachesTags(u.Elem())
+	}
+	g.reaches[t] = r
+	return r
+}
+
+// genStruct writes n's Validate, ValidateAll, and valexFields methods, and a
+// valexField<i> method running the chain of each tagged field, i being the
+// field's index.
+func (g *generator) genStruct(n *types.Named) error {
+	name := n.Obj().Name()
+	recv := receiverName(name)
+	st := n.Underlying().(*types.Struct)
+	if err := g.checkNames(n, st); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	var chains bytes.Buffer
+	vars := 0
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		if tagValue, ok := reflect.StructTag(st.Tag(i)).Lookup("val"); ok {
+			if err := g.genChain(&chains, n, recv, i, f, tagValue, &vars); err != nil {
+				return err
+			}
+			fmt.Fprintf(&body, "\tif directive, err := %s.%s(); err != nil {\n", recv, chainMethod(i))
+			fmt.Fprintf(&body, "\t\tif err := valex.DirectiveFailure(errs, prefix+%q, directive, err); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", f.Name())
+		}
+		if err := g.genValue(&body, f, f.Type(), recv+"."+f.Name(), fmt.Sprintf("prefix+%q", f.Name()), 1, 0); err != nil {
+			return err
+		}
+	}
+
+	w := &g.funcs
+	fmt.Fprintf(w, "// Validate validates %s as valex.ValidateStruct does, without reflection.\n", recv)
+	fmt.Fprintf(w, "func (%s *%s) Validate() error {\n\treturn valex.RunGenerated(%s, false, %s.valexFields)\n}\n\n", recv, name, recv, recv)
+	fmt.Fprintf(w, "// ValidateAll validates %s as valex.ValidateStructAll does, without reflection.\n", recv)
+	fmt.Fprintf(w, "func (%s *%s) ValidateAll() error {\n\treturn valex.RunGenerated(%s, true, %s.valexFields)\n}\n\n", recv, name, recv, recv)
+	fmt.Fprintf(w, "func (%s *%s) valexFields(prefix string, depth int, errs *[]error) error {\n", recv, name)
+	w.Write(body.Bytes())
+	w.WriteString("\treturn nil\n}\n\n")
+	w.Write(chains.Bytes())
+	return nil
+}
+
+// chainMethod names the method running the chain of the field at index i.
+// Field indexes keep it apart from the field names and from valexFields.
+func chainMethod(i int) string {
+	return fmt.Sprintf("valexField%d", i)
+}
+
+// checkNames rejects a field or method of n that has the name of a method
+// genStruct declares, since the generated file would not compile. Methods in
+// a file valexgen generated earlier are the ones being replaced.
+func (g *generator) checkNames(n *types.Named, st *types.Struct) error {
+	generated := map[string]bool{"Validate": true, "ValidateAll": true, "valexFields": true}
+	for i := 0; i < st.NumFields(); i++ {
+		if _, ok := reflect.StructTag(st.Tag(i)).Lookup("val"); ok && st.Field(i).Exported() {
+			generated[chainMethod(i)] = true
+		}
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if f := st.Field(i); generated[f.Name()] {
+			return fmt.Errorf("%s: field %s of %s has the name of a method valexgen generates; rename the field", g.position(f), f.Name(), n.Obj().Name())
+		}
+	}
+	for i := 0; i < n.NumMethods(); i++ {
+		if m := n.Method(i); generated[m.Name()] && !g.fromValexgen(m.Pos()) {
+			return fmt.Errorf("%s: method %s of %s has the name of a method valexgen generates", g.pkg.Fset.Position(m.Pos()), m.Name(), n.Obj().Name())
+		}
+	}
+	return nil
+}
+
+// fromValexgen reports whether pos is in a file valexgen generated.
+func (g *generator) fromValexgen(pos token.Pos) bool {
+	tf := g.pkg.Fset.File(pos)
+	for _, f := range g.pkg.Files {
+		if g.pkg.Fset.File(f.Pos()) != tf || len(f.Comments) == 0 {
+			continue
+		}
+		return strings.HasPrefix(f.Comments[0].Text(), "Code generated by valexgen;")
+	}
+	return false
+}
+
+// genChain writes the method running field f's "val" chain, which returns
+// the name of the directive that failed with its error.
+func (g *generator) genChain(w *bytes.Buffer, n *types.Named, recv string, i int, f *types.Var, tagValue string, vars *int) error {
+	directives, err := g.reg.CheckTag(tagValue)
+	if err != nil {
+		return fmt.Errorf("%s: val tag %q: %v", g.position(f), tagValue, err)
+	}
+	field := recv + "." + f.Name()
+	fmt.Fprintf(w, "func (%s *%s) %s() (string, error) {\n", recv, n.Obj().Name(), chainMethod(i))
+	declared := false
+	for _, d := range directives {
+		// Generated methods take no options, so they cannot select groups.
+		if len(d.Groups) > 0 {
+			return fmt.Errorf("%s: val tag %q: group-qualified segments are not generated", g.position(f), tagValue)
+		}
+		if d.Type == nil {
+			return fmt.Errorf("%s: val tag %q: %s is not generated", g.position(f), tagValue, d.Name)
+		}
+		if same, ok := catalog.SameType(f.Type(), d.Type); !ok || !same {
+			return fmt.Errorf("%s: val tag %q: directive %q applies to %s, not %s", g.position(f), tagValue, d.Name, d.Type, types.TypeString(f.Type(), g.qualifier))
+		}
+		proto, _ := catalog.Directive(d.Name)
+		rt := reflect.TypeOf(proto).Elem()
+		if rt.PkgPath() != validatorsPath {
+			return fmt.Errorf("%s: directive %q is not generated", g.position(f), d.Name)
+		}
+		// Parameters decide the mode of some directives, such as phone.
+		dup := reflect.New(rt).Interface()
+		if err := tagex.ProcessParams(dup, d.Args); err != nil {
+			return fmt.Errorf("%s: val tag %q: %v", g.position(f), tagValue, err)
+		}
+		mode := dup.(interface{ Mode() tagex.DirectiveMode }).Mode()
+
+		v := fmt.Sprintf("valex%s%d", n.Obj().Name(), *vars)
+		*vars++
+		g.imports[validatorsPath] = true
+		fmt.Fprintf(&g.vars, "\t%s = valex.MustParams(&validators.%s{}, %s)\n", v, rt.Name(), mapLiteral(d.Args))
+
+		switch {
+		case mode == tagex.MutMode:
+			op := ":="
+			if declared {
+				op = "="
+			}
+			declared = true
+			fmt.Fprintf(w, "\tval, err %s %s.Handle(%s)\n\tif err != nil {\n\t\treturn %q, err\n\t}\n\t%s = val\n", op, v, field, d.Name, field)
+		case hasValidate(reflect.TypeOf(proto)):
+			fmt.Fprintf(w, "\tif err := %s.Validate(%s); err != nil {\n\t\treturn %q, err\n\t}\n", v, field, d.Name)
+		default:
+			fmt.Fprintf(w, "\tif _, err := %s.Handle(%s); err != nil {\n\t\treturn %q, err\n\t}\n", v, field, d.Name)
+		}
+	}
+	w.WriteString("\treturn \"\", nil\n}\n\n")
+	return nil
+}
+
+// genValue writes the descent into x, of type t at path, mirroring how
+// ValidateStruct walks pointers, slices, arrays, and maps to nested structs.
+// depth is the offset added to the enclosing struct's depth, and level numbers
+// the loop variables.
+func (g *generator) genValue(w *bytes.Buffer, f *types.Var, t types.Type, x, path string, depth, level int) error {
+	if !g.reachesTags(t) {
+		return nil
+	}
+	fmt.Fprintf(w, "\tif err := valex.CheckDepth(%s, depth+%d); err != nil {\n\t\treturn err\n\t}\n", path, depth)
+	switch u := types.Unalias(t).(type) {
+	case *types.Named:
+		if _, ok := u.Underlying().(*types.Struct); !ok {
+			return g.genValue(w, f, u.Underlying(), x, path, depth, level)
+		}
+		if !g.targets[u] {
+			return fmt.Errorf("%s: field %s reaches %s, whose val tags valexgen cannot generate from this package", g.position(f), f.Name(), types.TypeString(u, g.qualifier))
+		}
+		fmt.Fprintf(w, "\tif err := %s.valexFields(%s, depth+%d, errs); err != nil {\n\t\treturn err\n\t}\n", x, concat(path, `"."`), depth)
+	case *types.Struct:
+		return fmt.Errorf("%s: field %s has an anonymous struct type with val tags, which valexgen does not support", g.position(f), f.Name())
+	case *types.Pointer:
+		fmt.Fprintf(w, "\tif %s != nil {\n", x)
+		elem := "(*" + x + ")"
+		if n, ok := types.Unalias(u.Elem()).(*types.Named); ok {
+			if _, ok := n.Underlying().(*types.Struct); ok {
+				elem = x // the method has a pointer receiver
+			}
+		}
+		if err := g.genValue(w, f, u.Elem(), elem, path, depth+1, level); err != nil {
+			return err
+		}
+		w.WriteString("\t}\n")
+	case *types.Slice, *types.Array:
+		elem := u.(interface{ Elem() types.Type }).Elem()
+		i, p := fmt.Sprintf("i%d", level), fmt.Sprintf("p%d", level)
+		g.imports["strconv"] = true
+		fmt.Fprintf(w, "\tfor %s := range %s {\n", i, x)
+		fmt.Fprintf(w, "\t%s := %s+strconv.Itoa(%s)+\"]\"\n", p, concat(path, `"["`), i)
+		if err := g.genValue(w, f, elem, fmt.Sprintf("%s[%s]", x, i), p, depth+1, level+1); err != nil {
+			return err
+		}
+		w.WriteString("\t}\n")
+	case *types.Map:
+		k, v, p := fmt.Sprintf("k%d", level), fmt.Sprintf("v%d", level), fmt.Sprintf("p%d", level)
+		fmt.Fprintf(w, "\tfor %s, %s := range %s {\n", k, v, x)
+		fmt.Fprintf(w, "\t%s := %s+%s+\"]\"\n", p, concat(path, `"["`), g.keyString(u.Key(), k))
+		if err := g.genValue(w, f, u.Elem(), v, p, depth+1, level+1); err != nil {
+			return err
+		}
+		// Map values are copies; store them back as ValidateStruct does.
+		fmt.Fprintf(w, "\t%s[%s] = %s\n\t}\n", x, k, v)
+	}
+	return nil
+}
+
+// concat returns the Go expression a+b, merging a string literal ending a
+// with one starting b.
+func concat(a, b string) string {
+	if strings.HasSuffix(a, `"`) && strings.HasPrefix(b, `"`) {
+		return a[:len(a)-1] + b[1:]
+	}
+	return a + "+" + b
+}
+
+// keyString returns an expression formatting map key k as %v does.
+func (g *generator) keyString(t types.Type, k string) string {
+	if b, ok := types.Unalias(t).(*types.Basic); ok {
+		switch b.Kind() {
+		case types.String:
+			return k
+		case types.Int:
+			g.imports["strconv"] = true
+			return "strconv.Itoa(" + k + ")"
+		}
+	}
+	g.imports["fmt"] = true
+	return "fmt.Sprint(" + k + ")"
+}
+
+func (g *generator) position(f *types.Var) token.Position {
+	return g.pkg.Fset.Position(f.Pos())
+}
+
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg.Types {
+		return ""
+	}
+	return p.Name()
+}
+
+// hasValidate reports whether directive type t has a Validate(T) error method
+// to call instead of Handle.
+func hasValidate(t reflect.Type) bool {
+	m, ok := t.MethodByName("Validate")
+	return ok && m.Type.NumIn() == 2 && m.Type.NumOut() == 1 && m.Type.Out(0) == reflect.TypeFor[error]()
+}
+
+// receiverName returns the lower-cased first letter of a type name.
+func receiverName(typeName string) string {
+	r, _ := utf8.DecodeRuneInString(typeName)
+	return string(unicode.ToLower(r))
+}
+
+// mapLiteral returns args as a Go map literal with sorted keys, or nil.
+func mapLiteral(args map[string]string) string {
+	if len(args) == 0 {
+		return "nil"
+	}
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = strconv.Quote(k) + ": " + strconv.Quote(args[k])
+	}
+	return "map[string]string{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/valex/internal/load"
+)
+
+func loadOne(t *testing.T, pattern string) *load.Package {
+	t.Helper()
+	pkgs, err := load.Packages([]string{pattern})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("%s: loaded %d packages", pattern, len(pkgs))
+	}
+	return pkgs[0]
+}
+
+// TestGolden regenerates internal/sample, whose tests compare the generated
+// methods with the registry, and fails when the checked-in file is stale.
+func TestGolden(t *testing.T) {
+	pkg := loadOne(t, "./internal/sample")
+	got, err := generate(pkg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(pkg.Dir, "sample_valex.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("internal/sample/sample_valex.go is stale; run go generate ./cmd/valexgen/...")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	pkg := loadOne(t, "./testdata/src/bad")
+	tests := []struct {
+		types []string
+		want  string
+	}{
+		{[]string{"Custom"}, `unknown directive "even"`},
+		{[]string{"Mismatch"}, `directive "rangeint" applies to int, not string`},
+		{[]string{"BadParam"}, `val tag "rangeint,min=low,max=2"`},
+		{[]string{"Anonymous"}, "anonymous struct type"},
+		{[]string{"Outer"}, "type Inner, reached from Outer.Inner, has val tags; add it to -type"},
+		{[]string{"Foreign"}, "reaches sample.Address"},
+		{[]string{"Grouped"}, "group-qualified segments are not generated"},
+		{[]string{"OmitEmpty"}, "omitempty is not generated"},
+		{[]string{"FieldClash"}, "field Validate of FieldClash has the name of a method valexgen generates"},
+		{[]string{"MethodClash"}, "method ValidateAll of MethodClash has the name of a method valexgen generates"},
+		{[]string{"Missing"}, "no type Missing"},
+	}
+	for _, tt := range tests {
+		_, err := generate(pkg, tt.types)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected an error containing %q, got %v", tt.types, tt.want, err)
+		}
+	}
+	if _, err := generate(pkg, []string{"Outer", "Inner"}); err != nil {
+		t.Errorf("Outer, Inner: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package sample holds the types valexgen's tests generate code for. The
+// generated sample_valex.go is checked in; TestGolden fails when it is stale,
+// and the package's own tests check it against valex.ValidateStructAll.
+package sample
+
+import "errors"
+
+//go:generate go run github.com/tedla-brandsema/valex/cmd/valexgen
+
+// Signup covers chains, a normalizing directive, nesting through every kind
+// ValidateStruct descends into, and lifecycle hooks.
+type Signup struct {
+	Name     string            `val:"!empty;min,size=3"`
+	Email    string            `val:"email"`
+	Age      int               `val:"rangeint,min=18,max=130"`
+	Phone    string            `val:"phone,region=US,normalize=true"`
+	Plan     string            `val:"oneof,values=free|pro"`
+	Address  Address           // Address.Street
+	Billing  *Address          // descended when set
+	Items    []Item            // Items[i]
+	Backups  [2]*Address       // Backups[i], nil skipped
+	Labels   map[string]Item   // Labels[key], stored back
+	Scores   map[int][]Item    // Scores[key][i]
+	Notes    []string          // no tags to reach
+	Parent   *Node             // cyclic
+	internal Address           // unexported, skipped
+	Rejected bool              `json:"-"`
+	Meta     map[string]string `json:"meta"`
+}
+
+// Before rejects a signup marked as rejected.
+func (s *Signup) Before() error {
+	if s.Rejected {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+// Address is a nested struct type.
+type Address struct {
+	Street string `val:"!empty"`
+	Zip    string `val:"regex,pattern=^[0-9]{5}$"`
+}
+
+// Item is an element type.
+type Item struct {
+	SKU string `val:"len,min=4,max=8"`
+	Qty int    `val:"minint,min=1"`
+}
+
+// Node is a linked list, for the depth limit.
+type Node struct {
+	ID   string `val:"uuid"`
+	Next *Node
+}
+
+// Form has fields named like the methods valexgen declares for their chains
+// and for the walker.
+type Form struct {
+	Fields      string `val:"!empty"`
+	ValexField0 string `val:"min,size=2"`
+}
@@ -0,0 +1,144 @@
+package sample_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/cmd/valexgen/internal/sample"
+	"github.com/tedla-brandsema/valex/internal/catalog"
+)
+
+func valid() sample.Signup {
+	return sample.Signup{
+		Name:    "Ada",
+		Email:   "ada@example.com",
+		Age:     36,
+		Phone:   "(415) 555-2671",
+		Plan:    "pro",
+		Address: sample.Address{Street: "Main St", Zip: "94105"},
+	}
+}
+
+func chain(n int) *sample.Node {
+	var head *sample.Node
+	for i := 0; i < n; i++ {
+		head = &sample.Node{ID: "123e4567-e89b-42d3-a456-426614174000", Next: head}
+	}
+	return head
+}
+
+// TestGeneratedMatchesReflection runs the generated methods and the registry
+// on copies of the same value and compares the errors and the results.
+func TestGeneratedMatchesReflection(t *testing.T) {
+	reg := catalog.Registry()
+	tests := []struct {
+		name   string
+		modify func(s *sample.Signup)
+		ok     bool
+	}{
+		{"valid", func(s *sample.Signup) {}, true},
+		{"fields", func(s *sample.Signup) { s.Name, s.Age, s.Plan = "", 7, "gold" }, false},
+		{"phone", func(s *sample.Signup) { s.Phone = "12" }, false},
+		{"nested", func(s *sample.Signup) {
+			s.Address.Zip = "9410"
+			s.Billing = &sample.Address{}
+		}, false},
+		{"elements", func(s *sample.Signup) {
+			s.Items = []sample.Item{{SKU: "ABCD", Qty: 1}, {SKU: "AB", Qty: 0}}
+			s.Backups = [2]*sample.Address{nil, {Street: "Side St"}}
+		}, false},
+		{"maps", func(s *sample.Signup) {
+			s.Labels = map[string]sample.Item{"a": {SKU: "ABCD", Qty: 0}}
+			s.Scores = map[int][]sample.Item{3: {{SKU: "ABCDE", Qty: 2}, {SKU: "X", Qty: 2}}}
+		}, false},
+		{"valid nesting", func(s *sample.Signup) {
+			s.Items = []sample.Item{{SKU: "ABCD", Qty: 1}}
+			s.Labels = map[string]sample.Item{"a": {SKU: "ABCD", Qty: 1}}
+			s.Parent = chain(3)
+		}, true},
+		{"depth", func(s *sample.Signup) { s.Parent = chain(600) }, false},
+		{"hook", func(s *sample.Signup) { s.Rejected = true }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, all := range []bool{false, true} {
+				gen, refl := valid(), valid()
+				tt.modify(&gen)
+				tt.modify(&refl)
+
+				var genErr, reflErr error
+				if all {
+					genErr, reflErr = gen.ValidateAll(), reg.ValidateStructAll(&refl)
+				} else {
+					genErr, reflErr = gen.Validate(), reg.ValidateStruct(&refl)
+				}
+				if (genErr == nil) != tt.ok {
+					t.Errorf("all=%v: expected ok=%v, got err: %v", all, tt.ok, genErr)
+				}
+				if g, r := describe(genErr), describe(reflErr); !reflect.DeepEqual(g, r) {
+					t.Errorf("all=%v: errors differ\ngenerated:  %q\nreflective: %q", all, g, r)
+				}
+				if tt.name != "depth" && !reflect.DeepEqual(gen, refl) {
+					t.Errorf("all=%v: results differ\ngenerated:  %+v\nreflective: %+v", all, gen, refl)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratedNormalizes(t *testing.T) {
+	s := valid()
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Phone != "+14155552671" {
+		t.Errorf("Phone = %q, want the normalized number", s.Phone)
+	}
+}
+
+func TestGeneratedFieldErrors(t *testing.T) {
+	s := valid()
+	s.Items = []sample.Item{{SKU: "ABCD", Qty: 0}}
+	fields := valex.FieldErrors(s.ValidateAll())
+	var pe *valex.ProcessError
+	if err := fields["Items[0].Qty"]; !errors.As(err, &pe) || pe.Directive != "minint" {
+		t.Errorf("FieldErrors = %v", fields)
+	}
+}
+
+// TestGeneratedFieldNames covers fields named like the generated methods.
+func TestGeneratedFieldNames(t *testing.T) {
+	gen, refl := sample.Form{ValexField0: "x"}, sample.Form{ValexField0: "x"}
+	genErr, reflErr := gen.ValidateAll(), catalog.Registry().ValidateStructAll(&refl)
+	if g, r := describe(genErr), describe(reflErr); len(g) != 2 || !reflect.DeepEqual(g, r) {
+		t.Errorf("errors differ\ngenerated:  %q\nreflective: %q", g, r)
+	}
+}
+
+// describe lists each joined error with the types it wraps, sorted, since
+// map order varies between runs.
+func describe(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var errs []error
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = j.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+	var out []string
+	for _, e := range errs {
+		s := e.Error()
+		for u := e; u != nil; u = errors.Unwrap(u) {
+			s += fmt.Sprintf(" %T", u)
+		}
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,345 @@
+// Code generated by valexgen; DO NOT EDIT.
+
+package sample
+
+import (
+	"strconv"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+var (
+	valexAddress0 = valex.MustParams(&validators.NonEmptyStringValidator{}, nil)
+	valexAddress1 = valex.MustParams(&validators.RegexValidator{}, map[string]string{"pattern": "^[0-9]{5}$"})
+	valexForm0    = valex.MustParams(&validators.NonEmptyStringValidator{}, nil)
+	valexForm1    = valex.MustParams(&validators.MinLengthValidator{}, map[string]string{"size": "2"})
+	valexItem0    = valex.MustParams(&validators.LengthRangeValidator{}, map[string]string{"max": "8", "min": "4"})
+	valexItem1    = valex.MustParams(&validators.MinIntValidator{}, map[string]string{"min": "1"})
+	valexNode0    = valex.MustParams(&validators.UUIDValidator{}, nil)
+	valexSignup0  = valex.MustParams(&validators.NonEmptyStringValidator{}, nil)
+	valexSignup1  = valex.MustParams(&validators.MinLengthValidator{}, map[string]string{"size": "3"})
+	valexSignup2  = valex.MustParams(&validators.EmailValidator{}, nil)
+	valexSignup3  = valex.MustParams(&validators.IntRangeValidator{}, map[string]string{"max": "130", "min": "18"})
+	valexSignup4  = valex.MustParams(&validators.PhoneValidator{}, map[string]string{"normalize": "true", "region": "US"})
+	valexSignup5  = valex.MustParams(&validators.OneOfStringValidator{}, map[string]string{"values": "free|pro"})
+)
+
+// Validate validates a as valex.ValidateStruct does, without reflection.
+func (a *Address) Validate() error {
+	return valex.RunGenerated(a, false, a.valexFields)
+}
+
+// ValidateAll validates a as valex.ValidateStructAll does, without reflection.
+func (a *Address) ValidateAll() error {
+	return valex.RunGenerated(a, true, a.valexFields)
+}
+
+func (a *Address) valexFields(prefix string, depth int, errs *[]error) error {
+	if directive, err := a.valexField0(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Street", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := a.valexField1(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Zip", directive, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Address) valexField0() (string, error) {
+	if err := valexAddress0.Validate(a.Street); err != nil {
+		return "!empty", err
+	}
+	return "", nil
+}
+
+func (a *Address) valexField1() (string, error) {
+	if err := valexAddress1.Validate(a.Zip); err != nil {
+		return "regex", err
+	}
+	return "", nil
+}
+
+// Validate validates f as valex.ValidateStruct does, without reflection.
+func (f *Form) Validate() error {
+	return valex.RunGenerated(f, false, f.valexFields)
+}
+
+// ValidateAll validates f as valex.ValidateStructAll does, without reflection.
+func (f *Form) ValidateAll() error {
+	return valex.RunGenerated(f, true, f.valexFields)
+}
+
+func (f *Form) valexFields(prefix string, depth int, errs *[]error) error {
+	if directive, err := f.valexField0(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Fields", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := f.valexField1(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"ValexField0", directive, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Form) valexField0() (string, error) {
+	if err := valexForm0.Validate(f.Fields); err != nil {
+		return "!empty", err
+	}
+	return "", nil
+}
+
+func (f *Form) valexField1() (string, error) {
+	if err := valexForm1.Validate(f.ValexField0); err != nil {
+		return "min", err
+	}
+	return "", nil
+}
+
+// Validate validates i as valex.ValidateStruct does, without reflection.
+func (i *Item) Validate() error {
+	return valex.RunGenerated(i, false, i.valexFields)
+}
+
+// ValidateAll validates i as valex.ValidateStructAll does, without reflection.
+func (i *Item) ValidateAll() error {
+	return valex.RunGenerated(i, true, i.valexFields)
+}
+
+func (i *Item) valexFields(prefix string, depth int, errs *[]error) error {
+	if directive, err := i.valexField0(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"SKU", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := i.valexField1(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Qty", directive, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Item) valexField0() (string, error) {
+	if err := valexItem0.Validate(i.SKU); err != nil {
+		return "len", err
+	}
+	return "", nil
+}
+
+func (i *Item) valexField1() (string, error) {
+	if err := valexItem1.Validate(i.Qty); err != nil {
+		return "minint", err
+	}
+	return "", nil
+}
+
+// Validate validates n as valex.ValidateStruct does, without reflection.
+func (n *Node) Validate() error {
+	return valex.RunGenerated(n, false, n.valexFields)
+}
+
+// ValidateAll validates n as valex.ValidateStructAll does, without reflection.
+func (n *Node) ValidateAll() error {
+	return valex.RunGenerated(n, true, n.valexFields)
+}
+
+func (n *Node) valexFields(prefix string, depth int, errs *[]error) error {
+	if directive, err := n.valexField0(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"ID", directive, err); err != nil {
+			return err
+		}
+	}
+	if err := valex.CheckDepth(prefix+"Next", depth+1); err != nil {
+		return err
+	}
+	if n.Next != nil {
+		if err := valex.CheckDepth(prefix+"Next", depth+2); err != nil {
+			return err
+		}
+		if err := n.Next.valexFields(prefix+"Next.", depth+2, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Node) valexField0() (string, error) {
+	if err := valexNode0.Validate(n.ID); err != nil {
+		return "uuid", err
+	}
+	return "", nil
+}
+
+// Validate validates s as valex.ValidateStruct does, without reflection.
+func (s *Signup) Validate() error {
+	return valex.RunGenerated(s, false, s.valexFields)
+}
+
+// ValidateAll validates s as valex.ValidateStructAll does, without reflection.
+func (s *Signup) ValidateAll() error {
+	return valex.RunGenerated(s, true, s.valexFields)
+}
+
+func (s *Signup) valexFields(prefix string, depth int, errs *[]error) error {
+	if directive, err := s.valexField0(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Name", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := s.valexField1(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Email", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := s.valexField2(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Age", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := s.valexField3(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Phone", directive, err); err != nil {
+			return err
+		}
+	}
+	if directive, err := s.valexField4(); err != nil {
+		if err := valex.DirectiveFailure(errs, prefix+"Plan", directive, err); err != nil {
+			return err
+		}
+	}
+	if err := valex.CheckDepth(prefix+"Address", depth+1); err != nil {
+		return err
+	}
+	if err := s.Address.valexFields(prefix+"Address.", depth+1, errs); err != nil {
+		return err
+	}
+	if err := valex.CheckDepth(prefix+"Billing", depth+1); err != nil {
+		return err
+	}
+	if s.Billing != nil {
+		if err := valex.CheckDepth(prefix+"Billing", depth+2); err != nil {
+			return err
+		}
+		if err := s.Billing.valexFields(prefix+"Billing.", depth+2, errs); err != nil {
+			return err
+		}
+	}
+	if err := valex.CheckDepth(prefix+"Items", depth+1); err != nil {
+		return err
+	}
+	for i0 := range s.Items {
+		p0 := prefix + "Items[" + strconv.Itoa(i0) + "]"
+		if err := valex.CheckDepth(p0, depth+2); err != nil {
+			return err
+		}
+		if err := s.Items[i0].valexFields(p0+".", depth+2, errs); err != nil {
+			return err
+		}
+	}
+	if err := valex.CheckDepth(prefix+"Backups", depth+1); err != nil {
+		return err
+	}
+	for i0 := range s.Backups {
+		p0 := prefix + "Backups[" + strconv.Itoa(i0) + "]"
+		if err := valex.CheckDepth(p0, depth+2); err != nil {
+			return err
+		}
+		if s.Backups[i0] != nil {
+			if err := valex.CheckDepth(p0, depth+3); err != nil {
+				return err
+			}
+			if err := s.Backups[i0].valexFields(p0+".", depth+3, errs); err != nil {
+				return err
+			}
+		}
+	}
+	if err := valex.CheckDepth(prefix+"Labels", depth+1); err != nil {
+		return err
+	}
+	for k0, v0 := range s.Labels {
+		p0 := prefix + "Labels[" + k0 + "]"
+		if err := valex.CheckDepth(p0, depth+2); err != nil {
+			return err
+		}
+		if err := v0.valexFields(p0+".", depth+2, errs); err != nil {
+			return err
+		}
+		s.Labels[k0] = v0
+	}
+	if err := valex.CheckDepth(prefix+"Scores", depth+1); err != nil {
+		return err
+	}
+	for k0, v0 := range s.Scores {
+		p0 := prefix + "Scores[" + strconv.Itoa(k0) + "]"
+		if err := valex.CheckDepth(p0, depth+2); err != nil {
+			return err
+		}
+		for i1 := range v0 {
+			p1 := p0 + "[" + strconv.Itoa(i1) + "]"
+			if err := valex.CheckDepth(p1, depth+3); err != nil {
+				return err
+			}
+			if err := v0[i1].valexFields(p1+".", depth+3, errs); err != nil {
+				return err
+			}
+		}
+		s.Scores[k0] = v0
+	}
+	if err := valex.CheckDepth(prefix+"Parent", depth+1); err != nil {
+		return err
+	}
+	if s.Parent != nil {
+		if err := valex.CheckDepth(prefix+"Parent", depth+2); err != nil {
+			return err
+		}
+		if err := s.Parent.valexFields(prefix+"Parent.", depth+2, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Signup) valexField0() (string, error) {
+	if err := valexSignup0.Validate(s.Name); err != nil {
+		return "!empty", err
+	}
+	if err := valexSignup1.Validate(s.Name); err != nil {
+		return "min", err
+	}
+	return "", nil
+}
+
+func (s *Signup) valexField1() (string, error) {
+	if err := valexSignup2.Validate(s.Email); err != nil {
+		return "email", err
+	}
+	return "", nil
+}
+
+func (s *Signup) valexField2() (string, error) {
+	if err := valexSignup3.Validate(s.Age); err != nil {
+		return "rangeint", err
+	}
+	return "", nil
+}
+
+func (s *Signup) valexField3() (string, error) {
+	val, err := valexSignup4.Handle(s.Phone)
+	if err != nil {
+		return "phone", err
+	}
+	s.Phone = val
+	return "", nil
+}
+
+func (s *Signup) valexField4() (string, error) {
+	if err := valexSignup5.Validate(s.Plan); err != nil {
+		return "oneof", err
+	}
+	return "", nil
+}
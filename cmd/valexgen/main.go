@@ -0,0 +1,94 @@
+// Valexgen generates reflection-free Validate and ValidateAll methods from
+// "val" struct tags.
+//
+// Usage:
+//
+//	valexgen [-type names] [-output file] [package]
+//
+// It loads the package (default the current directory) with the go command
+// and writes, for each named struct type (default: every struct type whose
+// values can hold a "val" tag), methods
+//
+//	func (t *T) Validate() error    // as valex.ValidateStruct(t)
+//	func (t *T) ValidateAll() error // as valex.ValidateStructAll(t)
+//
+// that call the valex/validators directives directly. They return the same
+// error types and field paths, run the same lifecycle hooks, and descend
+// through nested structs, pointers, slices, arrays, and maps the same way, so
+// the two can be swapped and cross-checked in tests. A typical use is a
+// go:generate line next to the types:
+//
+//	//go:generate go run github.com/tedla-brandsema/valex/cmd/valexgen -type Signup,Address
+//
+// The generated code knows only the valex/validators catalog, checked when it
+// is generated: a chain naming a custom directive or an alias, parameters that
+// do not convert, a directive on a field of the wrong type, and a nested struct
+// type from another package that has "val" tags are errors, as is a field or
+// method of a type named like a generated method, such as Validate. With
+// -type, every struct type of the package reached from a listed one that can
+// hold a tag must be listed too, since its methods are generated alongside.
+// Directives that read the registry's Env get a nil one, as on a registry
+// without options; values that cannot reach a tag are not walked, so the depth
+// limit only applies along paths that can.
+//
+// The default output file is <package>_valex.go in the package directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tedla-brandsema/valex/internal/load"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type `names`; default all with val tags")
+	output := flag.String("output", "", "output `file`; default <package>_valex.go in the package directory")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: valexgen [-type names] [-output file] [package]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	pattern := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		pattern = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	var names []string
+	for _, name := range strings.Split(*typeNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if err := run(pattern, names, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "valexgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pattern string, names []string, output string) error {
+	pkgs, err := load.Packages([]string{pattern})
+	if err != nil {
+		return err
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("%s matches %d packages, want one", pattern, len(pkgs))
+	}
+	src, err := generate(pkgs[0], names)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = filepath.Join(pkgs[0].Dir, pkgs[0].Name+"_valex.go")
+	}
+	return os.WriteFile(output, src, 0o644)
+}
@@ -0,0 +1,51 @@
+package bad
+
+import "github.com/tedla-brandsema/valex/cmd/valexgen/internal/sample"
+
+type Custom struct {
+	N int `val:"even"`
+}
+
+type Mismatch struct {
+	Name string `val:"rangeint,min=1,max=2"`
+}
+
+type BadParam struct {
+	N int `val:"rangeint,min=low,max=2"`
+}
+
+type Anonymous struct {
+	Inner struct {
+		Name string `val:"!empty"`
+	}
+}
+
+type Outer struct {
+	Inner Inner
+}
+
+type Inner struct {
+	Name string `val:"!empty"`
+}
+
+type Foreign struct {
+	Address sample.Address
+}
+
+type Grouped struct {
+	Name string `val:"create:!empty"`
+}
+
+type OmitEmpty struct {
+	Name string `val:"omitempty;!empty"`
+}
+
+type FieldClash struct {
+	Validate string `val:"!empty"`
+}
+
+type MethodClash struct {
+	Name string `val:"!empty"`
+}
+
+func (MethodClash) ValidateAll() error { return nil }
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/internal/catalog"
+	"github.com/tedla-brandsema/valex/internal/formtag"
+)
+
+// diagnostic is one problem found in a struct tag.
+type diagnostic struct {
+	pos token.Pos
+	msg string
+}
+
+func (d diagnostic) format(fset *token.FileSet) string {
+	pos := fset.Position(d.pos)
+	if wd, err := filepath.Abs("."); err == nil {
+		if rel, err := filepath.Rel(wd, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = rel
+		}
+	}
+	return fmt.Sprintf("%s: %s", pos, d.msg)
+}
+
+// checker checks struct tags against the catalog and the -known names, plus
+// the names a package declares in //valexvet:directive comments.
+type checker struct {
+	reg   *valex.Registry
+	known map[string]bool
+}
+
+// knownDirective stands in for a directive or alias valexvet cannot see, named
+// with -known or a //valexvet:directive comment. It accepts any parameters and is never type-checked.
+type knownDirective struct {
+	name string
+}
+
+func (d *knownDirective) Name() string              { return d.name }
+func (d *knownDirective) Mode() tagex.DirectiveMode { return tagex.EvalMode }
+func (d *knownDirective) Handle(v any) (any, error) { return v, nil }
+
+func newChecker(known []string) (*checker, error) {
+	c := &checker{reg: catalog.Registry(), known: make(map[string]bool)}
+	for _, name := range known {
+		if err := valex.RegisterDirectiveTo[any](c.reg, &knownDirective{name: name}); err != nil {
+			return nil, fmt.Errorf("-known %s: %w", name, err)
+		}
+		c.known[name] = true
+	}
+	return c, nil
+}
+
+// directivePrefix starts a comment declaring the custom directives and aliases
+// a package registers, separated by spaces or commas:
+//
+//	//valexvet:directive trim lower
+const directivePrefix = "//valexvet:directive"
+
+// check reports the problems in the struct tags of files, sorted by position.
+// info supplies field types; a field without one skips the type check.
+func (c *checker) check(fset *token.FileSet, files []*ast.File, info *types.Info) []diagnostic {
+	c, diags := c.declare(files)
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			st, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				var typ types.Type
+				if info != nil {
+					typ = info.TypeOf(field.Type)
+				}
+				for _, msg := range c.checkField(reflect.StructTag(tag), typ) {
+					diags = append(diags, diagnostic{pos: field.Tag.Pos(), msg: msg})
+				}
+			}
+			return true
+		})
+	}
+	sort.SliceStable(diags, func(i, j int) bool { return diags[i].pos < diags[j].pos })
+	return diags
+}
+
+// declare returns c extended with the names files declare in
+// //valexvet:directive comments, for checking their package, and reports the
+// comments that declare nothing or a catalog directive.
+func (c *checker) declare(files []*ast.File) (*checker, []diagnostic) {
+	var pc *checker
+	var diags []diagnostic
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, cm := range cg.List {
+				rest, ok := strings.CutPrefix(cm.Text, directivePrefix)
+				if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+					continue
+				}
+				names := strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+				if len(names) == 0 {
+					diags = append(diags, diagnostic{pos: cm.Pos(), msg: directivePrefix[2:] + " names no directive"})
+					continue
+				}
+				if pc == nil {
+					pc = c.clone()
+				}
+				for _, name := range names {
+					if pc.known[name] {
+						continue
+					}
+					if err := valex.RegisterDirectiveTo[any](pc.reg, &knownDirective{name: name}); err != nil {
+						diags = append(diags, diagnostic{pos: cm.Pos(), msg: fmt.Sprintf("%s %s: %v", directivePrefix[2:], name, err)})
+						continue
+					}
+					pc.known[name] = true
+				}
+			}
+		}
+	}
+	if pc == nil {
+		return c, diags
+	}
+	return pc, diags
+}
+
+// clone returns a checker with c's known names on a fresh registry.
+func (c *checker) clone() *checker {
+	pc := &checker{reg: catalog.Registry(), known: make(map[string]bool)}
+	for name := range c.known {
+		valex.MustRegisterDirectiveTo[any](pc.reg, &knownDirective{name: name})
+		pc.known[name] = true
+	}
+	return pc
+}
+
+// checkField returns the problems with one field's "val" and "field" tags.
+func (c *checker) checkField(tag reflect.StructTag, typ types.Type) []string {
+	var msgs []string
+	if val, ok := tag.Lookup("val"); ok {
+		msgs = append(msgs, c.checkVal(val, typ)...)
+	}
+	if field, ok := tag.Lookup("field"); ok {
+		msgs = append(msgs, checkFormField(field)...)
+	}
+	return msgs
+}
+
+func (c *checker) checkVal(tagValue string, typ types.Type) []string {
+	directives, err := c.reg.CheckTag(tagValue)
+	if err != nil {
+		return []string{fmt.Sprintf("val tag %q: %s", tagValue, describe(err))}
+	}
+	if typ == nil || typ == types.Typ[types.Invalid] || types.IsInterface(typ) {
+		return nil // unknown, or only known at run time
+	}
+	var msgs []string
+	for _, d := range directives {
+		if c.known[d.Name] || d.Type == nil {
+			continue
+		}
+		if same, ok := catalog.SameType(typ, d.Type); ok && !same {
+			msgs = append(msgs, fmt.Sprintf("val tag %q: directive %q applies to %s, not %s", tagValue, d.Name, d.Type, types.TypeString(typ, packageName)))
+		}
+	}
+	return msgs
+}
+
+// checkFormField reports what valex/forms would reject in a "field" tag, and
+// options that cannot take effect.
+func checkFormField(tagValue string) []string {
+	d, err := formtag.Parse(tagValue)
+	if err != nil {
+		return []string{fmt.Sprintf("field tag %q: %v", tagValue, err)}
+	}
+	var msgs []string
+	if d.Max <= 0 {
+		msgs = append(msgs, fmt.Sprintf("field tag %q: max must be positive, got %d", tagValue, d.Max))
+	}
+	if d.Required && strings.TrimSpace(d.DefaultValue) != "" {
+		msgs = append(msgs, fmt.Sprintf("field tag %q: required and default are both set; the default never applies", tagValue))
+	}
+	return msgs
+}
+
+// describe shortens a CheckTag error to its cause, naming the directive it
+// belongs to.
+func describe(err error) string {
+	var pe *valex.ProcessError
+	if !errors.As(err, &pe) || pe.Cause == nil {
+		return err.Error()
+	}
+	var unknown *valex.UnknownDirectiveError
+	if pe.Directive == "" || errors.As(pe.Cause, &unknown) {
+		return pe.Cause.Error()
+	}
+	return fmt.Sprintf("directive %q: %v", pe.Directive, pe.Cause)
+}
+
+func packageName(p *types.Package) string { return p.Name() }
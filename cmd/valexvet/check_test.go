@@ -0,0 +1,95 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/valex/internal/load"
+)
+
+// wantRe matches a `// want "pattern"` comment, as in go/analysis tests.
+var wantRe = regexp.MustCompile("// want `([^`]*)`")
+
+func TestCheck(t *testing.T) {
+	pkgs, err := load.Packages([]string{"./testdata/src/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newChecker([]string{"even"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range pkgs {
+		want := wants(pkg.Fset, pkg.Files)
+		for _, d := range c.check(pkg.Fset, pkg.Files, pkg.Info) {
+			line := pkg.Fset.Position(d.pos).Line
+			re, ok := want[line]
+			if !ok {
+				t.Errorf("line %d: unexpected diagnostic: %s", line, d.msg)
+				continue
+			}
+			if !re.MatchString(d.msg) {
+				t.Errorf("line %d: diagnostic %q does not match %q", line, d.msg, re)
+			}
+			delete(want, line)
+		}
+		for line, re := range want {
+			t.Errorf("line %d: no diagnostic matching %q", line, re)
+		}
+	}
+}
+
+func TestCheckKnownConflict(t *testing.T) {
+	if _, err := newChecker([]string{"email"}); err == nil {
+		t.Error("expected an error for a -known name that is a catalog directive")
+	}
+}
+
+// wants maps line numbers to the patterns in their want comments.
+func wants(fset *token.FileSet, files []*ast.File) map[int]*regexp.Regexp {
+	m := make(map[int]*regexp.Regexp)
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				if sm := wantRe.FindStringSubmatch(c.Text); sm != nil {
+					m[fset.Position(c.Pos()).Line] = regexp.MustCompile(sm[1])
+				}
+			}
+		}
+	}
+	return m
+}
+
+func TestCheckDirectiveComments(t *testing.T) {
+	pkgs, err := load.Packages([]string{"./testdata/src/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newChecker(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := pkgs[0]
+	want := map[int]string{
+		4: "valexvet:directive names no directive",
+		5: `valexvet:directive email: directive "email" is already registered`,
+		9: `unknown directive "upper"`,
+	}
+	for _, d := range c.check(pkg.Fset, pkg.Files, pkg.Info) {
+		line := pkg.Fset.Position(d.pos).Line
+		if w, ok := want[line]; !ok || !strings.Contains(d.msg, w) {
+			t.Errorf("line %d: unexpected diagnostic: %s", line, d.msg)
+		}
+		delete(want, line)
+	}
+	for line, w := range want {
+		t.Errorf("line %d: no diagnostic containing %q", line, w)
+	}
+	if c.known["trim"] {
+		t.Error("names declared by one package must not leak into the next")
+	}
+}
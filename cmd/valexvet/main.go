@@ -0,0 +1,80 @@
+// Valexvet checks "val" and "field" struct tags before the code runs.
+//
+// Usage:
+//
+//	valexvet [-known names] [packages]
+//
+// It loads the named packages (default ./...) with the go command, type-checks
+// them, and reports, for every struct field:
+//
+//   - "val" tags that do not parse, or whose alias-free directive chain names a
+//     directive that is not in the valex/validators catalog;
+//   - catalog directives whose parameters are missing or do not convert, such
+//     as rangeint,min=low;
+//   - directives applied to a field of the wrong type, such as rangeint on a
+//     string, which ValidateStruct would report as a *TypeMismatchError;
+//   - "field" tags that valex/forms would reject, a non-positive max, and
+//     fields that are both required and have a default, which never applies.
+//
+// Directives and aliases a program registers itself are unknown to valexvet;
+// list them with -known name,name, or declare them in a comment anywhere in
+// the package that uses them,
+//
+//	//valexvet:directive name name
+//
+// to accept them without checking their parameters or field types. Only
+// non-test Go files are checked.
+//
+// Valexvet prints one line per problem, file:line:col: message, and exits with
+// status 1 if it found any and 2 if the packages could not be loaded.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tedla-brandsema/valex/internal/load"
+)
+
+func main() {
+	known := flag.String("known", "", "comma-separated `names` of custom directives and aliases to accept")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: valexvet [-known names] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	var names []string
+	for _, name := range strings.Split(*known, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	pkgs, err := load.Packages(patterns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "valexvet:", err)
+		os.Exit(2)
+	}
+	c, err := newChecker(names)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "valexvet:", err)
+		os.Exit(2)
+	}
+	var found bool
+	for _, pkg := range pkgs {
+		for _, d := range c.check(pkg.Fset, pkg.Files, pkg.Info) {
+			fmt.Println(d.format(pkg.Fset))
+			found = true
+		}
+	}
+	if found {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package a
+
+import "time"
+
+type Email string
+
+type Input struct {
+	Name    string        `val:"min,size=3"`
+	Age     string        `val:"rangeint,min=1,max=150"`  // want `directive "rangeint" applies to int, not string`
+	Score   int           `val:"rangeint,min=low,max=10"` // want `unable to convert value "low" to int`
+	Missing string        `val:"min"`                     // want `"size" parameter not set`
+	Unknown string        `val:"trim;min,size=1"`         // want `unknown directive "trim"`
+	Custom  string        `val:"even,n=2"`
+	Broken  string        `val:"min,size"` // want `malformed key value pair "size"`
+	Mail    Email         `val:"email"`    // want `applies to string, not a.Email`
+	Raw     []byte        `val:"json"`     // want `applies to string, not \[\]byte`
+	Wait    time.Duration `val:"posduration"`
+	When    time.Time     `val:"posduration"` // want `applies to time.Duration, not time.Time`
+	Any     any           `val:"rangeint,min=1,max=2"`
+	Tags    []string      `field:"tags,max=0"`                   // want `max must be positive`
+	Page    int           `field:"page,required=true,default=1"` // want `required and default are both set`
+	Sort    string        `field:"sort,default=a=b"`             // want `malformed key value pair "default=a=b"`
+	Key     string        `field:""`                             // want `field tag value is required`
+	OK      string        `field:"ok,default=x" val:"!empty"`
+	Role    string        `val:"create:!empty;update:omitempty;email"`
+	Level   string        `val:"update:rangeint,min=1,max=2"` // want `directive "rangeint" applies to int, not string`
+	Nested  struct {
+		N string `val:"posint"` // want `applies to int, not string`
+	}
+}
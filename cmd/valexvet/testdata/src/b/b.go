@@ -0,0 +1,10 @@
+package b
+
+//valexvet:directive trim, lower
+//valexvet:directive
+//valexvet:directive email
+
+type Input struct {
+	Name  string `val:"trim;lower;min,size=1"`
+	Upper string `val:"upper"`
+}
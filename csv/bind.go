@@ -0,0 +1,80 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tedla-brandsema/valex/internal/convert"
+	"github.com/tedla-brandsema/valex/internal/formtag"
+)
+
+// column is a "csv"-tagged struct field and the header it reads.
+type column struct {
+	index     []int  // field index, through nested structs
+	path      string // field path, as valex reports it
+	header    string
+	directive formtag.Directive
+	cells     []int // positions of the header in each record
+}
+
+// plan lists the tagged fields of t, descending into nested struct values the
+// way valex/forms does.
+func plan(t reflect.Type, index []int, path string) ([]*column, error) {
+	var cols []*column
+	for n := 0; n < t.NumField(); n++ {
+		field := t.Field(n)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), n)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		if tagVal, ok := field.Tag.Lookup(tagKey); ok {
+			d, err := formtag.Parse(tagVal)
+			if err != nil {
+				return nil, fmt.Errorf("csv: field %s: %v", fieldPath, err)
+			}
+			if d.Max <= 0 {
+				return nil, fmt.Errorf("csv: field %s: invalid max %d", fieldPath, d.Max)
+			}
+			cols = append(cols, &column{index: fieldIndex, path: fieldPath, header: strings.TrimSpace(d.Key), directive: d})
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			nested, err := plan(field.Type, fieldIndex, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, nested...)
+		}
+	}
+	return cols, nil
+}
+
+// bind sets col's field of v from record, applying the default or reporting
+// ErrRequired when every cell of its column is empty.
+func (col *column) bind(v reflect.Value, record []string) error {
+	var raw []string
+	for _, i := range col.cells {
+		if i < len(record) && record[i] != "" {
+			raw = append(raw, record[i])
+		}
+	}
+	fieldValue := v.FieldByIndex(col.index)
+	if len(raw) == 0 {
+		if col.directive.Required {
+			return ErrRequired
+		}
+		if strings.TrimSpace(col.directive.DefaultValue) != "" {
+			return convert.Values(fieldValue, []string{col.directive.DefaultValue})
+		}
+		return nil
+	}
+	if max := col.directive.Max; len(raw) > max {
+		return fmt.Errorf("too many values (%d), max %d", len(raw), max)
+	}
+	return convert.Values(fieldValue, raw)
+}
@@ -0,0 +1,316 @@
+package csv
+
+import (
+	encsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+const tagKey = "csv"
+
+// ErrRequired is returned, wrapped in a *CellError, when a required column is
+// empty in a record.
+var ErrRequired = errors.New("value is required")
+
+// Cell identifies a cell of the input: the line its record starts on and the
+// header of its column.
+type Cell struct {
+	Line   int
+	Column string
+}
+
+// CellError is a failure for one cell: a required value that is empty, a
+// value that does not convert, or one that fails validation. Column is empty
+// for a failure that belongs to the record rather than a column, such as a
+// record with the wrong number of fields or a failing lifecycle hook.
+type CellError struct {
+	Line   int    // line the record starts on, from 1
+	Column string // header of the column
+	Field  string // struct field path, such as Address.Zip
+	Err    error
+}
+
+func (e *CellError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("csv: line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("csv: line %d, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *CellError) Unwrap() error { return e.Err }
+
+// CellErrors flattens err — from Decode or ReadAll — into a map keyed by cell.
+// It returns nil when err holds no *CellError.
+func CellErrors(err error) map[Cell]error {
+	m := make(map[Cell]error)
+	collectCellErrors(err, m)
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func collectCellErrors(err error, m map[Cell]error) {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			collectCellErrors(e, m)
+		}
+		return
+	}
+	var ce *CellError
+	if errors.As(err, &ce) {
+		if _, ok := m[Cell{ce.Line, ce.Column}]; !ok {
+			m[Cell{ce.Line, ce.Column}] = ce
+		}
+	}
+}
+
+// Option configures NewDecoder and ReadAll.
+type Option func(*config)
+
+type config struct {
+	comma     rune
+	maxErrors int
+	reg       *valex.Registry // nil uses valex's default registry
+}
+
+// WithComma sets the field delimiter, ',' by default.
+func WithComma(r rune) Option {
+	return func(c *config) {
+		c.comma = r
+	}
+}
+
+// WithMaxErrors makes ReadAll stop once it has collected n cell errors. n <= 0
+// collects every error, the default.
+func WithMaxErrors(n int) Option {
+	return func(c *config) {
+		c.maxErrors = n
+	}
+}
+
+// WithRegistry validates against reg instead of valex's default registry. A
+// nil reg uses the default.
+func WithRegistry(reg *valex.Registry) Option {
+	return func(c *config) {
+		c.reg = reg
+	}
+}
+
+// Decoder reads records of a CSV input with a header line into values of
+// type T, a struct type, and validates them.
+type Decoder[T any] struct {
+	cfg    *config
+	r      *encsv.Reader
+	cols   []*column
+	err    error // sticky: a bad type, tag, or header
+	header bool  // the header has been read
+	fields int   // fields in the header
+	line   int
+}
+
+// NewDecoder returns a Decoder reading from r. The first record of r is the
+// header, matched against the "csv" tags of T when Decode is first called.
+func NewDecoder[T any](r io.Reader, opts ...Option) *Decoder[T] {
+	cfg := &config{comma: ','}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cr := encsv.NewReader(r)
+	cr.Comma = cfg.comma
+	cr.FieldsPerRecord = -1 // checked against the header by Decode
+	d := &Decoder[T]{cfg: cfg, r: cr}
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		d.err = fmt.Errorf("csv: record type must be a struct, got %s", t)
+		return d
+	}
+	d.cols, d.err = plan(t, nil, "")
+	return d
+}
+
+// Line returns the line the last record read starts on.
+func (d *Decoder[T]) Line() int {
+	return d.line
+}
+
+// Decode reads the next record into *v, binds its cells, and validates the
+// result with ValidateStructAll. It returns io.EOF after the last record.
+//
+// A record that does not bind or validate returns an errors.Join of
+// *CellErrors — CellErrors keys them by cell — with *v holding what did bind;
+// Decode can be called again for the next record. Any other error, such as
+// malformed CSV, a header missing a required column, or a bad "csv" tag, is
+// returned again by every later call.
+func (d *Decoder[T]) Decode(v *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if !d.header {
+		if d.err = d.readHeader(); d.err != nil {
+			return d.err
+		}
+	}
+	record, err := d.r.Read()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			d.err = err
+		}
+		return err
+	}
+	d.line, _ = d.r.FieldPos(0)
+	if len(record) != d.fields {
+		return &CellError{Line: d.line, Err: fmt.Errorf("record has %d fields, header has %d", len(record), d.fields)}
+	}
+
+	*v = *new(T)
+	val := reflect.ValueOf(v).Elem()
+	var errs []error
+	failed := make(map[string]bool)
+	for _, col := range d.cols {
+		if err := col.bind(val, record); err != nil {
+			errs = append(errs, &CellError{Line: d.line, Column: col.header, Field: col.path, Err: err})
+			failed[col.path] = true
+		}
+	}
+	if err := d.validate(v); err != nil {
+		errs = append(errs, d.validationErrors(err, failed)...)
+	}
+	return errors.Join(errs...)
+}
+
+// readHeader maps each column to the positions of its header, and requires
+// the header of every required column.
+func (d *Decoder[T]) readHeader() error {
+	header, err := d.r.Read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("csv: no header line: %w", io.ErrUnexpectedEOF)
+	}
+	if err != nil {
+		return err
+	}
+	d.header = true
+	d.fields = len(header)
+	positions := make(map[string][]int)
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if i == 0 {
+			h = strings.TrimPrefix(h, "\ufeff") // a byte order mark
+		}
+		positions[h] = append(positions[h], i)
+	}
+	for _, col := range d.cols {
+		col.cells = positions[col.header]
+		if len(col.cells) == 0 && col.directive.Required {
+			return fmt.Errorf("csv: header has no column %q for required field %s", col.header, col.path)
+		}
+	}
+	return nil
+}
+
+func (d *Decoder[T]) validate(v *T) error {
+	if d.cfg.reg != nil {
+		return d.cfg.reg.ValidateStructAll(v)
+	}
+	return valex.ValidateStructAll(v)
+}
+
+// validationErrors turns a ValidateStructAll error into *CellErrors, each on
+// the column whose field (or an element of it) failed, skipping fields that
+// already failed to bind. A failure that is not a field's, such as a
+// lifecycle hook, belongs to the record.
+func (d *Decoder[T]) validationErrors(err error, failed map[string]bool) []error {
+	fields := valex.FieldErrors(err)
+	if len(fields) == 0 {
+		return []error{&CellError{Line: d.line, Err: err}}
+	}
+	errs := make([]*CellError, 0, len(fields))
+	order := make(map[*CellError]int, len(fields))
+	for path, err := range fields {
+		ce := &CellError{Line: d.line, Field: path, Err: err}
+		i := d.columnOf(path)
+		if i < len(d.cols) {
+			if failed[d.cols[i].path] {
+				continue
+			}
+			ce.Column = d.cols[i].header
+		}
+		order[ce] = i
+		errs = append(errs, ce)
+	}
+	// In column order, then by path; failures outside any column come last.
+	sort.Slice(errs, func(a, b int) bool {
+		if order[errs[a]] != order[errs[b]] {
+			return order[errs[a]] < order[errs[b]]
+		}
+		return errs[a].Field < errs[b].Field
+	})
+	out := make([]error, len(errs))
+	for i, ce := range errs {
+		out[i] = ce
+	}
+	return out
+}
+
+// columnOf returns the index of the column whose field path is path or holds
+// it, or len(d.cols) if there is none.
+func (d *Decoder[T]) columnOf(path string) int {
+	for i, col := range d.cols {
+		if path == col.path || strings.HasPrefix(path, col.path+".") || strings.HasPrefix(path, col.path+"[") {
+			return i
+		}
+	}
+	return len(d.cols)
+}
+
+// ReadAll decodes every record of r, returning the records that bound and
+// validated, in order, and the errors.Join of the *CellErrors of the others.
+// With WithMaxErrors(n) it stops reading once it has n of them. An error that
+// ends decoding early, such as malformed CSV, is joined after them.
+func ReadAll[T any](r io.Reader, opts ...Option) ([]T, error) {
+	d := NewDecoder[T](r, opts...)
+	var out []T
+	var errs []error
+	for {
+		var v T
+		err := d.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			out = append(out, v)
+			continue
+		}
+		cells, ok := cellErrors(err)
+		if !ok {
+			errs = append(errs, err)
+			break
+		}
+		errs = append(errs, cells...)
+		if max := d.cfg.maxErrors; max > 0 && len(errs) >= max {
+			errs = errs[:max]
+			break
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// cellErrors returns the *CellErrors a record's error is made of, or false
+// for an error that ends decoding.
+func cellErrors(err error) ([]error, bool) {
+	var ce *CellError
+	if errors.As(err, &ce) && ce == err {
+		return []error{err}, true
+	}
+	j, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil, false
+	}
+	return j.Unwrap(), true
+}
@@ -0,0 +1,142 @@
+package csv_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/csv"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+type address struct {
+	City string `csv:"city" val:"!empty"`
+}
+
+type contact struct {
+	Name    string        `csv:"name,required=true" val:"min,size=2"`
+	Age     int           `csv:"age" val:"rangeint,min=0,max=150"`
+	Plan    string        `csv:"plan,default=free"`
+	Tags    []string      `csv:"tag,max=2"`
+	Timeout time.Duration `csv:"timeout,default=1s"`
+	Address address
+	Ignored string
+}
+
+func newRegistry() *valex.Registry {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.MinLengthValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.NonEmptyStringValidator{})
+	return reg
+}
+
+const input = "\ufeffname,age,plan,tag,tag,city,timeout,extra\n" +
+	"Ada,36,pro,a,b,London,,x\n" +
+	"B,200,,,,Paris,,x\n" +
+	",x,,,,,2m,x\n" +
+	"\"Dee\nDee\",7,,c,,Oslo,5s,x\n"
+
+func TestReadAll(t *testing.T) {
+	got, err := csv.ReadAll[contact](strings.NewReader(input), csv.WithRegistry(newRegistry()))
+	want := []contact{
+		{Name: "Ada", Age: 36, Plan: "pro", Tags: []string{"a", "b"}, Timeout: time.Second, Address: address{City: "London"}},
+		{Name: "Dee\nDee", Age: 7, Plan: "free", Tags: []string{"c"}, Timeout: 5 * time.Second, Address: address{City: "Oslo"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	cells := csv.CellErrors(err)
+	wantCells := []csv.Cell{{3, "name"}, {3, "age"}, {4, "name"}, {4, "age"}, {4, "city"}}
+	if len(cells) != len(wantCells) {
+		t.Errorf("CellErrors = %v", cells)
+	}
+	for _, cell := range wantCells {
+		if cells[cell] == nil {
+			t.Errorf("no error for %+v in %v", cell, cells)
+		}
+	}
+	var ce *csv.CellError
+	if !errors.As(cells[csv.Cell{4, "name"}], &ce) || !errors.Is(ce, csv.ErrRequired) || ce.Field != "Name" {
+		t.Errorf("want ErrRequired for Name, got %v", cells[csv.Cell{4, "name"}])
+	}
+	var pe *valex.ProcessError
+	if !errors.As(cells[csv.Cell{3, "age"}], &pe) || pe.Directive != "rangeint" {
+		t.Errorf("want a rangeint failure, got %v", cells[csv.Cell{3, "age"}])
+	}
+	if !errors.As(cells[csv.Cell{4, "city"}], &ce) || ce.Field != "Address.City" {
+		t.Errorf("want the nested field path, got %v", cells[csv.Cell{4, "city"}])
+	}
+}
+
+func TestReadAllMaxErrors(t *testing.T) {
+	_, err := csv.ReadAll[contact](strings.NewReader(input), csv.WithRegistry(newRegistry()), csv.WithMaxErrors(1))
+	if cells := csv.CellErrors(err); len(cells) != 1 || cells[csv.Cell{3, "name"}] == nil {
+		t.Errorf("CellErrors = %v", cells)
+	}
+}
+
+type person struct {
+	Name string `csv:"name"`
+	Age  int    `csv:"age" val:"rangeint,min=0,max=150"`
+}
+
+func TestDecoder(t *testing.T) {
+	in := "name;age\nAda;36\nAda;36;extra\nBo;x\nCy;1\n"
+	d := csv.NewDecoder[person](strings.NewReader(in), csv.WithComma(';'), csv.WithRegistry(newRegistry()))
+	var lines []int
+	var errs []error
+	for {
+		var p person
+		err := d.Decode(&p)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		lines = append(lines, d.Line())
+		errs = append(errs, err)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 3, 4, 5}) {
+		t.Errorf("lines = %v", lines)
+	}
+	var ce *csv.CellError
+	if errs[0] != nil || !errors.As(errs[1], &ce) || ce.Column != "" || errs[2] == nil || errs[3] != nil {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+func TestDecoderFatal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"no header", ""},
+		{"missing required column", "age\n1\n"},
+		{"bare quote", "name\nA\"da\n"},
+	}
+	for _, tt := range tests {
+		d := csv.NewDecoder[contact](strings.NewReader(tt.in), csv.WithRegistry(newRegistry()))
+		var c contact
+		err := d.Decode(&c)
+		if err == nil || errors.Is(err, io.EOF) || csv.CellErrors(err) != nil {
+			t.Errorf("%s: want a fatal error, got %v", tt.name, err)
+		}
+		if again := d.Decode(&c); again != err {
+			t.Errorf("%s: want the error again, got %v", tt.name, again)
+		}
+	}
+
+	type badTag struct {
+		N int `csv:"n,max=0"`
+	}
+	if _, err := csv.ReadAll[badTag](strings.NewReader("n\n1\n")); err == nil {
+		t.Error("expected an error for max=0")
+	}
+	if _, err := csv.ReadAll[int](strings.NewReader("n\n1\n")); err == nil {
+		t.Error("expected an error for a non-struct type")
+	}
+}
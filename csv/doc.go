@@ -0,0 +1,57 @@
+// Package csv binds the records of a CSV file into structs and validates them
+// with the valex engine's "val" tag, reporting each failure by line and column.
+//
+// Like valex/forms, it involves two tags: "csv" maps a field to a header
+// column and controls binding, with the grammar of the forms "field" tag, and
+// "val" validates the bound value.
+//
+//	type Contact struct {
+//		Email string   `csv:"email,required=true" val:"email"`
+//		Age   int      `csv:"age" val:"rangeint,min=0,max=150"`
+//		Plan  string   `csv:"plan,default=free"`
+//		Tags  []string `csv:"tag,max=3"`
+//	}
+//
+//	contacts, err := csv.ReadAll[Contact](f)
+//	for cell, err := range csv.CellErrors(err) {
+//		log.Printf("line %d, %s: %v", cell.Line, cell.Column, err)
+//	}
+//
+// # The csv tag
+//
+// The first value is the header of the column to read; the remaining options
+// are key=value pairs:
+//
+//	Option    Default  Description
+//	--------- -------- ---------------------------------------------------------
+//	(header)  -        header of the column, required
+//	required  false    report ErrRequired when the cell is empty, and require
+//	                   the column in the header
+//	default   -        value to bind when the cell is empty or the column absent
+//	max       1        columns with this header a slice field takes at most
+//
+// The first line of the input is the header; headers are trimmed, and a byte
+// order mark before the first is ignored. Columns no field reads are ignored.
+// A header may repeat: a slice field receives one element per non-empty cell
+// under it. Cells convert the way valex/env converts variables — strings,
+// booleans, integers, floats, time.Duration, and any type
+// implementing encoding.TextUnmarshaler. Nested struct fields without a "csv"
+// tag are descended into, and their fields read columns the same way.
+//
+// # Errors
+//
+// Each record is bound and then validated with ValidateStructAll. Every
+// failure is a *CellError carrying the line the record starts on, the header
+// of the column, the struct field path, and the cause: ErrRequired, a
+// conversion error, or the valex validation error. A failure outside any
+// column, such as a record with the wrong number of fields, has an empty
+// Column. CellErrors keys them by Cell, the (line, column) pair.
+//
+// ReadAll collects them all and returns the valid records; WithMaxErrors(n)
+// stops it after n. Decoder reads one record at a time for inputs too large
+// to hold, and leaves the decision to the caller. Malformed CSV, a header
+// missing a required column, and a bad "csv" tag stop decoding.
+//
+// WithComma sets the delimiter, and WithRegistry validates against an isolated
+// *valex.Registry instead of the default.
+package csv
@@ -0,0 +1,35 @@
+package csv_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/csv"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+// ReadAll returns the valid rows and reports the others by line and column.
+func ExampleReadAll() {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.EmailValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+
+	type Contact struct {
+		Email string `csv:"email,required=true" val:"email"`
+		Age   int    `csv:"age" val:"rangeint,min=0,max=150"`
+		Plan  string `csv:"plan,default=free"`
+	}
+
+	input := "email,age,plan\n" +
+		"ada@example.com,36,pro\n" +
+		"not-an-email,36,\n" +
+		"bo@example.com,old,\n"
+	contacts, err := csv.ReadAll[Contact](strings.NewReader(input), csv.WithRegistry(reg))
+	fmt.Printf("%+v\n", contacts)
+	cells := csv.CellErrors(err)
+	fmt.Println(cells[csv.Cell{Line: 3, Column: "email"}] != nil, cells[csv.Cell{Line: 4, Column: "age"}])
+	// Output:
+	// [{Email:ada@example.com Age:36 Plan:pro}]
+	// true csv: line 4, column "age": strconv.ParseInt: parsing "old": invalid syntax
+}
@@ -6,11 +6,14 @@
 // It supports two primary workflows:
 //
 //  1. Programmatic validation using Validator or ValidatorFunc, with
-//     ValidatedValue for guarded assignment and MustValidate for fail-fast use.
+//     ValidatedValue for guarded assignment (SyncValidatedValue when the value
+//     is shared across goroutines), Refined for values that must stay valid
+//     through JSON, text, and database decoding, and MustValidate for
+//     fail-fast use.
 //  2. Struct-tag validation using the "val" tag and ValidateStruct. Register
 //     directives with MustRegisterDirective (or RegisterDirective, which returns
-//     an error instead of panicking); pass additional tagex.Tag values to
-//     ValidateStruct to process multiple tags in a single pass.
+//     an error instead of panicking); pass WithTags to ValidateStruct to
+//     process multiple tags in the same call.
 //
 // The engine ships no directives of its own. Ready-made validators live in the
 // github.com/tedla-brandsema/valex/validators subpackage; register the ones you
@@ -39,4 +42,57 @@
 // Register on one with the free functions RegisterDirectiveTo /
 // MustRegisterDirectiveTo (free functions because Go methods can't be generic),
 // and validate with its ValidateStruct method.
+//
+// NewRegistry takes options that make up the registry's environment (Env):
+// WithClock sets the clock time-relative directives read "now" from, and
+// WithValue adds any other dependency a directive looks up, such as a resolver
+// or a lookup table. A directive receives its registry's Env by implementing
+// EnvReceiver, so directives that depend on the time or on lookups can be
+// tested in isolation instead of against process-global state.
+//
+// # Aliases
+//
+// Alias names a chain so structs can share it: after
+// MustAlias("useremail", "trim;lower;email;max,size=254") a field tagged
+// `val:"useremail"` runs that chain. A parameter value written as $name in the
+// chain is bound where the alias is used, so "trim;min,size=$min" aliased as
+// "username" is used as `val:"username,min=3"`. Aliases may use other aliases
+// but not in a cycle, which Alias reports as an *AliasCycleError. ExpandTag
+// returns a tag value with its aliases expanded, for tooling that inspects tags,
+// and CheckTag goes further, resolving each directive and its parameters and
+// reporting the field type it applies to; cmd/valexvet uses it to check tags
+// at build time.
+//
+// # Groups
+//
+// A segment prefixed with groups, as in `val:"create:!empty;update:omitempty;email"`,
+// runs only when ValidateStructWith is called with Groups naming one of them;
+// segments without a prefix always run. The built-in omitempty keyword ends a
+// chain early for a zero value, so the rest of the chain checks only values
+// that are set.
+//
+// # Partial validation
+//
+// ValidateFields and ValidateFieldsAll validate only the fields at the given
+// paths, such as "Email" or "Address.Zip", and everything under them — the
+// fields a PATCH request sets. A path that names no field is reported as an
+// *UnknownFieldError. To select groups as well, pass the Fields and Groups
+// options to ValidateStructWith.
+//
+// # Generated code
+//
+// cmd/valexgen generates Validate and ValidateAll methods from "val" tags that
+// call the catalog directives without reflection and return the same errors as
+// ValidateStruct and ValidateStructAll. The code it writes calls MustParams,
+// RunGenerated, DirectiveFailure, and CheckDepth, which exist for it.
+//
+// # Parameter cache
+//
+// tagex converts a directive's parameters on every ValidateStruct call. A
+// directive whose conversion is expensive can pass it through CachedParam,
+// which keeps the result in a bounded, process-wide LRU cache keyed by the
+// directive's type, parameter, and raw value; the catalog does this for regular
+// expressions, time and IP parameters, time zones, version constraints, and
+// pipe-separated lists. SetParamCacheSize bounds or disables the cache, and
+// ParamCacheStats and SetParamCacheHook expose its hit rate.
 package valex
@@ -0,0 +1,100 @@
+package valex
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// Clock supplies the current time to directives that validate relative to
+// "now". Inject a fixed Clock with WithClock to make such directives
+// deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function into a Clock.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// Option configures a Registry created by NewRegistry.
+type Option func(*Registry)
+
+// WithClock makes the registry's directives read the current time from c
+// instead of time.Now.
+func WithClock(c Clock) Option {
+	return func(r *Registry) {
+		r.env.clock = c
+	}
+}
+
+// WithValue adds a value to the registry's environment under key, for
+// directives that look up a dependency — a resolver, a lookup table, a source
+// of randomness — instead of reaching for process-global state. As with
+// context.WithValue, key should be of an unexported type defined by the
+// package that reads it, to avoid collisions. A later WithValue for the same
+// key replaces the earlier one.
+func WithValue(key, val any) Option {
+	return func(r *Registry) {
+		if r.env.values == nil {
+			r.env.values = make(map[any]any)
+		}
+		r.env.values[key] = val
+	}
+}
+
+// Env is the environment a Registry hands to its directives: the clock they
+// read "now" from and the values added with WithValue. It is fixed once the
+// registry is created, so directives may read it concurrently. A nil *Env is
+// valid and behaves like an empty one on the system clock, so a directive used
+// programmatically, outside any registry, behaves sensibly.
+type Env struct {
+	clock  Clock
+	values map[any]any
+}
+
+// Now returns the current time according to the environment's clock, or
+// time.Now when none was configured.
+func (e *Env) Now() time.Time {
+	if e == nil || e.clock == nil {
+		return time.Now()
+	}
+	return e.clock.Now()
+}
+
+// Value returns the value added under key with WithValue, or nil.
+func (e *Env) Value(key any) any {
+	if e == nil {
+		return nil
+	}
+	return e.values[key]
+}
+
+// EnvReceiver is implemented by directives that need the registry's
+// environment. When such a directive is registered, the registry stores a copy
+// of it with SetEnv already called, so every Handle call sees that registry's
+// Env; the value passed to RegisterDirectiveTo is left untouched, and the same
+// value can be registered on several registries.
+type EnvReceiver interface {
+	SetEnv(env *Env)
+}
+
+// bindEnv returns d bound to env if d is an EnvReceiver, copying pointer
+// directives first so the caller's value is not modified.
+func bindEnv[T any](env *Env, d tagex.Directive[T]) tagex.Directive[T] {
+	if _, ok := d.(EnvReceiver); !ok {
+		return d
+	}
+	if v := reflect.ValueOf(d); v.Kind() == reflect.Pointer && !v.IsNil() {
+		dup := reflect.New(v.Elem().Type())
+		dup.Elem().Set(v.Elem())
+		d = dup.Interface().(tagex.Directive[T])
+	}
+	d.(EnvReceiver).SetEnv(env)
+	return d
+}
@@ -0,0 +1,59 @@
+// Package env binds environment variables into a configuration struct and
+// validates it with the valex engine's "val" tag.
+//
+// Like valex/forms, it is separate from the core engine and involves two tags:
+// "env" maps a field to a variable and controls binding, and "val" validates
+// the bound value.
+//
+//	type Config struct {
+//		Port     int           `env:"PORT,default=8080" val:"rangeint,min=1,max=65535"`
+//		Timeout  time.Duration `env:"TIMEOUT,default=5s"`
+//		Hosts    []string      `env:"HOSTS,required=true"`
+//		DB       DBConfig      `env:",prefix=DB_"`
+//	}
+//
+//	var cfg Config
+//	if err := env.ValidateAll(&cfg, env.WithPrefix("APP_")); err != nil {
+//		log.Fatal(err) // every missing or invalid variable, each named
+//	}
+//
+// # The env tag
+//
+// The first value is the variable name; an empty name derives one from the
+// field name in upper snake case (MaxConns reads MAX_CONNS). The remaining
+// comma-separated options are key=value pairs, single-quoted when a value
+// contains a comma or significant whitespace (default='a, b'):
+//
+//	Option    Default  Description
+//	--------- -------- ---------------------------------------------------------
+//	(name)    derived  variable to read, after any prefixes
+//	required  false    report ErrRequired when the variable is unset or empty
+//	default   -        value to bind when the variable is unset or empty; not
+//	                   with required
+//	sep       ,        separator between the elements of a slice field
+//	prefix    -        on a struct field, prefix for the variables inside it
+//
+// Only fields with an "env" tag are bound; a variable that is set but empty
+// counts as unset. Fields convert from text — strings, booleans, integers,
+// floats, time.Duration, and any type implementing encoding.TextUnmarshaler
+// (time.Time, net.IP, …) — and slice elements are trimmed after splitting. Struct fields are descended
+// into, with their prefix appended to the enclosing one; a nil struct pointer
+// is allocated when it carries an "env" tag and skipped otherwise.
+//
+// # Sources and registries
+//
+// Variables come from os.LookupEnv unless WithLookup or WithMap supplies
+// another source, which keeps tests independent of the process environment.
+// WithPrefix prepends a prefix to every name, and WithRegistry validates
+// against an isolated *valex.Registry instead of the default.
+//
+// # Errors
+//
+// Bind and Validate stop at the first failure; ValidateAll reports them all as
+// an errors.Join. A failure on a bound field is a *VarError naming the
+// variable and the struct field path, wrapping ErrRequired, a conversion
+// error, or the valex validation error. FieldErrors flattens the result into a
+// map keyed by field path, preferring a binding failure over a validation
+// failure on the same field. A malformed "env" tag or a target that is not a
+// pointer to a struct is returned as a plain error.
+package env
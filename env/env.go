@@ -0,0 +1,349 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/internal/convert"
+	"github.com/tedla-brandsema/valex/internal/scan"
+)
+
+type fieldDirective struct {
+	Name         string `param:"name,required=false"`
+	Required     bool   `param:"required,required=false"`
+	DefaultValue string `param:"default,required=false"`
+	Sep          string `param:"sep,required=false"`
+	Prefix       string `param:"prefix,required=false"`
+}
+
+// ErrRequired is returned, wrapped in a *VarError, when a required variable is
+// unset or empty.
+var ErrRequired = errors.New("variable is required")
+
+// VarError is a failure for one environment variable: a missing required
+// variable, a value that does not convert to the field's type, or a "val"
+// directive that rejected the bound value. Unwrap returns the cause.
+type VarError struct {
+	Var   string // environment variable name, prefixes included
+	Field string // struct field path, e.g. "DB.Port"
+	Err   error
+}
+
+func (e *VarError) Error() string { return fmt.Sprintf("env var %q: %v", e.Var, e.Err) }
+func (e *VarError) Unwrap() error { return e.Err }
+
+// Option configures Bind, Validate, and ValidateAll.
+type Option func(*config)
+
+type config struct {
+	prefix string
+	lookup func(string) (string, bool)
+	reg    *valex.Registry // nil uses valex's default registry
+}
+
+// WithPrefix prepends prefix to every variable name, e.g. "APP_".
+func WithPrefix(prefix string) Option {
+	return func(c *config) {
+		c.prefix = prefix
+	}
+}
+
+// WithLookup reads variables through fn instead of os.LookupEnv.
+func WithLookup(fn func(string) (string, bool)) Option {
+	return func(c *config) {
+		c.lookup = fn
+	}
+}
+
+// WithMap reads variables from m instead of the process environment, which
+// keeps tests independent of it.
+func WithMap(m map[string]string) Option {
+	return WithLookup(func(name string) (string, bool) {
+		v, ok := m[name]
+		return v, ok
+	})
+}
+
+// WithRegistry validates against reg instead of valex's default registry. A
+// nil reg uses the default.
+func WithRegistry(reg *valex.Registry) Option {
+	return func(c *config) {
+		c.reg = reg
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Bind sets dst's "env"-tagged fields from the environment, stopping at the
+// first error.
+func Bind(dst any, opts ...Option) error {
+	b, err := newBinder(dst, opts, false)
+	if err != nil {
+		return err
+	}
+	if len(b.errs) > 0 {
+		return b.errs[0]
+	}
+	return nil
+}
+
+// Validate binds dst like Bind and then validates its "val" tags, stopping at
+// the first error. A validation failure on a bound field is returned as a
+// *VarError naming the variable.
+func Validate(dst any, opts ...Option) error {
+	b, err := newBinder(dst, opts, false)
+	if err != nil {
+		return err
+	}
+	if len(b.errs) > 0 {
+		return b.errs[0]
+	}
+	return b.validate(dst)
+}
+
+// ValidateAll binds dst and validates its "val" tags, collecting every missing,
+// malformed, and invalid variable instead of stopping at the first. It returns
+// nil on success, or an errors.Join of the failures — pass it to FieldErrors
+// for a field-keyed map.
+func ValidateAll(dst any, opts ...Option) error {
+	b, err := newBinder(dst, opts, true)
+	if err != nil {
+		return err
+	}
+	parts := b.errs
+	if valErr := b.validate(dst); valErr != nil {
+		parts = append(parts, valErr)
+	}
+	return errors.Join(parts...)
+}
+
+// FieldErrors flattens err — typically from ValidateAll — into a map from
+// struct field path to the error for that field. As in forms.FieldErrors, a
+// binding failure wins over a validation failure on the same field, and
+// non-field errors are omitted.
+func FieldErrors(err error) map[string]error {
+	if err == nil {
+		return nil
+	}
+	m := valex.FieldErrors(err)
+	if m == nil {
+		m = make(map[string]error)
+	}
+	collectBindErrors(err, m)
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func collectBindErrors(err error, m map[string]error) {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			collectBindErrors(e, m)
+		}
+		return
+	}
+	var ve *VarError
+	var pe *valex.ProcessError
+	if errors.As(err, &ve) && !errors.As(err, &pe) {
+		m[ve.Field] = err
+	}
+}
+
+// binder holds one pass over a struct: the variable bound to each field path,
+// and the errors collected along the way.
+type binder struct {
+	cfg  *config
+	vars map[string]string // field path -> variable name
+	all  bool
+	errs []error
+}
+
+// newBinder binds dst. Field failures are collected in b.errs — only the first
+// unless all is set; a malformed target or tag is returned as err.
+func newBinder(dst any, opts []Option, all bool) (*binder, error) {
+	b := &binder{cfg: newConfig(opts), vars: make(map[string]string), all: all}
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a pointer to a struct but got %T", dst)
+	}
+	if err := b.bindStruct(val.Elem(), b.cfg.prefix, ""); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// bindStruct binds val's exported fields under prefix. Struct fields are
+// descended into, extending prefix with their tag's prefix option; a nil
+// struct pointer is allocated only when it carries an "env" tag.
+func (b *binder) bindStruct(val reflect.Value, prefix, path string) error {
+	for n := 0; n < val.NumField() && !b.stopped(); n++ {
+		field := val.Type().Field(n)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldValue := val.Field(n)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tagVal, tagged := field.Tag.Lookup("env")
+		var directive fieldDirective
+		if tagged {
+			if err := parseTag(tagVal, &directive); err != nil {
+				return fmt.Errorf("env: field %s: %w", fieldPath, err)
+			}
+		}
+
+		if elem, ok := structValue(fieldValue, tagged); ok {
+			if err := b.bindStruct(elem, prefix+directive.Prefix, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if !tagged {
+			continue
+		}
+
+		name := directive.Name
+		if name == "" {
+			name = strings.ToUpper(strings.Join(scan.Words(field.Name), "_"))
+		}
+		name = prefix + name
+		b.vars[fieldPath] = name
+		if err := b.bindField(fieldValue, directive, name); err != nil {
+			b.errs = append(b.errs, &VarError{Var: name, Field: fieldPath, Err: err})
+		}
+	}
+	return nil
+}
+
+// stopped reports whether binding should stop: after the first field error,
+// unless all errors are being collected.
+func (b *binder) stopped() bool {
+	return !b.all && len(b.errs) > 0
+}
+
+// bindField sets fieldValue from the variable name. An unset or empty variable
+// takes the default, or fails with ErrRequired.
+func (b *binder) bindField(fieldValue reflect.Value, directive fieldDirective, name string) error {
+	raw, ok := b.cfg.lookup(name)
+	if !ok || raw == "" {
+		if directive.Required {
+			return ErrRequired
+		}
+		if directive.DefaultValue == "" {
+			return nil
+		}
+		raw = directive.DefaultValue
+	}
+	sep := directive.Sep
+	if sep == "" {
+		sep = ","
+	}
+	return convert.Values(fieldValue, splitValue(fieldValue, raw, sep))
+}
+
+// validate runs dst's "val" directives against the configured registry, in
+// accumulate mode when all errors are being collected, and names the variable
+// behind each failure it can.
+func (b *binder) validate(dst any) error {
+	reg := b.cfg.reg
+	var err error
+	switch {
+	case reg != nil && b.all:
+		err = reg.ValidateStructAll(dst)
+	case reg != nil:
+		err = reg.ValidateStruct(dst)
+	case b.all:
+		err = valex.ValidateStructAll(dst)
+	default:
+		err = valex.ValidateStruct(dst)
+	}
+	return b.wrap(err)
+}
+
+// wrap rewrites the validation failures in err that belong to a bound field as
+// *VarErrors naming its variable.
+func (b *binder) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := j.Unwrap()
+		wrapped := make([]error, len(errs))
+		for i, e := range errs {
+			wrapped[i] = b.wrap(e)
+		}
+		return errors.Join(wrapped...)
+	}
+	var pe *valex.ProcessError
+	if errors.As(err, &pe) {
+		if name, ok := b.vars[pe.FieldPath]; ok {
+			return &VarError{Var: name, Field: pe.FieldPath, Err: err}
+		}
+	}
+	return err
+}
+
+// structValue returns the struct to descend into for a struct or struct-pointer
+// field, allocating a nil pointer when alloc is set. Types that decode
+// themselves from text, such as time.Time, are bound as values instead.
+func structValue(v reflect.Value, alloc bool) (reflect.Value, bool) {
+	if convert.IsText(v.Type()) {
+		return reflect.Value{}, false
+	}
+	switch {
+	case v.Kind() == reflect.Struct:
+		return v, true
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct && !convert.IsText(v.Type().Elem()):
+		if v.IsNil() {
+			if !alloc {
+				return reflect.Value{}, false
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return v.Elem(), true
+	}
+	return reflect.Value{}, false
+}
+
+// splitValue splits raw on sep for a slice field, trimming each element.
+func splitValue(v reflect.Value, raw, sep string) []string {
+	if v.Kind() != reflect.Slice || convert.IsText(v.Type()) {
+		return []string{raw}
+	}
+	parts := strings.Split(raw, sep)
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
+
+// parseTag parses a "env" tag value into directive, rejecting required
+// together with default.
+func parseTag(tagVal string, directive *fieldDirective) error {
+	args, err := scan.Options(tagVal, "name")
+	if err != nil {
+		return err
+	}
+	if err := tagex.ProcessParams(directive, args); err != nil {
+		return err
+	}
+	if directive.Required && directive.DefaultValue != "" {
+		return errors.New("required and default are both set; the default never applies")
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package env_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/env"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+type dbConfig struct {
+	Host string `env:"HOST,required=true"`
+	Port int    `env:",default=5432" val:"rangeint,min=1,max=65535"`
+}
+
+type config struct {
+	MaxConns int           `env:"" val:"rangeint,min=1,max=100"`
+	Timeout  time.Duration `env:"TIMEOUT,default=5s"`
+	Hosts    []string      `env:"HOSTS,sep=;"`
+	Ports    []int         `env:"PORTS"`
+	Greeting string        `env:"GREETING,default='hello, world'"`
+	Bind     net.IP        `env:"BIND"`
+	Debug    *bool         `env:"DEBUG"`
+	DB       dbConfig      `env:",prefix=DB_"`
+	Cache    *dbConfig     `env:",prefix=CACHE_"`
+	Ignored  string
+}
+
+func newRegistry() *valex.Registry {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+	return reg
+}
+
+func TestBind(t *testing.T) {
+	vars := map[string]string{
+		"APP_MAX_CONNS":  "10",
+		"APP_HOSTS":      "a.example; b.example",
+		"APP_PORTS":      "80,443",
+		"APP_BIND":       "127.0.0.1",
+		"APP_DEBUG":      "true",
+		"APP_DB_HOST":    "db.example",
+		"APP_CACHE_HOST": "cache.example",
+		"APP_CACHE_PORT": "6379",
+		"IGNORED":        "x",
+	}
+	var cfg config
+	if err := env.Bind(&cfg, env.WithPrefix("APP_"), env.WithMap(vars)); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+
+	if cfg.MaxConns != 10 || cfg.Timeout != 5*time.Second || cfg.Greeting != "hello, world" {
+		t.Errorf("unexpected scalars: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a.example", "b.example"}) {
+		t.Errorf("Hosts = %q", cfg.Hosts)
+	}
+	if !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+		t.Errorf("Ports = %v", cfg.Ports)
+	}
+	if !cfg.Bind.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("Bind = %v", cfg.Bind)
+	}
+	if cfg.Debug == nil || !*cfg.Debug {
+		t.Errorf("Debug = %v", cfg.Debug)
+	}
+	if cfg.DB != (dbConfig{Host: "db.example", Port: 5432}) {
+		t.Errorf("DB = %+v", cfg.DB)
+	}
+	if cfg.Cache == nil || *cfg.Cache != (dbConfig{Host: "cache.example", Port: 6379}) {
+		t.Errorf("Cache = %+v", cfg.Cache)
+	}
+	if cfg.Ignored != "" {
+		t.Errorf("untagged field was bound: %q", cfg.Ignored)
+	}
+}
+
+func TestBindNames(t *testing.T) {
+	type names struct {
+		MaxConns int    `env:""`
+		HTTPPort int    `env:""`
+		DBURL    string `env:""`
+		TLS2Cert string `env:""`
+		Explicit string `env:"OTHER"`
+	}
+	var seen []string
+	lookup := func(name string) (string, bool) {
+		seen = append(seen, name)
+		return "", false
+	}
+	if err := env.Bind(&names{}, env.WithLookup(lookup)); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	want := []string{"MAX_CONNS", "HTTP_PORT", "DBURL", "TLS2_CERT", "OTHER"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("looked up %q, want %q", seen, want)
+	}
+}
+
+func TestBindEmptyIsMissing(t *testing.T) {
+	type input struct {
+		Port int `env:"PORT,default=8080"`
+	}
+	var in input
+	if err := env.Bind(&in, env.WithMap(map[string]string{"PORT": ""})); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if in.Port != 8080 {
+		t.Errorf("Port = %d, want the default", in.Port)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	type input struct {
+		Host string `env:"HOST,required=true"`
+		Port int    `env:"PORT"`
+	}
+
+	err := env.Bind(&input{}, env.WithMap(map[string]string{"PORT": "abc"}))
+	var ve *env.VarError
+	if !errors.As(err, &ve) || ve.Var != "HOST" || ve.Field != "Host" || !errors.Is(err, env.ErrRequired) {
+		t.Fatalf("want the required HOST error first, got %v", err)
+	}
+
+	err = env.Bind(&input{}, env.WithMap(map[string]string{"HOST": "h", "PORT": "abc"}))
+	if !errors.As(err, &ve) || ve.Var != "PORT" || ve.Field != "Port" {
+		t.Fatalf("want a PORT conversion error, got %v", err)
+	}
+
+	if err := env.Bind(input{}); err == nil {
+		t.Error("expected an error for a non-pointer target")
+	}
+
+	type badTag struct {
+		Port int `env:"PORT,default"`
+	}
+	err = env.Bind(&badTag{}, env.WithMap(nil))
+	if err == nil || errors.As(err, &ve) {
+		t.Errorf("want a plain tag error, got %v", err)
+	}
+
+	type requiredDefault struct {
+		DB struct {
+			Port int `env:"PORT,required=true,default=5432"`
+		}
+	}
+	err = env.Bind(&requiredDefault{}, env.WithMap(map[string]string{"PORT": "1"}))
+	if err == nil || errors.As(err, &ve) || !strings.Contains(err.Error(), "field DB.Port") {
+		t.Errorf("want a tag error naming DB.Port, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	reg := newRegistry()
+	vars := map[string]string{"MAX_CONNS": "500", "DB_HOST": "db", "CACHE_HOST": "cache"}
+
+	err := env.Validate(&config{}, env.WithMap(vars), env.WithRegistry(reg))
+	var ve *env.VarError
+	if !errors.As(err, &ve) || ve.Var != "MAX_CONNS" {
+		t.Fatalf("want a MAX_CONNS error, got %v", err)
+	}
+	var pe *valex.ProcessError
+	if !errors.As(err, &pe) || pe.FieldPath != "MaxConns" {
+		t.Fatalf("want the validation error underneath, got %v", err)
+	}
+
+	vars["MAX_CONNS"] = "50"
+	if err := env.Validate(&config{}, env.WithMap(vars), env.WithRegistry(reg)); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	reg := newRegistry()
+	vars := map[string]string{
+		"MAX_CONNS":  "0",     // invalid
+		"PORTS":      "80,x",  // does not convert
+		"DB_PORT":    "70000", // invalid
+		"CACHE_HOST": "cache",
+	}
+
+	err := env.ValidateAll(&config{}, env.WithMap(vars), env.WithRegistry(reg))
+	if err == nil {
+		t.Fatal("expected failures")
+	}
+	for _, name := range []string{"MAX_CONNS", "PORTS", "DB_HOST", "DB_PORT"} {
+		if !strings.Contains(err.Error(), `"`+name+`"`) {
+			t.Errorf("error does not mention %s: %v", name, err)
+		}
+	}
+
+	fe := env.FieldErrors(err)
+	if len(fe) != 4 {
+		t.Fatalf("want 4 field errors, got %d: %v", len(fe), fe)
+	}
+	if !errors.Is(fe["DB.Host"], env.ErrRequired) {
+		t.Errorf("DB.Host = %v", fe["DB.Host"])
+	}
+	var pe *valex.ProcessError
+	if !errors.As(fe["DB.Port"], &pe) {
+		t.Errorf("DB.Port should be a validation error, got %v", fe["DB.Port"])
+	}
+	if errors.As(fe["Ports"], &pe) {
+		t.Errorf("Ports should be a binding error, got %v", fe["Ports"])
+	}
+
+	if env.FieldErrors(nil) != nil {
+		t.Error("FieldErrors(nil) should be nil")
+	}
+}
+
+func TestValidateAllBindingWins(t *testing.T) {
+	type input struct {
+		Size int `env:"SIZE" val:"rangeint,min=1,max=10"`
+	}
+	err := env.ValidateAll(&input{}, env.WithMap(map[string]string{"SIZE": "big"}), env.WithRegistry(newRegistry()))
+	fe := env.FieldErrors(err)
+	var pe *valex.ProcessError
+	if len(fe) != 1 || fe["Size"] == nil || errors.As(fe["Size"], &pe) {
+		t.Errorf("want the binding error for Size, got %v", fe)
+	}
+}
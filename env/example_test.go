@@ -0,0 +1,39 @@
+package env_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/env"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+// ValidateAll binds every tagged variable and validates the result, reporting
+// each missing or invalid variable by name.
+func ExampleValidateAll() {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+
+	type Config struct {
+		Port    int           `env:"PORT,default=8080" val:"rangeint,min=1,max=65535"`
+		Timeout time.Duration `env:"TIMEOUT,default=5s"`
+		Token   string        `env:"TOKEN,required=true"`
+	}
+
+	vars := map[string]string{"APP_PORT": "99999"}
+	var cfg Config
+	err := env.ValidateAll(&cfg, env.WithPrefix("APP_"), env.WithMap(vars), env.WithRegistry(reg))
+	fmt.Println(env.FieldErrors(err)["Token"])
+	fmt.Println(len(env.FieldErrors(err)), "variables failed")
+
+	vars = map[string]string{"APP_TOKEN": "secret"}
+	cfg = Config{}
+	if err := env.ValidateAll(&cfg, env.WithPrefix("APP_"), env.WithMap(vars), env.WithRegistry(reg)); err == nil {
+		fmt.Println(cfg.Port, cfg.Timeout)
+	}
+	// Output:
+	// env var "APP_TOKEN": variable is required
+	// 2 variables failed
+	// 8080 5s
+}
@@ -3,6 +3,7 @@ package valex_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/tedla-brandsema/tagex"
 	"github.com/tedla-brandsema/valex"
@@ -96,3 +97,39 @@ func ExampleRegisterDirective() {
 	// <nil>
 	// tag "val" error: directive processing field "Seats" directive "even": value 3 is not even
 }
+
+// WithClock pins the time that time-relative directives compare against, so
+// validation that depends on "now" is deterministic.
+func ExampleWithClock() {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	reg := valex.NewRegistry(valex.WithClock(valex.ClockFunc(func() time.Time { return now })))
+	valex.MustRegisterDirectiveTo(reg, &validators.FutureValidator{})
+
+	type Booking struct {
+		Start time.Time `val:"future"`
+	}
+
+	fmt.Println(reg.ValidateStruct(&Booking{Start: now.Add(time.Hour)}))
+	fmt.Println(reg.ValidateStruct(&Booking{Start: now.Add(-time.Hour)}))
+	// Output:
+	// <nil>
+	// tag "val" error: directive processing field "Start" directive "future": time 2024-05-15T11:00:00Z is not in the future
+}
+
+// Groups selects per-scenario rules: one struct serves both create and update,
+// and segments without a group prefix run in both.
+func ExampleGroups() {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.NonEmptyStringValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.EmailValidator{})
+
+	type User struct {
+		Email string `val:"create:!empty;update:omitempty;email"`
+	}
+
+	fmt.Println(reg.ValidateStructWith(&User{}, valex.Groups("create")))
+	fmt.Println(reg.ValidateStructWith(&User{}, valex.Groups("update")))
+	// Output:
+	// tag "val" error: directive processing field "Email" directive "!empty": string is empty
+	// <nil>
+}
@@ -32,6 +32,7 @@ func (*lowerDirective) Handle(s string) (string, error) {
 	return strings.ToLower(s), nil
 }
 
+//valexvet:directive trim lower
 func init() {
 	valex.MustRegisterDirective(&trimDirective{})
 	valex.MustRegisterDirective(&lowerDirective{})
@@ -22,6 +22,7 @@ func (*EvenDirective) Handle(n int) (int, error) {
 	return n, nil
 }
 
+//valexvet:directive even
 func main() {
 	valex.MustRegisterDirective(&EvenDirective{})
 
@@ -0,0 +1,13 @@
+package valex
+
+import "testing"
+
+// SwapParamCache gives t an empty parameter cache of the current size and
+// puts the process-wide one back, with its entries, counters, size, and hook,
+// when t ends.
+func SwapParamCache(t testing.TB) {
+	t.Helper()
+	saved := paramCache
+	paramCache = newLRUCache(saved.stats().Capacity)
+	t.Cleanup(func() { paramCache = saved })
+}
@@ -0,0 +1,149 @@
+package valex
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UnknownFieldError is returned by ValidateFields for a path that does not
+// name a field of the struct, such as a misspelled field name.
+type UnknownFieldError struct {
+	Path string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("valex: no field %q", e.Path)
+}
+
+// ValidateFields is like ValidateStruct but validates only the fields at the
+// given paths and everything under them, leaving the rest of data unchecked —
+// for example the fields a PATCH request sets. Paths are written the way
+// errors report them: "Email", "Address.Zip", or "Items[2].SKU". Only segments
+// without a group prefix run; to select groups as well, pass Fields and Groups
+// to ValidateStructWith. Lifecycle hooks run as for ValidateStruct. A path that
+// does not name a field of data returns an *UnknownFieldError; with no paths,
+// no field is validated.
+func (r *Registry) ValidateFields(data any, paths ...string) error {
+	return r.validate(data, nil, nil, scope{mask: newFieldMask(paths)})
+}
+
+// ValidateFieldsAll is like ValidateFields but does not stop at the first
+// failure, in the way of ValidateStructAll.
+func (r *Registry) ValidateFieldsAll(data any, paths ...string) error {
+	errs := make([]error, 0)
+	return r.validate(data, &errs, nil, scope{mask: newFieldMask(paths)})
+}
+
+// ValidateFields validates the fields at the given paths using the default
+// registry; see Registry.ValidateFields.
+func ValidateFields(data any, paths ...string) error {
+	return defaultRegistry.ValidateFields(data, paths...)
+}
+
+// ValidateFieldsAll is like ValidateFields but does not stop at the first
+// failure; see Registry.ValidateFieldsAll.
+func ValidateFieldsAll(data any, paths ...string) error {
+	return defaultRegistry.ValidateFieldsAll(data, paths...)
+}
+
+// Fields limits a ValidateStructWith call to the fields at the given paths and
+// everything under them, as ValidateFields does. Several Fields options add up.
+func Fields(paths ...string) ValidateOption {
+	return func(c *validateConfig) {
+		c.mask = append(newFieldMask(c.mask), paths...)
+	}
+}
+
+// fieldMask lists the field paths the walker validates. A nil mask validates
+// every field; an empty one, none.
+type fieldMask []string
+
+func newFieldMask(paths []string) fieldMask {
+	return append(fieldMask{}, paths...)
+}
+
+// check returns an *UnknownFieldError for the first path that is not a field
+// of t.
+func (m fieldMask) check(t reflect.Type) error {
+	for _, p := range m {
+		if !fieldPathOf(t, p) {
+			return &ProcessError{Stage: StageInput, Cause: &UnknownFieldError{Path: p}}
+		}
+	}
+	return nil
+}
+
+// selects reports whether path is one of m's paths or under one.
+func (m fieldMask) selects(path string) bool {
+	for _, p := range m {
+		if path == p || under(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// leadsTo reports whether one of m's paths is under path.
+func (m fieldMask) leadsTo(path string) bool {
+	for _, p := range m {
+		if under(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// under reports whether path is a field or element below parent.
+func under(path, parent string) bool {
+	return strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[")
+}
+
+// fieldPathOf reports whether path names a field of the struct type t, or an
+// element of one, the way the walker builds paths: field names joined by dots,
+// with [index] or [key] for slice, array, and map elements.
+func fieldPathOf(t reflect.Type, path string) bool {
+	name := true // a field name comes next, not an index
+	for {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if name {
+			i := strings.IndexAny(path, ".[")
+			if i < 0 {
+				i = len(path)
+			}
+			if t.Kind() != reflect.Struct {
+				return false
+			}
+			f, ok := t.FieldByName(path[:i])
+			if !ok || f.PkgPath != "" || len(f.Index) != 1 {
+				return false
+			}
+			t, path = f.Type, path[i:]
+		} else {
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return false
+			}
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+				t = t.Elem()
+			default:
+				return false
+			}
+			path = path[end+1:]
+		}
+		if path == "" {
+			return true
+		}
+		switch path[0] {
+		case '.':
+			path, name = path[1:], true
+		case '[':
+			name = false
+		default:
+			return false
+		}
+	}
+}
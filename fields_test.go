@@ -0,0 +1,112 @@
+package valex_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+	_ "github.com/tedla-brandsema/valex/internal/stub" // registers stub directives
+)
+
+type patchItem struct {
+	SKU string `val:"minlen,size=3"`
+}
+
+type patchAddress struct {
+	City string `val:"minlen,size=2"`
+	Zip  string `val:"minlen,size=5"`
+}
+
+type patchOrder struct {
+	Name    string `val:"minlen,size=5"`
+	Age     int    `val:"intrange,min=0,max=120"`
+	Address patchAddress
+	Billing *patchAddress
+	Items   []patchItem
+	Tags    map[string]patchItem
+	note    string
+}
+
+// invalidOrder fails every rule patchOrder has.
+func invalidOrder() *patchOrder {
+	return &patchOrder{
+		Name:    "Al",
+		Age:     200,
+		Address: patchAddress{City: "X", Zip: "1"},
+		Billing: &patchAddress{City: "Y", Zip: "2"},
+		Items:   []patchItem{{SKU: "ok-1"}, {SKU: "x"}},
+		Tags:    map[string]patchItem{"a": {SKU: "y"}},
+	}
+}
+
+func TestValidateFieldsAll(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"none", nil, nil},
+		{"top-level field", []string{"Name"}, []string{"Name"}},
+		{"two fields", []string{"Age", "Name"}, []string{"Age", "Name"}},
+		{"nested struct", []string{"Address"}, []string{"Address.City", "Address.Zip"}},
+		{"nested field", []string{"Address.Zip"}, []string{"Address.Zip"}},
+		{"through pointer", []string{"Billing.City"}, []string{"Billing.City"}},
+		{"whole slice", []string{"Items"}, []string{"Items[1].SKU"}},
+		{"slice element", []string{"Items[0]"}, nil},
+		{"slice element field", []string{"Items[1].SKU"}, []string{"Items[1].SKU"}},
+		{"map element", []string{"Tags[a]"}, []string{"Tags[a].SKU"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := valex.ValidateFieldsAll(invalidOrder(), tc.paths...)
+			var got []string
+			for path := range valex.FieldErrors(err) {
+				got = append(got, path)
+			}
+			sort.Strings(got)
+			if len(got) != len(tc.want) {
+				t.Fatalf("failed fields = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("failed fields = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFieldsStopsAtFirstFailure(t *testing.T) {
+	err := valex.ValidateFields(invalidOrder(), "Name", "Age")
+	if got := len(valex.FieldErrors(err)); got != 1 {
+		t.Fatalf("want 1 field error, got %d: %v", got, err)
+	}
+	if err := valex.ValidateFields(&patchOrder{Name: "Alice"}, "Name", "Address.City"); err == nil {
+		t.Fatal("empty Address.City should fail")
+	}
+	if err := valex.ValidateFields(invalidOrder(), "Items[0].SKU"); err != nil {
+		t.Fatalf("valid element: %v", err)
+	}
+}
+
+func TestValidateFieldsUnknownPath(t *testing.T) {
+	for _, path := range []string{"", "Nmae", "note", "Name.Len", "Name[0]", "Address..Zip", "Items[1", "Items[1]SKU", "Address.Zip."} {
+		err := valex.ValidateFields(&patchOrder{}, "Name", path)
+		var unknown *valex.UnknownFieldError
+		if !errors.As(err, &unknown) || unknown.Path != path {
+			t.Errorf("ValidateFields(%q) = %v, want *UnknownFieldError", path, err)
+		}
+		var pe *valex.ProcessError
+		if !errors.As(err, &pe) || pe.Stage != valex.StageInput {
+			t.Errorf("ValidateFields(%q) should fail at StageInput, got %v", path, err)
+		}
+	}
+}
+
+func TestValidateFieldsInvalidTarget(t *testing.T) {
+	var target *valex.InvalidTargetError
+	if err := valex.ValidateFields(patchOrder{}, "Name"); !errors.As(err, &target) {
+		t.Fatalf("want *InvalidTargetError, got %v", err)
+	}
+}
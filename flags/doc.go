@@ -0,0 +1,54 @@
+// Package flags validates command-line flags with the valex engine.
+//
+// It offers two ways in. Value wraps a single variable and a valex.Validator,
+// so a flag registered with flag.Var rejects bad input during flag.Parse:
+//
+//	port := 8080
+//	flag.Var(flags.NewValue(&port, portRange), "port", "listen port")
+//
+// Parse registers a flag for each "flag"-tagged field of a struct, parses the
+// arguments, and validates the struct's "val" tags, reporting every invalid
+// or missing flag in one *UsageError:
+//
+//	type Options struct {
+//		Addr    string        `flag:"addr,usage='listen address'" val:"!empty"`
+//		Workers int           `flag:",usage='worker count'" val:"rangeint,min=1,max=64"`
+//		Timeout time.Duration `flag:"timeout"`
+//		Config  string        `flag:"config,required=true"`
+//	}
+//
+//	opts := Options{Addr: ":8080", Workers: 4} // the flags' defaults
+//	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+//	flags.Parse(fs, &opts, os.Args[1:])
+//
+// # The flag tag
+//
+// The first value is the flag name; an empty name derives one from the field
+// name in kebab case (MaxConns becomes -max-conns). The remaining
+// comma-separated options are key=value pairs, single-quoted when a value
+// contains a comma or significant whitespace:
+//
+//	Option    Default  Description
+//	--------- -------- ---------------------------------------------------------
+//	(name)    derived  flag name, after any prefixes
+//	usage     -        usage text shown by PrintDefaults
+//	required  false    report ErrRequired when the flag is not given
+//	prefix    -        on a struct field, prefix for the flags inside it
+//
+// A field's value before parsing is its flag's default. Fields convert the way
+// valex/env converts variables — strings, booleans, integers, floats,
+// time.Duration, and encoding.TextUnmarshaler types — and a bool field
+// is a boolean flag. A slice field collects repeated flags (-tag a -tag b), the
+// first occurrence replacing the default.
+//
+// # Errors
+//
+// Conversion errors are parse errors, reported by flag.FlagSet.Parse as usual.
+// Validate, which Parse calls after parsing, collects the rest into a
+// *UsageError: a *FlagError for each required flag that was not given and for
+// each "val" failure on a flag-bound field, plus any other validation errors.
+// Parse prints it with the usage message and then follows the FlagSet's
+// error handling, so ExitOnError exits with status 2 as a parse error would.
+// Call Register, fs.Parse, and Validate separately to handle the error
+// yourself.
+package flags
@@ -0,0 +1,60 @@
+package flags_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/flags"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+// NewValue wraps a variable so flag.Parse rejects values that fail the
+// validator.
+func ExampleNewValue() {
+	workers := 4
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	fs.Var(flags.NewValue(&workers, valex.ValidatorFunc[int](func(n int) error {
+		if n < 1 || n > 64 {
+			return fmt.Errorf("%d is not between 1 and 64", n)
+		}
+		return nil
+	})), "workers", "worker count")
+
+	fmt.Println(fs.Parse([]string{"-workers", "100"}) != nil, workers)
+	fmt.Println(fs.Parse([]string{"-workers", "8"}) != nil, workers)
+	// Output:
+	// invalid value "100" for flag -workers: 100 is not between 1 and 64
+	// Usage of run:
+	//   -workers value
+	//     	worker count (default 4)
+	// true 4
+	// false 8
+}
+
+// Parse registers flags from a struct's "flag" tags, parses, and validates the
+// "val" tags, reporting every bad flag at once.
+func ExampleParse() {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+
+	type Options struct {
+		Workers int    `flag:",usage='worker count'" val:"rangeint,min=1,max=64"`
+		Config  string `flag:"config,usage='config file',required=true"`
+	}
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	opts := Options{Workers: 4}
+	_ = flags.Parse(fs, &opts, []string{"-workers", "0"}, flags.WithRegistry(reg))
+	// Output:
+	// flag -config: flag is required
+	// flag -workers: tag "val" error: directive processing field "Workers" directive "rangeint": value 0 is out of range [1, 64]
+	// Usage of run:
+	//   -config value
+	//     	config file
+	//   -workers value
+	//     	worker count (default 4)
+}
@@ -0,0 +1,262 @@
+package flags
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/internal/convert"
+	"github.com/tedla-brandsema/valex/internal/scan"
+)
+
+type fieldDirective struct {
+	Name     string `param:"name,required=false"`
+	Usage    string `param:"usage,required=false"`
+	Required bool   `param:"required,required=false"`
+	Prefix   string `param:"prefix,required=false"`
+}
+
+// ErrRequired is returned, wrapped in a *FlagError, when a required flag was
+// not given.
+var ErrRequired = errors.New("flag is required")
+
+// FlagError is a failure for one flag: a required flag that was not given, or
+// a "val" directive that rejected the parsed value. Unwrap returns the cause.
+type FlagError struct {
+	Flag  string // flag name, without the leading dash
+	Field string // struct field path, e.g. "DB.Port"
+	Err   error
+}
+
+func (e *FlagError) Error() string { return fmt.Sprintf("flag -%s: %v", e.Flag, e.Err) }
+func (e *FlagError) Unwrap() error { return e.Err }
+
+// UsageError aggregates every failure Validate found after parsing, one per
+// line, so a command reports all of its bad flags at once. Unwrap returns the
+// individual errors — mostly *FlagErrors — for errors.As and
+// valex.FieldErrors.
+type UsageError struct {
+	Errs []error
+}
+
+func (e *UsageError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *UsageError) Unwrap() []error { return e.Errs }
+
+// Option configures Register, Validate, and Parse.
+type Option func(*config)
+
+type config struct {
+	reg *valex.Registry // nil uses valex's default registry
+}
+
+// WithRegistry validates against reg instead of valex's default registry. A
+// nil reg uses the default.
+func WithRegistry(reg *valex.Registry) Option {
+	return func(c *config) {
+		c.reg = reg
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Parse registers dst's "flag"-tagged fields on fs, parses args, and validates
+// dst. A parse error is handled by fs as flag.FlagSet.Parse always does. A
+// validation failure is reported the same way: the *UsageError is printed to
+// fs.Output followed by the usage message, and then, depending on fs's error
+// handling, returned, passed to os.Exit(2), or panicked with.
+func Parse(fs *flag.FlagSet, dst any, args []string, opts ...Option) error {
+	if err := Register(fs, dst); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	err := Validate(fs, dst, opts...)
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		return err
+	}
+	fmt.Fprintln(fs.Output(), err)
+	if fs.Usage != nil {
+		fs.Usage()
+	} else {
+		fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+		fs.PrintDefaults()
+	}
+	switch fs.ErrorHandling() {
+	case flag.ExitOnError:
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// Register defines a flag on fs for each "flag"-tagged field of dst, which
+// must be a pointer to a struct. A field's current value is its flag's
+// default. The flags convert their arguments but do not validate them; call
+// Validate after fs.Parse. A field of a type no flag can be parsed into, such
+// as a map or a channel, is an error, and no flags are defined.
+func Register(fs *flag.FlagSet, dst any) error {
+	fields, err := collect(dst)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		t := f.value.Type()
+		if isList(t) {
+			t = t.Elem()
+		}
+		if !convert.CanText(t) {
+			return fmt.Errorf("flags: field %s: unsupported field type %s", f.path, f.value.Type())
+		}
+	}
+	for _, f := range fields {
+		fs.Var(&fieldValue{v: f.value}, f.name, f.usage)
+	}
+	return nil
+}
+
+// Validate checks dst after fs.Parse: it reports each required flag that was
+// not given and runs dst's "val" directives, collecting every failure into a
+// *UsageError. A failure on a flag-bound field is a *FlagError naming the flag.
+func Validate(fs *flag.FlagSet, dst any, opts ...Option) error {
+	fields, err := collect(dst)
+	if err != nil {
+		return err
+	}
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	var errs []error
+	names := make(map[string]string, len(fields))
+	for _, f := range fields {
+		names[f.path] = f.name
+		if f.required && !given[f.name] {
+			errs = append(errs, &FlagError{Flag: f.name, Field: f.path, Err: ErrRequired})
+		}
+	}
+
+	cfg := newConfig(opts)
+	var valErr error
+	if cfg.reg != nil {
+		valErr = cfg.reg.ValidateStructAll(dst)
+	} else {
+		valErr = valex.ValidateStructAll(dst)
+	}
+	errs = appendWrapped(errs, valErr, names)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &UsageError{Errs: errs}
+}
+
+// appendWrapped appends the leaves of err to errs, wrapping each validation
+// failure on a flag-bound field as a *FlagError.
+func appendWrapped(errs []error, err error, names map[string]string) []error {
+	if err == nil {
+		return errs
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			errs = appendWrapped(errs, e, names)
+		}
+		return errs
+	}
+	var pe *valex.ProcessError
+	if errors.As(err, &pe) {
+		if name, ok := names[pe.FieldPath]; ok {
+			return append(errs, &FlagError{Flag: name, Field: pe.FieldPath, Err: err})
+		}
+	}
+	return append(errs, err)
+}
+
+// flagField is one "flag"-tagged struct field.
+type flagField struct {
+	name, path, usage string
+	required          bool
+	value             reflect.Value
+}
+
+// collect walks dst's "flag"-tagged fields. Struct fields are descended into
+// with their tag's prefix option appended to the enclosing prefix.
+func collect(dst any) ([]flagField, error) {
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a pointer to a struct but got %T", dst)
+	}
+	var fields []flagField
+	err := collectStruct(val.Elem(), "", "", &fields)
+	return fields, err
+}
+
+func collectStruct(val reflect.Value, prefix, path string, fields *[]flagField) error {
+	for n := 0; n < val.NumField(); n++ {
+		field := val.Type().Field(n)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldValue := val.Field(n)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tagVal, tagged := field.Tag.Lookup("flag")
+		if !tagged {
+			continue
+		}
+		var directive fieldDirective
+		if err := parseTag(tagVal, &directive); err != nil {
+			return fmt.Errorf("flags: field %s: %w", fieldPath, err)
+		}
+
+		if fieldValue.Kind() == reflect.Struct && !convert.IsText(fieldValue.Type()) {
+			if err := collectStruct(fieldValue, prefix+directive.Prefix, fieldPath, fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := directive.Name
+		if name == "" {
+			name = strings.ToLower(strings.Join(scan.Words(field.Name), "-"))
+		}
+		*fields = append(*fields, flagField{
+			name:     prefix + name,
+			path:     fieldPath,
+			usage:    directive.Usage,
+			required: directive.Required,
+			value:    fieldValue,
+		})
+	}
+	return nil
+}
+
+// parseTag parses a "flag" tag value into directive.
+func parseTag(tagVal string, directive *fieldDirective) error {
+	args, err := scan.Options(tagVal, "name")
+	if err != nil {
+		return err
+	}
+	return tagex.ProcessParams(directive, args)
+}
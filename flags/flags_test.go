@@ -0,0 +1,193 @@
+package flags_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/flags"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+var positive = valex.ValidatorFunc[int](func(n int) error {
+	if n <= 0 {
+		return errors.New("must be positive")
+	}
+	return nil
+})
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+		ok   bool
+	}{
+		{nil, 7, true},
+		{[]string{"-n", "3"}, 3, true},
+		{[]string{"-n", "0"}, 7, false},
+		{[]string{"-n", "x"}, 7, false},
+	}
+	for _, tt := range tests {
+		n := 7
+		fs := newFlagSet()
+		fs.Var(flags.NewValue(&n, positive), "n", "")
+		err := fs.Parse(tt.args)
+		if (err == nil) != tt.ok || n != tt.want {
+			t.Errorf("%q: expected ok=%v n=%d, got n=%d (err: %v)", tt.args, tt.ok, tt.want, n, err)
+		}
+	}
+}
+
+func TestValueSliceAndBool(t *testing.T) {
+	atMostTwo := valex.ValidatorFunc[[]string](func(s []string) error {
+		if len(s) > 2 {
+			return errors.New("at most two")
+		}
+		return nil
+	})
+	tags := []string{"default"}
+	verbose := false
+	fs := newFlagSet()
+	fs.Var(flags.NewValue(&tags, atMostTwo), "tag", "")
+	fs.Var(flags.NewValue(&verbose, valex.ValidatorFunc[bool](func(bool) error { return nil })), "v", "")
+
+	if err := fs.Parse([]string{"-tag", "a", "-v", "-tag", "b"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"a", "b"}) || !verbose {
+		t.Errorf("got tags=%q verbose=%v", tags, verbose)
+	}
+	if got := fs.Lookup("tag").Value.String(); got != "a,b" {
+		t.Errorf("String() = %q", got)
+	}
+
+	fs = newFlagSet()
+	fs.Var(flags.NewValue(&tags, atMostTwo), "tag", "")
+	if err := fs.Parse([]string{"-tag", "a", "-tag", "b", "-tag", "c"}); err == nil {
+		t.Error("expected the third -tag to fail validation")
+	}
+}
+
+type dbOptions struct {
+	Host string `flag:"host,required=true"`
+	Port int    `flag:",usage='database port'" val:"rangeint,min=1,max=65535"`
+}
+
+type options struct {
+	MaxConns int           `flag:"" val:"rangeint,min=1,max=100"`
+	Timeout  time.Duration `flag:"timeout"`
+	Tags     []string      `flag:"tag"`
+	Verbose  bool          `flag:"v"`
+	DB       dbOptions     `flag:",prefix=db-"`
+	Ignored  string
+}
+
+func newRegistry() *valex.Registry {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+	return reg
+}
+
+func TestParse(t *testing.T) {
+	opts := options{MaxConns: 10, Timeout: time.Second, DB: dbOptions{Port: 5432}}
+	args := []string{"-max-conns", "20", "-timeout", "2s", "-tag", "a", "-tag", "b", "-v", "-db-host", "db", "rest"}
+	fs := newFlagSet()
+	if err := flags.Parse(fs, &opts, args, flags.WithRegistry(newRegistry())); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := options{MaxConns: 20, Timeout: 2 * time.Second, Tags: []string{"a", "b"}, Verbose: true, DB: dbOptions{Host: "db", Port: 5432}}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("got %+v, want %+v", opts, want)
+	}
+	if !reflect.DeepEqual(fs.Args(), []string{"rest"}) {
+		t.Errorf("Args() = %q", fs.Args())
+	}
+	if f := fs.Lookup("db-port"); f == nil || f.Usage != "database port" || f.DefValue != "5432" {
+		t.Errorf("db-port flag = %+v", f)
+	}
+	if fs.Lookup("ignored") != nil {
+		t.Error("untagged field was registered")
+	}
+}
+
+func TestParseUsageError(t *testing.T) {
+	var out bytes.Buffer
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(&out)
+
+	opts := options{MaxConns: 10, DB: dbOptions{Port: 5432}}
+	err := flags.Parse(fs, &opts, []string{"-max-conns", "0", "-db-port", "70000"}, flags.WithRegistry(newRegistry()))
+
+	var usageErr *flags.UsageError
+	if !errors.As(err, &usageErr) || len(usageErr.Errs) != 3 {
+		t.Fatalf("want a *UsageError with 3 errors, got %v", err)
+	}
+	var fe *flags.FlagError
+	if !errors.As(usageErr.Errs[0], &fe) || fe.Flag != "db-host" || !errors.Is(fe, flags.ErrRequired) {
+		t.Errorf("want the required -db-host first, got %v", usageErr.Errs[0])
+	}
+	for _, e := range usageErr.Errs[1:] {
+		var pe *valex.ProcessError
+		if !errors.As(e, &fe) || !errors.As(e, &pe) {
+			t.Errorf("want a *FlagError wrapping a validation error, got %v", e)
+		}
+	}
+	if fields := valex.FieldErrors(err); len(fields) != 2 || fields["DB.Port"] == nil {
+		t.Errorf("FieldErrors = %v", fields)
+	}
+	for _, want := range []string{"flag -max-conns:", "flag -db-port:", "Usage of serve:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestParseConversionError(t *testing.T) {
+	opts := options{}
+	err := flags.Parse(newFlagSet(), &opts, []string{"-timeout", "soon"})
+	var usageErr *flags.UsageError
+	if err == nil || errors.As(err, &usageErr) {
+		t.Errorf("want a plain parse error, got %v", err)
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	if err := flags.Register(newFlagSet(), options{}); err == nil {
+		t.Error("expected an error for a non-pointer target")
+	}
+	type bad struct {
+		N int `flag:"n,usage"`
+	}
+	if err := flags.Register(newFlagSet(), &bad{}); err == nil {
+		t.Error("expected an error for a malformed tag")
+	}
+	type unsupported struct {
+		N int            `flag:"n"`
+		M map[string]int `flag:"m"`
+	}
+	fs := newFlagSet()
+	if err := flags.Register(fs, &unsupported{}); err == nil || !strings.Contains(err.Error(), "field M") {
+		t.Errorf("expected an error for a map field, got %v", err)
+	}
+	if fs.Lookup("n") != nil {
+		t.Error("expected no flags to be defined after the error")
+	}
+	type chanField struct {
+		C []chan int `flag:"c"`
+	}
+	if err := flags.Register(newFlagSet(), &chanField{}); err == nil {
+		t.Error("expected an error for a slice of channels")
+	}
+}
@@ -0,0 +1,144 @@
+package flags
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/internal/convert"
+)
+
+// Value is a flag.Value that parses its argument into a T and stores it only if
+// it passes a valex.Validator, so flag.Parse rejects bad input with the
+// validator's error:
+//
+//	var port int = 8080
+//	flag.Var(flags.NewValue(&port, portValidator), "port", "listen port")
+//
+// A slice T collects repeated flags: each occurrence appends one element, the
+// first replacing the default, and the whole slice is validated. A bool T is a
+// boolean flag, settable as -name without a value.
+type Value[T any] struct {
+	p         *T
+	validator valex.Validator[T]
+	set       bool
+}
+
+// NewValue returns a Value storing into p, whose current value is the flag's
+// default, and validating with v.
+func NewValue[T any](p *T, v valex.Validator[T]) *Value[T] {
+	return &Value[T]{p: p, validator: v}
+}
+
+// Set parses s and stores the result if it passes validation.
+func (f *Value[T]) Set(s string) error {
+	if f.validator == nil {
+		return valex.ErrNoValidator
+	}
+	var val T
+	rv := reflect.ValueOf(&val).Elem()
+	if isList(rv.Type()) {
+		if f.set {
+			rv.Set(reflect.ValueOf(*f.p))
+		}
+		if err := appendText(rv, s); err != nil {
+			return err
+		}
+	} else if err := convert.Text(rv, s); err != nil {
+		return err
+	}
+	if err := f.validator.Validate(val); err != nil {
+		return err
+	}
+	*f.p = val
+	f.set = true
+	return nil
+}
+
+// String formats the stored value; slice elements are comma-separated.
+func (f *Value[T]) String() string {
+	if f == nil || f.p == nil {
+		return ""
+	}
+	return format(reflect.ValueOf(f.p).Elem())
+}
+
+// Get returns the stored value, implementing flag.Getter.
+func (f *Value[T]) Get() any {
+	return *f.p
+}
+
+// IsBoolFlag reports whether T is a bool, so the flag can be given without a
+// value.
+func (f *Value[T]) IsBoolFlag() bool {
+	return isBool(reflect.TypeFor[T]())
+}
+
+// fieldValue is the flag.Value Register installs for a struct field. It only
+// converts; the field's "val" directives run after parsing.
+type fieldValue struct {
+	v   reflect.Value
+	set bool
+}
+
+func (f *fieldValue) Set(s string) error {
+	if !isList(f.v.Type()) {
+		return convert.Text(f.v, s)
+	}
+	if !f.set {
+		f.v.Set(reflect.Zero(f.v.Type()))
+		f.set = true
+	}
+	return appendText(f.v, s)
+}
+
+func (f *fieldValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	return format(f.v)
+}
+
+func (f *fieldValue) Get() any { return f.v.Interface() }
+
+func (f *fieldValue) IsBoolFlag() bool { return isBool(f.v.Type()) }
+
+// isList reports whether t collects repeated flags: a slice that does not
+// decode itself from text, as net.IP does.
+func isList(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !convert.IsText(t)
+}
+
+func isBool(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Bool
+}
+
+// appendText parses s as one element and appends it to the slice v, copying
+// the backing array so a shared default is never written through.
+func appendText(v reflect.Value, s string) error {
+	elem := reflect.New(v.Type().Elem()).Elem()
+	if err := convert.Text(elem, s); err != nil {
+		return err
+	}
+	grown := reflect.MakeSlice(v.Type(), 0, v.Len()+1)
+	grown = reflect.AppendSlice(grown, v)
+	v.Set(reflect.Append(grown, elem))
+	return nil
+}
+
+// format renders v for flag defaults and String; it reports an unformattable
+// value as empty rather than failing.
+func format(v reflect.Value) string {
+	if !isList(v.Type()) {
+		s, _ := convert.Format(v)
+		return s
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i], _ = convert.Format(v.Index(i))
+	}
+	return strings.Join(parts, ",")
+}
@@ -20,6 +20,9 @@
 // parameters, so GET requests are supported. Validate is a convenience wrapper
 // that parses, binds, validates, and returns a *Error carrying an HTTP status
 // code. Bind binds url.Values without validating, for use outside HTTP handlers.
+// For partial updates such as PATCH, ValidatePresent and ValidatePresentAll bind
+// and validate only the fields whose keys the request carries, so required and
+// default do not apply to omitted fields.
 //
 //	func handler(w http.ResponseWriter, r *http.Request) {
 //		var in Signup
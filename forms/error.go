@@ -96,6 +96,39 @@ func ValidateAllWith(r *http.Request, dst any, reg *valex.Registry) error {
 	return validator.ValidateAll(dst)
 }
 
+// ValidatePresent parses the request and binds and validates only the fields of
+// dst whose keys it carries, against the default registry — for partial updates
+// such as PATCH. See Validator.ValidatePresent.
+func ValidatePresent(r *http.Request, dst any) error {
+	return ValidatePresentWith(r, dst, nil)
+}
+
+// ValidatePresentWith is like ValidatePresent but validates against reg instead
+// of the default registry. A nil reg uses the default.
+func ValidatePresentWith(r *http.Request, dst any, reg *valex.Registry) error {
+	validator, err := NewWith(r, reg)
+	if err != nil {
+		return &Error{status: http.StatusBadRequest, Err: err}
+	}
+	return validator.ValidatePresent(dst)
+}
+
+// ValidatePresentAll is like ValidatePresent but collects every binding and
+// validation failure instead of stopping at the first.
+func ValidatePresentAll(r *http.Request, dst any) error {
+	return ValidatePresentAllWith(r, dst, nil)
+}
+
+// ValidatePresentAllWith is like ValidatePresentAll but validates against reg
+// instead of the default registry. A nil reg uses the default.
+func ValidatePresentAllWith(r *http.Request, dst any, reg *valex.Registry) error {
+	validator, err := NewWith(r, reg)
+	if err != nil {
+		return &Error{status: http.StatusBadRequest, Err: err}
+	}
+	return validator.ValidatePresentAll(dst)
+}
+
 // FieldErrors flattens err — typically from ValidateAll — into a map from struct
 // field path to the error for that field, merging binding and validation
 // failures into one view. When a field fails both (for example a non-numeric
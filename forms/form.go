@@ -9,17 +9,10 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/tedla-brandsema/tagex"
 	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/internal/formtag"
 )
 
-type fieldDirective struct {
-	Key          string `param:"key,required=false"`
-	Max          int    `param:"max,default=1"`
-	Required     bool   `param:"required,required=false"`
-	DefaultValue string `param:"default,required=false"`
-}
-
 // ErrFieldRequired is returned when a required form field is missing or empty.
 var ErrFieldRequired = errors.New("field is required")
 
@@ -51,7 +44,7 @@ func NewWith(r *http.Request, reg *valex.Registry) (*Validator, error) {
 // Validate binds form values into dst and validates its "val" tags. It returns
 // nil on success, or an *Error carrying an HTTP status code on failure (see Status).
 func (v *Validator) Validate(dst any) error {
-	if err := bindFormValues(dst, v.rawValues); err != nil {
+	if err := bindFormValues(dst, v.rawValues, nil); err != nil {
 		return &Error{status: Status(err), Err: err}
 	}
 	if err := v.validate(dst); err != nil {
@@ -78,7 +71,7 @@ func (v *Validator) ValidateAll(dst any) error {
 		return &Error{status: Status(err), Err: err}
 	}
 	var parts []error
-	if bindErr := bindFormValuesAll(dst, v.rawValues); bindErr != nil {
+	if bindErr := bindFormValuesAll(dst, v.rawValues, nil); bindErr != nil {
 		parts = append(parts, bindErr)
 	}
 	if valErr := v.validateAll(dst); valErr != nil {
@@ -100,10 +93,65 @@ func (v *Validator) validateAll(dst any) error {
 	return valex.ValidateStructAll(dst)
 }
 
+// ValidatePresent is like Validate but for partial updates such as a PATCH:
+// it binds and validates only the "field"-tagged fields whose keys are present
+// in the request, leaving the others of dst as they are. An omitted field is
+// not reported as required and does not receive its default; a key sent with
+// an empty value is bound as usual, so it can still fail required. Fields
+// without a "field" tag are not validated.
+func (v *Validator) ValidatePresent(dst any) error {
+	present := make([]string, 0)
+	if err := bindFormValues(dst, v.rawValues, &present); err != nil {
+		return &Error{status: Status(err), Err: err}
+	}
+	if err := v.validateFields(dst, present); err != nil {
+		return &Error{status: Status(err), Err: err}
+	}
+	return nil
+}
+
+// validateFields runs the "val" directives of the fields at paths.
+func (v *Validator) validateFields(dst any, paths []string) error {
+	if v.reg != nil {
+		return v.reg.ValidateFields(dst, paths...)
+	}
+	return valex.ValidateFields(dst, paths...)
+}
+
+// ValidatePresentAll is like ValidatePresent but collects every binding and
+// validation failure, in the way of ValidateAll.
+func (v *Validator) ValidatePresentAll(dst any) error {
+	if _, err := pointerStruct(dst); err != nil {
+		return &Error{status: Status(err), Err: err}
+	}
+	var parts []error
+	present := make([]string, 0)
+	if bindErr := bindFormValuesAll(dst, v.rawValues, &present); bindErr != nil {
+		parts = append(parts, bindErr)
+	}
+	if valErr := v.validateFieldsAll(dst, present); valErr != nil {
+		parts = append(parts, valErr)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	joined := errors.Join(parts...)
+	return &Error{status: Status(joined), Err: joined}
+}
+
+// validateFieldsAll runs the "val" directives of the fields at paths in
+// accumulate mode.
+func (v *Validator) validateFieldsAll(dst any, paths []string) error {
+	if v.reg != nil {
+		return v.reg.ValidateFieldsAll(dst, paths...)
+	}
+	return valex.ValidateFieldsAll(dst, paths...)
+}
+
 // Bind binds url.Values into a struct pointer using "field" tags, stopping at
 // the first error.
 func Bind(dst any, values url.Values) error {
-	return bindFormValues(dst, values)
+	return bindFormValues(dst, values, nil)
 }
 
 // bindError is a field-scoped binding failure (type mismatch, too many values,
@@ -117,24 +165,25 @@ type bindError struct {
 func (e *bindError) Error() string { return fmt.Sprintf("form field %q: %v", e.Field, e.Err) }
 func (e *bindError) Unwrap() error { return e.Err }
 
-// bindFormValues binds into dst, stopping at the first field error.
-func bindFormValues(dst any, values url.Values) error {
+// bindFormValues binds into dst, stopping at the first field error. A non-nil
+// present selects present-only binding (see bindStructFields).
+func bindFormValues(dst any, values url.Values, present *[]string) error {
 	val, err := pointerStruct(dst)
 	if err != nil {
 		return err
 	}
-	return bindStructFields(val, values, "", nil)
+	return bindStructFields(val, values, "", nil, present)
 }
 
 // bindFormValuesAll binds into dst, accumulating every field error and returning
 // them as errors.Join (nil when all fields bind).
-func bindFormValuesAll(dst any, values url.Values) error {
+func bindFormValuesAll(dst any, values url.Values, present *[]string) error {
 	val, err := pointerStruct(dst)
 	if err != nil {
 		return err
 	}
 	errs := make([]error, 0)
-	_ = bindStructFields(val, values, "", &errs)
+	_ = bindStructFields(val, values, "", &errs, present)
 	if len(errs) == 0 {
 		return nil
 	}
@@ -151,8 +200,10 @@ func pointerStruct(v any) (reflect.Value, error) {
 
 // bindStructFields binds val's "field"-tagged fields. When errs is nil it stops
 // at the first error; when non-nil, each field error accumulates into it (as a
-// *bindError) and binding continues.
-func bindStructFields(val reflect.Value, values url.Values, path string, errs *[]error) error {
+// *bindError) and binding continues. When present is non-nil, fields whose keys
+// are absent from values are skipped, and the paths of the others are appended
+// to it.
+func bindStructFields(val reflect.Value, values url.Values, path string, errs *[]error, present *[]string) error {
 	for n := 0; n < val.NumField(); n++ {
 		field := val.Type().Field(n)
 		if field.PkgPath != "" {
@@ -166,7 +217,7 @@ func bindStructFields(val reflect.Value, values url.Values, path string, errs *[
 		}
 
 		if _, ok := field.Tag.Lookup("field"); ok {
-			if err := bindField(field, fieldValue, values, fieldPath); err != nil {
+			if err := bindField(field, fieldValue, values, fieldPath, present); err != nil {
 				if errs == nil {
 					return err
 				}
@@ -176,7 +227,7 @@ func bindStructFields(val reflect.Value, values url.Values, path string, errs *[
 
 		switch fieldValue.Kind() {
 		case reflect.Struct:
-			if err := bindStructFields(fieldValue, values, fieldPath, errs); err != nil {
+			if err := bindStructFields(fieldValue, values, fieldPath, errs, present); err != nil {
 				return err
 			}
 		case reflect.Ptr:
@@ -187,7 +238,7 @@ func bindStructFields(val reflect.Value, values url.Values, path string, errs *[
 			if elem.Kind() != reflect.Struct {
 				continue
 			}
-			if err := bindStructFields(elem, values, fieldPath, errs); err != nil {
+			if err := bindStructFields(elem, values, fieldPath, errs, present); err != nil {
 				return err
 			}
 		}
@@ -200,17 +251,14 @@ func bindStructFields(val reflect.Value, values url.Values, path string, errs *[
 // failures (type mismatch, too many values, a missing required value) are
 // returned as a *bindError keyed by fieldPath — Status maps those to 422 and
 // FieldErrors surfaces them. Failures from a malformed field tag itself
-// (splitFormTag, ProcessParams) are developer errors, returned unwrapped, so
-// Status maps them to 400 and FieldErrors omits them.
-func bindField(field reflect.StructField, fieldValue reflect.Value, values url.Values, fieldPath string) error {
-	args, err := splitFormTag(field.Tag.Get("field"))
+// (formtag.Parse) are developer errors, returned unwrapped, so Status maps
+// them to 400 and FieldErrors omits them. A non-nil present records fieldPath
+// when its key is in values and skips the field when it is not.
+func bindField(field reflect.StructField, fieldValue reflect.Value, values url.Values, fieldPath string, present *[]string) error {
+	directive, err := formtag.Parse(field.Tag.Get("field"))
 	if err != nil {
 		return err
 	}
-	var directive fieldDirective
-	if err := tagex.ProcessParams(&directive, args); err != nil {
-		return err
-	}
 
 	key := strings.TrimSpace(directive.Key)
 	if key == "" {
@@ -218,6 +266,12 @@ func bindField(field reflect.StructField, fieldValue reflect.Value, values url.V
 	}
 
 	raw, ok := values[key]
+	if present != nil {
+		if !ok {
+			return nil
+		}
+		*present = append(*present, fieldPath)
+	}
 	if !ok || len(raw) == 0 || raw[0] == "" {
 		if err := applyDefaultOrRequired(fieldValue, directive); err != nil {
 			return &bindError{Field: fieldPath, Err: err}
@@ -233,7 +287,7 @@ func bindField(field reflect.StructField, fieldValue reflect.Value, values url.V
 	return nil
 }
 
-func applyDefaultOrRequired(fieldValue reflect.Value, directive fieldDirective) error {
+func applyDefaultOrRequired(fieldValue reflect.Value, directive formtag.Directive) error {
 	if directive.Required {
 		return ErrFieldRequired
 	}
@@ -255,33 +309,6 @@ func enforceMax(raw []string, max int) error {
 	return nil
 }
 
-func splitFormTag(tagVal string) (map[string]string, error) {
-	parts := strings.Split(tagVal, ",")
-	if len(parts) == 0 || strings.TrimSpace(parts[0]) == "" {
-		return nil, fmt.Errorf("field tag value is required")
-	}
-
-	args := make(map[string]string)
-	args["key"] = strings.TrimSpace(parts[0])
-	for _, pair := range parts[1:] {
-		pair = strings.TrimSpace(pair)
-		if pair == "" {
-			continue
-		}
-		kv := strings.Split(pair, "=")
-		if len(kv) != 2 {
-			return nil, fmt.Errorf("malformed key value pair %q, expected format is \"key=value\"", pair)
-		}
-		key := strings.TrimSpace(kv[0])
-		val := strings.TrimSpace(kv[1])
-		if key == "" || val == "" {
-			return nil, fmt.Errorf("malformed key value pair %q, expected format is \"key=value\"", pair)
-		}
-		args[key] = val
-	}
-	return args, nil
-}
-
 func setValueFromRaw(fieldValue reflect.Value, raw []string) error {
 	if fieldValue.Kind() == reflect.Ptr {
 		if fieldValue.IsNil() {
@@ -0,0 +1,86 @@
+package forms_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/forms"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+type profilePatch struct {
+	Name    string `field:"name,required=true" val:"min,size=3"`
+	Email   string `field:"email,required=true" val:"email"`
+	Age     int    `field:"age,default=18" val:"rangeint,min=1,max=150"`
+	Address struct {
+		City string `field:"city" val:"min,size=2"`
+	}
+}
+
+func presentRegistry() *valex.Registry {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.MinLengthValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.EmailValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+	return reg
+}
+
+func TestValidatePresentSkipsOmittedFields(t *testing.T) {
+	dst := profilePatch{Email: "old@example.com", Age: 40}
+	err := forms.ValidatePresentWith(postForm(url.Values{"name": {"Alice"}}), &dst, presentRegistry())
+	if err != nil {
+		t.Fatalf("omitted required fields must not fail: %v", err)
+	}
+	if dst.Name != "Alice" {
+		t.Errorf("Name = %q, want Alice", dst.Name)
+	}
+	if dst.Email != "old@example.com" || dst.Age != 40 {
+		t.Errorf("omitted fields changed: Email %q, Age %d", dst.Email, dst.Age)
+	}
+
+	// The same request fails full validation.
+	if err := forms.ValidateWith(postForm(url.Values{"name": {"Alice"}}), &profilePatch{}, presentRegistry()); err == nil {
+		t.Fatal("Validate should require email")
+	}
+}
+
+func TestValidatePresentAllValidatesPresentFields(t *testing.T) {
+	form := url.Values{
+		"name": {"Al"},  // present and too short
+		"age":  {"abc"}, // present and not a number
+		"city": {"X"},   // nested, present and too short
+	}
+	err := forms.ValidatePresentAllWith(postForm(form), &profilePatch{}, presentRegistry())
+	var ferr *forms.Error
+	if !errors.As(err, &ferr) || ferr.StatusCode() != http.StatusUnprocessableEntity {
+		t.Fatalf("want *forms.Error with 422, got %v", err)
+	}
+	fe := forms.FieldErrors(err)
+	if len(fe) != 3 || fe["Name"] == nil || fe["Age"] == nil || fe["Address.City"] == nil {
+		t.Fatalf("want Name, Age, and Address.City errors, got %v", fe)
+	}
+}
+
+func TestValidatePresentEmptyValue(t *testing.T) {
+	// A key sent empty clears the field, so required still applies.
+	err := forms.ValidatePresentWith(postForm(url.Values{"email": {""}}), &profilePatch{}, presentRegistry())
+	if !errors.Is(err, forms.ErrFieldRequired) {
+		t.Fatalf("want ErrFieldRequired, got %v", err)
+	}
+}
+
+func TestValidatePresentQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/?email=nope", nil)
+	v, err := forms.NewWith(req, presentRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fe := forms.FieldErrors(v.ValidatePresentAll(&profilePatch{}))
+	if len(fe) != 1 || fe["Email"] == nil {
+		t.Fatalf("want only an Email error, got %v", fe)
+	}
+}
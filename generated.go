@@ -0,0 +1,71 @@
+package valex
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// This file supports the code cmd/valexgen generates: Validate and ValidateAll
+// methods that call catalog directives directly instead of walking the struct
+// with reflection. The functions are exported for that code; they are not
+// meant to be called by hand.
+
+// FieldsFunc validates a struct's fields the way generated code does: prefix
+// is prepended to each field name to form its path ("" at the top, "Items[0]."
+// below), depth counts nesting for the depth limit, and failures are returned
+// at once when errs is nil and appended to *errs otherwise.
+type FieldsFunc func(prefix string, depth int, errs *[]error) error
+
+// MustParams writes params to the parameters of directive d, a pointer to a
+// directive struct, and returns d. It panics if a parameter is missing or does
+// not convert; cmd/valexgen checks them before it emits the call.
+func MustParams[D any](d D, params map[string]string) D {
+	if err := tagex.ProcessParams(d, params); err != nil {
+		panic(fmt.Sprintf("valex: parameters of %T: %v", d, err))
+	}
+	return d
+}
+
+// RunGenerated validates data, a pointer to a struct, with fields, running its
+// lifecycle hooks around them as ValidateStruct (all false) or
+// ValidateStructAll (all true) does, and returning the same errors.
+func RunGenerated(data any, all bool, fields FieldsFunc) error {
+	if v := reflect.ValueOf(data); v.Kind() != reflect.Pointer || v.IsNil() {
+		return &ProcessError{Stage: StageInput, Cause: &InvalidTargetError{Got: fmt.Sprintf("%T", data)}}
+	}
+	var errs *[]error
+	if all {
+		errs = &[]error{}
+	}
+	return runHooks(data, errs, func() error {
+		return fields("", 0, errs)
+	})
+}
+
+// DirectiveFailure reports that directive returned err for the field at
+// fieldPath, as the *TagError ValidateStruct returns. With errs nil it returns
+// that error; otherwise it appends it to *errs and returns nil.
+func DirectiveFailure(errs *[]error, fieldPath, directive string, err error) error {
+	e := &TagError{TagKey: tagKey, Err: &ProcessError{
+		Stage:     StageDirective,
+		FieldPath: fieldPath,
+		Directive: directive,
+		Cause:     &HandleError{Nested: err},
+	}}
+	if errs == nil {
+		return e
+	}
+	*errs = append(*errs, e)
+	return nil
+}
+
+// CheckDepth returns the *MaxDepthError ValidateStruct reports for the value
+// at path when depth is past the nesting limit, and nil otherwise.
+func CheckDepth(path string, depth int) error {
+	if depth > maxDepth {
+		return &ProcessError{Stage: StageStruct, FieldPath: truncatePath(path), Cause: &MaxDepthError{Limit: maxDepth}}
+	}
+	return nil
+}
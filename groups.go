@@ -0,0 +1,89 @@
+package valex
+
+import (
+	"reflect"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// omitEmpty is the chain keyword that ends a chain early for a zero value.
+const omitEmpty = "omitempty"
+
+// ValidateOption configures a single ValidateStructWith or
+// ValidateStructAllWith call.
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	tags   []*tagex.Tag
+	groups []string
+	mask   fieldMask // nil validates every field
+}
+
+func newValidateConfig(opts []ValidateOption) validateConfig {
+	var c validateConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c validateConfig) scope() scope {
+	return scope{groups: c.groups, mask: c.mask}
+}
+
+// WithTags processes extra tags in the same call. They run on each field
+// before its "val" directives, inside the same lifecycle hooks.
+func WithTags(tags ...*tagex.Tag) ValidateOption {
+	return func(c *validateConfig) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// Groups selects the groups whose segments run, so one struct can carry the
+// rules of several scenarios. A segment written with a group prefix, such as
+// "create:!empty" or "create|update:email", runs only when one of its groups is
+// selected; a segment without one always runs. Without Groups, only segments
+// without a group prefix run.
+//
+//	type User struct {
+//		Email string `val:"create:!empty;update:omitempty;email"`
+//	}
+//
+//	err := valex.ValidateStructWith(&u, valex.Groups("update"))
+func Groups(names ...string) ValidateOption {
+	return func(c *validateConfig) {
+		c.groups = append(c.groups, names...)
+	}
+}
+
+// applies reports whether s runs when groups are selected.
+func (s segment) applies(groups []string) bool {
+	if len(s.groups) == 0 {
+		return true
+	}
+	for _, g := range s.groups {
+		if inGroups(groups, g) {
+			return true
+		}
+	}
+	return false
+}
+
+func inGroups(groups []string, name string) bool {
+	for _, g := range groups {
+		if g == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty reports whether v is the zero value of its type, or an empty slice
+// or map, for omitempty.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,176 @@
+package valex_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+func groupRegistry(t *testing.T) *valex.Registry {
+	t.Helper()
+	reg := aliasRegistry(t)
+	valex.MustRegisterDirectiveTo(reg, &validators.NonEmptyStringValidator{})
+	return reg
+}
+
+type account struct {
+	Email string `val:"create:!empty;update:omitempty;email"`
+	Name  string `val:"create|update:min,size=3"`
+	Nick  string `val:"min,size=2"`
+}
+
+func TestGroups(t *testing.T) {
+	reg := groupRegistry(t)
+	tests := []struct {
+		name   string
+		in     account
+		groups []string
+		want   []string
+	}{
+		{"ungrouped only", account{Email: "a@b.co", Nick: "ab"}, nil, nil},
+		{"ungrouped still runs", account{Email: "a@b.co", Nick: "a"}, nil, []string{"Nick"}},
+		{"create requires email", account{Name: "Gopher", Nick: "go"}, []string{"create"}, []string{"Email"}},
+		{"update allows empty email", account{Name: "Gopher", Nick: "go"}, []string{"update"}, nil},
+		{"update checks a sent email", account{Email: "nope", Name: "Gopher", Nick: "go"}, []string{"update"}, []string{"Email"}},
+		{"either group", account{Email: "a@b.co", Name: "Al", Nick: "go"}, []string{"update"}, []string{"Name"}},
+		{"unknown group", account{Email: "a@b.co", Name: "Al", Nick: "go"}, []string{"delete"}, nil},
+		{"several groups", account{Name: "Al", Nick: "go"}, []string{"update", "create"}, []string{"Email", "Name"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			in := tc.in
+			fe := valex.FieldErrors(reg.ValidateStructAllWith(&in, valex.Groups(tc.groups...)))
+			if len(fe) != len(tc.want) {
+				t.Fatalf("failed fields = %v, want %v", fe, tc.want)
+			}
+			for _, field := range tc.want {
+				if fe[field] == nil {
+					t.Fatalf("failed fields = %v, want %v", fe, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOmitEmpty(t *testing.T) {
+	reg := groupRegistry(t)
+	type optional struct {
+		Code  string   `val:"omitempty;min,size=3"`
+		Codes []string `val:"omitempty;nope"` // never looked up while empty
+	}
+	if err := reg.ValidateStruct(&optional{Codes: []string{}}); err != nil {
+		t.Fatalf("empty values must skip the chain: %v", err)
+	}
+	if err := reg.ValidateStruct(&optional{Code: "ab"}); err == nil {
+		t.Fatal("a set value must still be validated")
+	}
+	var unknown *valex.UnknownDirectiveError
+	if err := reg.ValidateStruct(&optional{Codes: []string{"x"}}); !errors.As(err, &unknown) {
+		t.Fatalf("want *UnknownDirectiveError past omitempty, got %v", err)
+	}
+}
+
+func TestGroupsInAliases(t *testing.T) {
+	reg := groupRegistry(t)
+	reg.MustAlias("contact", "update:omitempty;email")
+
+	type user struct {
+		Email string `val:"create:!empty;contact"`
+		Alt   string `val:"create:contact"`
+	}
+	if err := reg.ValidateStructWith(&user{}, valex.Groups("update")); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	// In create, omitempty is outside the group, so an empty Email fails.
+	fe := valex.FieldErrors(reg.ValidateStructAllWith(&user{}, valex.Groups("create")))
+	if fe["Email"] == nil || fe["Alt"] == nil {
+		t.Fatalf("create: want Email and Alt errors, got %v", fe)
+	}
+
+	expanded, err := reg.ExpandTag("create:contact;min,size=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expanded != "create:email;min,size=1" {
+		t.Errorf("ExpandTag = %q", expanded)
+	}
+	expanded, err = reg.ExpandTag("contact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expanded != "update:omitempty;email" {
+		t.Errorf("ExpandTag = %q", expanded)
+	}
+}
+
+func TestGroupSyntax(t *testing.T) {
+	reg := groupRegistry(t)
+	ds, err := reg.CheckTag("a | b:min,size=1;omitempty;email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ds[0].Groups, []string{"a", "b"}) || ds[2].Groups != nil {
+		t.Errorf("groups = %v, %v", ds[0].Groups, ds[2].Groups)
+	}
+	if ds[1].Name != "omitempty" || ds[1].Type != nil {
+		t.Errorf("omitempty = %+v", ds[1])
+	}
+
+	for _, tag := range []string{"create:", ":min,size=1", "a||b:email"} {
+		var parseErr *valex.DirectiveParseError
+		if _, err := reg.CheckTag(tag); !errors.As(err, &parseErr) {
+			t.Errorf("CheckTag(%q) = %v, want *DirectiveParseError", tag, err)
+		}
+	}
+
+	var dup *valex.DuplicateDirectiveError
+	if err := reg.Alias("omitempty", "email"); !errors.As(err, &dup) {
+		t.Errorf("Alias(omitempty) = %v, want *DuplicateDirectiveError", err)
+	}
+	if err := reg.Alias("a:b", "email"); err == nil {
+		t.Error("an alias name with a colon must be rejected")
+	}
+}
+
+// ValidateStruct keeps its tags signature, so it still works as a method
+// value or behind an interface.
+var _ interface {
+	ValidateStruct(any, ...*tagex.Tag) error
+	ValidateStructAll(any, ...*tagex.Tag) error
+} = (*valex.Registry)(nil)
+
+func TestValidateStructWith(t *testing.T) {
+	reg := groupRegistry(t)
+	in := account{Name: "Al", Nick: "a"}
+
+	// Fields and Groups combine: only Name runs, with its create rules.
+	fe := valex.FieldErrors(reg.ValidateStructAllWith(&in, valex.Fields("Name"), valex.Groups("create")))
+	if len(fe) != 1 || fe["Name"] == nil {
+		t.Fatalf("failed fields = %v, want Name", fe)
+	}
+	fe = valex.FieldErrors(reg.ValidateStructAllWith(&in, valex.Fields("Name", "Email"), valex.Groups("create")))
+	if len(fe) != 2 || fe["Email"] == nil {
+		t.Fatalf("failed fields = %v, want Name and Email", fe)
+	}
+	if err := reg.ValidateStructWith(&in, valex.Fields()); err != nil {
+		t.Fatalf("no fields selected: %v", err)
+	}
+	var unknown *valex.UnknownFieldError
+	if err := reg.ValidateStructWith(&in, valex.Fields("Bogus"), valex.Groups("create")); !errors.As(err, &unknown) {
+		t.Fatalf("want *UnknownFieldError, got %v", err)
+	}
+
+	check := tagex.NewTag("check")
+	tagex.MustRegisterDirective(check, &validators.MinLengthValidator{})
+	type tagged struct {
+		Code string `check:"min,size=2" val:"create:min,size=3"`
+	}
+	fe = valex.FieldErrors(reg.ValidateStructAllWith(&tagged{Code: "x"}, valex.WithTags(check), valex.Groups("create")))
+	if len(fe) != 1 || fe["Code"] == nil {
+		t.Fatalf("failed fields = %v, want Code", fe)
+	}
+}
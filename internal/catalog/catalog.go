@@ -0,0 +1,141 @@
+// Package catalog lists the valex/validators directives for the valex
+// commands, which check and generate code against them.
+package catalog
+
+import (
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+// entry is one catalog directive: the value itself, and how to register it.
+type entry struct {
+	directive any
+	register  func(r *valex.Registry)
+}
+
+func add[T any](d tagex.Directive[T]) entry {
+	return entry{directive: d, register: func(r *valex.Registry) { valex.MustRegisterDirectiveTo(r, d) }}
+}
+
+var entries = []entry{
+	add(&validators.LuhnValidator{}),
+	add(&validators.Mod11Validator{}),
+	add(&validators.CreditCardValidator{}),
+	add(&validators.IBANValidator{}),
+	add(&validators.ISBNValidator{}),
+	add(&validators.EANValidator{}),
+	add(&validators.UPCValidator{}),
+	add(&validators.DecimalValidator{}),
+	add(&validators.StepIntValidator{}),
+	add(&validators.StepFloat64Validator{}),
+	add(&validators.LatitudeValidator{}),
+	add(&validators.LongitudeValidator{}),
+	add(&validators.LatLongValidator{}),
+	add(&validators.SlugValidator{}),
+	add(&validators.IdentifierValidator{}),
+	add(&validators.DNSLabelValidator{}),
+	add(&validators.K8sNameValidator{}),
+	add(&validators.K8sLabelValueValidator{}),
+	add(&validators.EnvVarNameValidator{}),
+	add(&validators.JSONSchemaValidator{}),
+	add(&validators.RawJSONSchemaValidator{}),
+	add(&validators.CountryValidator{}),
+	add(&validators.CurrencyValidator{}),
+	add(&validators.LanguageValidator{}),
+	add(&validators.TimezoneValidator{}),
+	add(&validators.AddrValidator{}),
+	add(&validators.NonZeroAddrValidator{}),
+	add(&validators.AddrRangeValidator{}),
+	add(&validators.PrivateAddrValidator{}),
+	add(&validators.PublicAddrValidator{}),
+	add(&validators.LoopbackAddrValidator{}),
+	add(&validators.MulticastAddrValidator{}),
+	add(&validators.InPrefixValidator{}),
+	add(&validators.NetPrefixValidator{}),
+	add(&validators.AddrPortValidator{}),
+	add(&validators.PasswordValidator{}),
+	add(&validators.E164Validator{}),
+	add(&validators.PhoneValidator{}),
+	add(&validators.FutureValidator{}),
+	add(&validators.PastValidator{}),
+	add(&validators.WithinValidator{}),
+	add(&validators.NotOlderThanValidator{}),
+	add(&validators.WeekdayValidator{}),
+	add(&validators.TimeOfDayValidator{}),
+	add(&validators.BusinessHoursValidator{}),
+	add(&validators.SemverValidator{}),
+	add(&validators.IntRangeValidator{}),
+	add(&validators.Float64RangeValidator{}),
+	add(&validators.NonNegativeIntValidator{}),
+	add(&validators.NonNegativeFloat64Validator{}),
+	add(&validators.NonPositiveIntValidator{}),
+	add(&validators.NonPositiveFloat64Validator{}),
+	add(&validators.UrlValidator{}),
+	add(&validators.EmailValidator{}),
+	add(&validators.NonEmptyStringValidator{}),
+	add(&validators.MinLengthValidator{}),
+	add(&validators.MaxLengthValidator{}),
+	add(&validators.LengthRangeValidator{}),
+	add(&validators.RegexValidator{}),
+	add(&validators.AlphaNumericValidator{}),
+	add(&validators.AlphaValidator{}),
+	add(&validators.NumericValidator{}),
+	add(&validators.UnicodeLetterValidator{}),
+	add(&validators.PrintableValidator{}),
+	add(&validators.ASCIIValidator{}),
+	add(&validators.NoControlCharValidator{}),
+	add(&validators.MACAddressValidator{}),
+	add(&validators.IpValidator{}),
+	add(&validators.IPv4Validator{}),
+	add(&validators.IPv6Validator{}),
+	add(&validators.XMLValidator{}),
+	add(&validators.JSONValidator{}),
+	add(&validators.MinIntValidator{}),
+	add(&validators.MinFloat64Validator{}),
+	add(&validators.MaxIntValidator{}),
+	add(&validators.MaxFloat64Validator{}),
+	add(&validators.NonZeroIntValidator{}),
+	add(&validators.NonZeroFloat64Validator{}),
+	add(&validators.NonZeroTimeValidator{}),
+	add(&validators.TimeBeforeValidator{}),
+	add(&validators.TimeAfterValidator{}),
+	add(&validators.TimeBetweenValidator{}),
+	add(&validators.PositiveDurationValidator{}),
+	add(&validators.NonZeroDurationValidator{}),
+	add(&validators.NonZeroIPValidator{}),
+	add(&validators.IPRangeValidator{}),
+	add(&validators.NonZeroURLValidator{}),
+	add(&validators.OneOfFloat64Validator{}),
+	add(&validators.OneOfStringValidator{}),
+	add(&validators.OneOfIntValidator{}),
+	add(&validators.PrefixValidator{}),
+	add(&validators.SuffixValidator{}),
+	add(&validators.ContainsValidator{}),
+	add(&validators.UUIDValidator{}),
+	add(&validators.HostnameValidator{}),
+	add(&validators.IPCIDRValidator{}),
+	add(&validators.Base64Validator{}),
+	add(&validators.HexValidator{}),
+	add(&validators.TimeValidator{}),
+}
+
+// Registry returns a new registry holding every catalog directive.
+func Registry() *valex.Registry {
+	r := valex.NewRegistry()
+	for _, e := range entries {
+		e.register(r)
+	}
+	return r
+}
+
+// Directive returns the catalog directive registered as name, a pointer to a
+// zero validators struct, or false if there is none.
+func Directive(name string) (any, bool) {
+	for _, e := range entries {
+		if e.directive.(interface{ Name() string }).Name() == name {
+			return e.directive, true
+		}
+	}
+	return nil, false
+}
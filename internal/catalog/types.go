@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// SameType reports whether typ is the type rt describes, which is what a
+// directive on T needs of its field. ok is false when the comparison is not
+// decidable here, such as for generic or function types.
+func SameType(typ types.Type, rt reflect.Type) (same, ok bool) {
+	typ = types.Unalias(typ)
+	if rt.Kind() == reflect.Interface {
+		return false, true // a concrete field never satisfies an interface T
+	}
+	if rt.Name() != "" {
+		if strings.Contains(rt.Name(), "[") {
+			return false, false
+		}
+		if rt.PkgPath() == "" {
+			b, isBasic := typ.(*types.Basic)
+			return isBasic && basicName(b) == rt.Name(), true
+		}
+		named, isNamed := typ.(*types.Named)
+		if !isNamed {
+			return false, true
+		}
+		obj := named.Obj()
+		return obj.Pkg() != nil && obj.Pkg().Path() == rt.PkgPath() && obj.Name() == rt.Name(), true
+	}
+	switch rt.Kind() {
+	case reflect.Pointer:
+		p, is := typ.(*types.Pointer)
+		if !is {
+			return false, true
+		}
+		return SameType(p.Elem(), rt.Elem())
+	case reflect.Slice:
+		s, is := typ.(*types.Slice)
+		if !is {
+			return false, true
+		}
+		return SameType(s.Elem(), rt.Elem())
+	case reflect.Array:
+		a, is := typ.(*types.Array)
+		if !is || a.Len() != int64(rt.Len()) {
+			return false, true
+		}
+		return SameType(a.Elem(), rt.Elem())
+	case reflect.Map:
+		m, is := typ.(*types.Map)
+		if !is {
+			return false, true
+		}
+		if same, ok := SameType(m.Key(), rt.Key()); !same || !ok {
+			return same, ok
+		}
+		return SameType(m.Elem(), rt.Elem())
+	}
+	return false, false
+}
+
+// basicName returns b's name with the byte and rune aliases resolved, as
+// reflect names them.
+func basicName(b *types.Basic) string {
+	switch b.Kind() {
+	case types.Byte:
+		return "uint8"
+	case types.Rune:
+		return "int32"
+	}
+	return b.Name()
+}
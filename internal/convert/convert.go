@@ -0,0 +1,161 @@
+// Package convert parses raw strings into reflected Go values. It is the shared
+// conversion layer behind the packages that bind text input — environment
+// variables, command-line flags, CSV cells — into struct fields, so they all
+// accept the same types the same way.
+package convert
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Values sets v from raw. A slice receives one element per raw value; any other
+// type takes raw[0] (see Text). A slice type that is itself an
+// encoding.TextUnmarshaler, such as net.IP, is treated as a single value.
+func Values(v reflect.Value, raw []string) error {
+	if v.Kind() == reflect.Slice && !IsText(v.Type()) {
+		slice := reflect.MakeSlice(v.Type(), 0, len(raw))
+		for _, item := range raw {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := Text(elem, item); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+		return nil
+	}
+	return Text(v, raw[0])
+}
+
+// Text parses s into v, allocating through nil pointers. It uses the type's
+// encoding.TextUnmarshaler when it has one, time.ParseDuration for
+// time.Duration, and strconv for strings, booleans, integers, and floats.
+func Text(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return Text(v.Elem(), s)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+		return nil
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+}
+
+// CanText reports whether Text can parse into a value of type t, so a binder
+// can reject an unsupported field up front rather than on its first value.
+func CanText(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		return CanText(t.Elem())
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Format renders v as text, the inverse of Text: through its
+// encoding.TextMarshaler, time.Duration's String, or strconv. A nil pointer
+// formats as the empty string.
+func Format(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		return Format(v.Elem())
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported field type %s", v.Type())
+	}
+}
+
+// IsText reports whether t (or *t) decodes itself from text, so a binder
+// should convert it as one value rather than descend into it as a struct or
+// split it as a slice.
+func IsText(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,58 @@
+// Package formtag parses the valex/forms "field" tag, whose grammar the
+// valex/csv "csv" tag shares. It lives apart from forms so that cmd/valexvet
+// rejects exactly the tags the binder rejects.
+package formtag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// Directive is a parsed "field" tag.
+type Directive struct {
+	Key          string `param:"key,required=false"`
+	Max          int    `param:"max,default=1"`
+	Required     bool   `param:"required,required=false"`
+	DefaultValue string `param:"default,required=false"`
+}
+
+// Parse parses a "field" tag value: a request key followed by ','-separated
+// key=value options.
+func Parse(tagVal string) (Directive, error) {
+	var d Directive
+	args, err := split(tagVal)
+	if err != nil {
+		return d, err
+	}
+	err = tagex.ProcessParams(&d, args)
+	return d, err
+}
+
+func split(tagVal string) (map[string]string, error) {
+	parts := strings.Split(tagVal, ",")
+	if len(parts) == 0 || strings.TrimSpace(parts[0]) == "" {
+		return nil, fmt.Errorf("field tag value is required")
+	}
+
+	args := make(map[string]string)
+	args["key"] = strings.TrimSpace(parts[0])
+	for _, pair := range parts[1:] {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.Split(pair, "=")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed key value pair %q, expected format is \"key=value\"", pair)
+		}
+		key := strings.TrimSpace(kv[0])
+		val := strings.TrimSpace(kv[1])
+		if key == "" || val == "" {
+			return nil, fmt.Errorf("malformed key value pair %q, expected format is \"key=value\"", pair)
+		}
+		args[key] = val
+	}
+	return args, nil
+}
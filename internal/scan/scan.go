@@ -0,0 +1,100 @@
+// Package scan splits struct-tag values with the quoting rules of the "val"
+// tag: a value wrapped in single quotes keeps separators literally, and a
+// doubled single quote inside it is an escaped quote.
+package scan
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const quote = '\''
+
+// SplitTop splits s on sep outside single-quoted spans, like strings.SplitN:
+// n < 0 splits on every top-level sep, n > 0 caps the result at n fields.
+func SplitTop(s string, sep byte, n int) []string {
+	var out []string
+	start := 0
+	inQuote := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			if inQuote && i+1 < len(s) && s[i+1] == quote {
+				i++ // escaped ''
+				continue
+			}
+			inQuote = !inQuote
+		case c == sep && !inQuote && (n < 0 || len(out) < n-1):
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
+}
+
+// IsQuoted reports whether s is wrapped in a matching pair of single quotes.
+func IsQuoted(s string) bool {
+	return len(s) >= 2 && s[0] == quote && s[len(s)-1] == quote
+}
+
+// Unquote trims s and, if it is single-quoted, strips the quotes and collapses
+// each doubled quote to one.
+func Unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if !IsQuoted(s) {
+		return s
+	}
+	return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+}
+
+// Quote quotes v if it would not survive Unquote and SplitTop on any of seps
+// unquoted.
+func Quote(v, seps string) string {
+	if v != "" && v == strings.TrimSpace(v) && !strings.ContainsAny(v, seps+"'") {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// Options parses a binder tag value such as the "env" and "flag" tags: a
+// positional value, stored under first, followed by ','-separated key=value
+// pairs whose values may be single-quoted. The result is the argument map
+// tagex.ProcessParams takes.
+func Options(tagVal, first string) (map[string]string, error) {
+	parts := SplitTop(tagVal, ',', -1)
+	args := map[string]string{first: strings.TrimSpace(parts[0])}
+	for _, pair := range parts[1:] {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := SplitTop(pair, '=', 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || key == "" || strings.TrimSpace(kv[1]) == "" {
+			return nil, fmt.Errorf("malformed key value pair %q, expected format is \"key=value\"", pair)
+		}
+		args[key] = Unquote(kv[1])
+	}
+	return args, nil
+}
+
+// Words splits a Go identifier into its words at case changes, keeping
+// initialisms together: MaxConns gives Max, Conns and HTTPPort gives HTTP,
+// Port.
+func Words(s string) []string {
+	runes := []rune(s)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		r, prev := runes[i], runes[i-1]
+		if !unicode.IsUpper(r) {
+			continue
+		}
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
@@ -0,0 +1,222 @@
+package valex
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultParamCacheSize is the number of converted parameters the process-wide
+// parameter cache holds until SetParamCacheSize changes it.
+const DefaultParamCacheSize = 1024
+
+// CacheStats is a snapshot of the parameter cache's counters.
+type CacheStats struct {
+	Hits      uint64 // lookups answered from the cache
+	Misses    uint64 // lookups that ran the conversion
+	Evictions uint64 // entries dropped to stay within Capacity
+	Len       int    // entries currently cached
+	Capacity  int    // maximum entries; 0 means caching is off
+}
+
+// HitRate returns Hits / (Hits + Misses), or 0 before the first lookup.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// ParamCacheEvent describes one parameter cache lookup, for SetParamCacheHook.
+type ParamCacheEvent struct {
+	Directive string
+	Param     string
+	Hit       bool
+	Evicted   bool // the lookup's insert evicted the least recently used entry
+}
+
+// CachedParam returns convert(raw), caching the result process-wide under the
+// directive's type and name, param, raw, and T. tagex converts a directive's
+// parameters on every ValidateStruct call; a ParamConverter whose conversion
+// is expensive — compiling a regular expression, loading a time zone, parsing
+// a list — can route it through CachedParam so each distinct tag value is
+// converted once:
+//
+//	func (d *Pattern) ConvertParam(field reflect.StructField, fv reflect.Value, raw string) error {
+//		re, err := valex.CachedParam(d, "expr", raw, regexp.Compile)
+//		if err != nil {
+//			return err
+//		}
+//		fv.Set(reflect.ValueOf(re))
+//		return nil
+//	}
+//
+// Keying by the directive's type keeps directives that share a name — in two
+// registries, or a custom directive reusing a catalog name — from being served
+// each other's values. convert must therefore depend only on the directive's
+// type, param, and raw, not on per-call state. Errors are returned but not
+// cached. The cached value is shared by every
+// later call with the same key, possibly from several goroutines at once, so it
+// must be safe to use concurrently and must not be modified — a *regexp.Regexp
+// or an immutable slice, not a buffer. The cache holds at most
+// DefaultParamCacheSize entries, evicting the least recently used; see
+// SetParamCacheSize, ParamCacheStats, and SetParamCacheHook.
+func CachedParam[T any](directive interface{ Name() string }, param, raw string, convert func(string) (T, error)) (T, error) {
+	name := directive.Name()
+	key := paramCacheKey{dir: reflect.TypeOf(directive), directive: name, param: param, raw: raw, typ: reflect.TypeFor[T]()}
+	if v, ok := paramCache.get(key); ok {
+		paramCache.notify(ParamCacheEvent{Directive: name, Param: param, Hit: true})
+		return v.(T), nil
+	}
+	v, err := convert(raw)
+	if err != nil {
+		paramCache.notify(ParamCacheEvent{Directive: name, Param: param})
+		return v, err
+	}
+	evicted := paramCache.put(key, v)
+	paramCache.notify(ParamCacheEvent{Directive: name, Param: param, Evicted: evicted})
+	return v, nil
+}
+
+// SetParamCacheSize sets the number of entries the parameter cache holds,
+// evicting the least recently used ones if it shrinks. A size of 0 turns
+// caching off; CachedParam then converts on every call.
+func SetParamCacheSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	paramCache.resize(n)
+}
+
+// ParamCacheStats returns a snapshot of the parameter cache's counters.
+func ParamCacheStats() CacheStats {
+	return paramCache.stats()
+}
+
+// ResetParamCache empties the parameter cache and zeroes its counters, keeping
+// its size.
+func ResetParamCache() {
+	paramCache.reset()
+}
+
+// SetParamCacheHook registers fn to be called after every CachedParam lookup,
+// for exporting hit rates to a metrics system; pass nil to remove it. fn runs
+// synchronously on the validating goroutine, possibly concurrently with
+// itself, so it should be quick and safe for concurrent use.
+func SetParamCacheHook(fn func(ParamCacheEvent)) {
+	if fn == nil {
+		paramCache.hook.Store(nil)
+		return
+	}
+	paramCache.hook.Store(&fn)
+}
+
+type paramCacheKey struct {
+	dir                   reflect.Type // the directive's type
+	directive, param, raw string
+	typ                   reflect.Type // the converted value's type
+}
+
+type paramCacheEntry struct {
+	key paramCacheKey
+	val any
+}
+
+// lruCache is a mutex-guarded LRU map: entries move to the front of order on
+// use and are evicted from the back.
+type lruCache struct {
+	mu        sync.Mutex
+	capacity  int
+	order     *list.List
+	items     map[paramCacheKey]*list.Element
+	hits      uint64
+	misses    uint64
+	evictions uint64
+	hook      atomic.Pointer[func(ParamCacheEvent)]
+}
+
+var paramCache = newLRUCache(DefaultParamCacheSize)
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[paramCacheKey]*list.Element),
+	}
+}
+
+func (c *lruCache) get(key paramCacheKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return el.Value.(*paramCacheEntry).val, true
+}
+
+// put stores val under key and reports whether an entry was evicted to make
+// room.
+func (c *lruCache) put(key paramCacheKey, val any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity == 0 {
+		return false
+	}
+	if el, ok := c.items[key]; ok { // converted concurrently by another caller
+		el.Value.(*paramCacheEntry).val = val
+		c.order.MoveToFront(el)
+		return false
+	}
+	c.items[key] = c.order.PushFront(&paramCacheEntry{key: key, val: val})
+	return c.evictTo(c.capacity) > 0
+}
+
+func (c *lruCache) evictTo(n int) int {
+	evicted := 0
+	for c.order.Len() > n {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.items, el.Value.(*paramCacheEntry).key)
+		c.evictions++
+		evicted++
+	}
+	return evicted
+}
+
+func (c *lruCache) resize(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = n
+	c.evictTo(n)
+}
+
+func (c *lruCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.items = make(map[paramCacheKey]*list.Element)
+	c.hits, c.misses, c.evictions = 0, 0, 0
+}
+
+func (c *lruCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Len:       c.order.Len(),
+		Capacity:  c.capacity,
+	}
+}
+
+func (c *lruCache) notify(ev ParamCacheEvent) {
+	if fn := c.hook.Load(); fn != nil {
+		(*fn)(ev)
+	}
+}
@@ -0,0 +1,219 @@
+package valex_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+)
+
+// cacheDirective names a directive for CachedParam calls made directly.
+type cacheDirective string
+
+func (d cacheDirective) Name() string { return string(d) }
+
+func TestCachedParam(t *testing.T) {
+	valex.SwapParamCache(t)
+	calls := 0
+	conv := func(raw string) (int, error) {
+		calls++
+		return strconv.Atoi(raw)
+	}
+
+	for i := 0; i < 3; i++ {
+		n, err := valex.CachedParam(cacheDirective("d"), "p", "42", conv)
+		if err != nil || n != 42 {
+			t.Fatalf("CachedParam = %d, %v", n, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected one conversion, got %d", calls)
+	}
+
+	// Directive, param, and target type are all part of the key.
+	valex.CachedParam(cacheDirective("other"), "p", "42", conv)
+	valex.CachedParam(cacheDirective("d"), "q", "42", conv)
+	valex.CachedParam(cacheDirective("d"), "p", "42", func(raw string) (string, error) { return raw, nil })
+	if calls != 3 {
+		t.Errorf("expected distinct keys to convert separately, got %d conversions", calls)
+	}
+
+	// Errors are returned but not cached.
+	for i := 0; i < 2; i++ {
+		if _, err := valex.CachedParam(cacheDirective("d"), "p", "x", conv); err == nil {
+			t.Fatal("expected a conversion error")
+		}
+	}
+	if calls != 5 {
+		t.Errorf("expected failed conversions to be retried, got %d conversions", calls)
+	}
+
+	s := valex.ParamCacheStats()
+	want := valex.CacheStats{Hits: 2, Misses: 6, Len: 4, Capacity: valex.DefaultParamCacheSize}
+	if s != want {
+		t.Errorf("stats = %+v, want %+v", s, want)
+	}
+	if got := s.HitRate(); got != 0.25 {
+		t.Errorf("HitRate = %v, want 0.25", got)
+	}
+}
+
+func TestParamCacheEviction(t *testing.T) {
+	valex.SwapParamCache(t)
+	valex.SetParamCacheSize(2)
+	calls := map[string]int{}
+	conv := func(raw string) (string, error) {
+		calls[raw]++
+		return raw, nil
+	}
+
+	valex.CachedParam(cacheDirective("d"), "p", "a", conv)
+	valex.CachedParam(cacheDirective("d"), "p", "b", conv)
+	valex.CachedParam(cacheDirective("d"), "p", "a", conv) // a is now most recently used
+	valex.CachedParam(cacheDirective("d"), "p", "c", conv) // evicts b
+	valex.CachedParam(cacheDirective("d"), "p", "a", conv)
+	valex.CachedParam(cacheDirective("d"), "p", "b", conv)
+	if calls["a"] != 1 || calls["b"] != 2 || calls["c"] != 1 {
+		t.Errorf("unexpected conversions %v", calls)
+	}
+	if s := valex.ParamCacheStats(); s.Evictions != 2 || s.Len != 2 {
+		t.Errorf("stats = %+v, want 2 evictions and 2 entries", s)
+	}
+
+	valex.SetParamCacheSize(1)
+	if s := valex.ParamCacheStats(); s.Len != 1 || s.Evictions != 3 {
+		t.Errorf("after shrinking: stats = %+v", s)
+	}
+
+	valex.SetParamCacheSize(0)
+	valex.CachedParam(cacheDirective("d"), "p", "z", conv)
+	valex.CachedParam(cacheDirective("d"), "p", "z", conv)
+	if calls["z"] != 2 {
+		t.Errorf("expected size 0 to disable caching, got %d conversions", calls["z"])
+	}
+}
+
+func TestParamCacheHook(t *testing.T) {
+	valex.SwapParamCache(t)
+	valex.SetParamCacheSize(1)
+	var events []valex.ParamCacheEvent
+	valex.SetParamCacheHook(func(ev valex.ParamCacheEvent) {
+		events = append(events, ev)
+	})
+	conv := func(raw string) (string, error) {
+		if raw == "bad" {
+			return "", errors.New("bad")
+		}
+		return raw, nil
+	}
+
+	valex.CachedParam(cacheDirective("regex"), "pattern", "a", conv)
+	valex.CachedParam(cacheDirective("regex"), "pattern", "a", conv)
+	valex.CachedParam(cacheDirective("regex"), "pattern", "b", conv)
+	valex.CachedParam(cacheDirective("regex"), "pattern", "bad", conv)
+	want := []valex.ParamCacheEvent{
+		{Directive: "regex", Param: "pattern"},
+		{Directive: "regex", Param: "pattern", Hit: true},
+		{Directive: "regex", Param: "pattern", Evicted: true},
+		{Directive: "regex", Param: "pattern"},
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+
+	valex.SetParamCacheHook(nil)
+	valex.CachedParam(cacheDirective("regex"), "pattern", "a", conv)
+	if len(events) != len(want) {
+		t.Error("expected no events after removing the hook")
+	}
+}
+
+func TestParamCacheConcurrent(t *testing.T) {
+	valex.SwapParamCache(t)
+	valex.SetParamCacheSize(8)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				raw := strconv.Itoa((g + i) % 16)
+				n, err := valex.CachedParam(cacheDirective("d"), "p", raw, strconv.Atoi)
+				if err != nil || strconv.Itoa(n) != raw {
+					t.Errorf("CachedParam(%q) = %d, %v", raw, n, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if s := valex.ParamCacheStats(); s.Hits+s.Misses != 8*200 || s.Len > 8 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+// upperCode and lowerCode are two directives named "code" whose "value"
+// parameters convert differently, as two registries might register.
+type upperCode struct {
+	Value string `param:"value"`
+}
+
+func (*upperCode) Name() string                      { return "code" }
+func (*upperCode) Mode() tagex.DirectiveMode         { return tagex.EvalMode }
+func (d *upperCode) Handle(s string) (string, error) { return checkCode(s, d.Value) }
+func (d *upperCode) ConvertParam(field reflect.StructField, fv reflect.Value, raw string) error {
+	v, err := valex.CachedParam(d, "value", raw, func(raw string) (string, error) { return strings.ToUpper(raw), nil })
+	fv.SetString(v)
+	return err
+}
+
+type lowerCode struct {
+	Value string `param:"value"`
+}
+
+func (*lowerCode) Name() string                      { return "code" }
+func (*lowerCode) Mode() tagex.DirectiveMode         { return tagex.EvalMode }
+func (d *lowerCode) Handle(s string) (string, error) { return checkCode(s, d.Value) }
+func (d *lowerCode) ConvertParam(field reflect.StructField, fv reflect.Value, raw string) error {
+	v, err := valex.CachedParam(d, "value", raw, func(raw string) (string, error) { return strings.ToLower(raw), nil })
+	fv.SetString(v)
+	return err
+}
+
+func checkCode(s, want string) (string, error) {
+	if s != want {
+		return s, fmt.Errorf("code %q, want %q", s, want)
+	}
+	return s, nil
+}
+
+// Registries that register different directives under one name must each get
+// their own directive's conversion from the shared cache.
+func TestParamCacheKeyedByDirectiveType(t *testing.T) {
+	valex.SwapParamCache(t)
+	upper := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(upper, &upperCode{})
+	lower := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(lower, &lowerCode{})
+
+	type item struct {
+		Code string `val:"code,value=Ab"`
+	}
+	for i := 0; i < 2; i++ {
+		if err := upper.ValidateStruct(&item{Code: "AB"}); err != nil {
+			t.Fatalf("upper: %v", err)
+		}
+		if err := lower.ValidateStruct(&item{Code: "ab"}); err != nil {
+			t.Fatalf("lower: %v", err)
+		}
+	}
+	if s := valex.ParamCacheStats(); s.Len != 2 || s.Hits != 2 {
+		t.Errorf("stats = %+v, want two entries each hit once", s)
+	}
+}
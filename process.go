@@ -0,0 +1,480 @@
+package valex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// This file walks a struct for the "val" tag. It is a fork of tagex's walker
+// (tagex.ProcessStruct): tagex resolves a directive name only against a
+// tagex.Tag's own registry and has no hook for anything else. The fork keeps
+// tagex's field order, field paths, error types, depth limit, and lifecycle
+// hooks, and walker_test.go checks each against tagex. It differs from tagex
+// only where valex needs it to, each covered by a test there:
+//
+//   - names resolve against the Registry, where a name can also be an alias
+//     that expands into a chain (see alias.go);
+//   - a segment can carry a group prefix ("create|update:") and a chain can
+//     start with omitempty, which tagex would report as unknown directives;
+//   - a field mask (ValidateFields) skips the fields it does not select.
+//
+// The extra *tagex.Tag values of WithTags keep tagex's walker: their
+// directives live inside each tagex.Tag, out of valex's reach. processExtraTags
+// hands tagex one field at a time, so they interleave with "val" per field as
+// they would in a single tagex.ProcessStruct call. One difference remains: on a
+// field whose value holds nested fields with an extra tag, a directive on that
+// field is type-checked against the value rather than the declared type, so an
+// interface directive accepts a value that implements it.
+
+// maxDepth bounds recursion so cyclic data returns a *MaxDepthError instead of
+// overflowing the stack, matching tagex.
+const maxDepth = 1000
+
+// directiveFunc runs one registered directive, with args as its parameters, on
+// fieldValue. Errors are *ProcessErrors without a FieldPath.
+type directiveFunc func(args map[string]string, fieldValue reflect.Value) error
+
+// registered is a directive as the Registry stores it: run applies it to a
+// field, params only converts its parameters, and typ is the field type it
+// applies to.
+type registered struct {
+	typ    reflect.Type
+	run    directiveFunc
+	params func(args map[string]string) error
+}
+
+// newRegistered adapts d the way tagex runs a directive: parameters are
+// written to a per-call copy of d, the field is type-checked against T, and a
+// MutMode result is written back.
+func newRegistered[T any](d tagex.Directive[T]) *registered {
+	name := d.Name()
+	return &registered{
+		typ: reflect.TypeFor[T](),
+		run: func(args map[string]string, fieldValue reflect.Value) error {
+			dup, err := processParams(d, args)
+			if err != nil {
+				return err
+			}
+			if err := handleField(dup, fieldValue); err != nil {
+				return &ProcessError{Stage: StageDirective, Directive: name, Cause: err}
+			}
+			return nil
+		},
+		params: func(args map[string]string) error {
+			_, err := processParams(d, args)
+			return err
+		},
+	}
+}
+
+// processParams returns a copy of d with args written to its parameters, or a
+// StageParam *ProcessError.
+func processParams[T any](d tagex.Directive[T], args map[string]string) (tagex.Directive[T], error) {
+	dup := cloneDirective(d)
+	if err := tagex.ProcessParams(dup, args); err != nil {
+		param := ""
+		var missingErr *MissingParamError
+		if errors.As(err, &missingErr) {
+			param = missingErr.Param
+		}
+		var convErr *ConversionError
+		if errors.As(err, &convErr) && param == "" {
+			param = convErr.Param
+		}
+		return nil, &ProcessError{Stage: StageParam, Directive: d.Name(), Param: param, Cause: err}
+	}
+	return dup, nil
+}
+
+// cloneDirective returns a fresh copy of a pointer directive, so per-call
+// parameters never touch the registered template.
+func cloneDirective[T any](d tagex.Directive[T]) tagex.Directive[T] {
+	src := reflect.ValueOf(d)
+	if src.Kind() != reflect.Pointer || src.IsNil() {
+		return d
+	}
+	dup := reflect.New(src.Elem().Type())
+	dup.Elem().Set(src.Elem())
+	return dup.Interface().(tagex.Directive[T])
+}
+
+func handleField[T any](d tagex.Directive[T], fieldValue reflect.Value) (err error) {
+	if !fieldValue.CanInterface() {
+		return &FieldAccessError{Msg: "cannot access field value"}
+	}
+	typ := reflect.TypeFor[T]()
+	if !typ.AssignableTo(fieldValue.Type()) {
+		return &TypeMismatchError{Expected: fieldValue.Type(), Got: typ}
+	}
+	t, ok := fieldValue.Interface().(T)
+	if !ok {
+		return &TypeMismatchError{Expected: fieldValue.Type(), Got: typ}
+	}
+	t, err = d.Handle(t)
+	if err != nil {
+		return &HandleError{Nested: err}
+	}
+	if d.Mode() != tagex.MutMode {
+		return nil
+	}
+	if !fieldValue.CanSet() {
+		return &FieldSetError{Msg: "unable to set field value"}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = &FieldSetError{Msg: fmt.Sprintf("failed to set field value: %v", r)}
+		}
+	}()
+	fieldValue.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// validate is the engine behind ValidateStruct (errs nil: stop at the first
+// failure) and ValidateStructAll (errs non-nil: accumulate field failures).
+// Extra tags run on each field before its "val" chain, inside the same
+// lifecycle hooks. sc selects the groups whose segments run and, with a
+// non-nil mask, the fields (see ValidateFields).
+func (r *Registry) validate(data any, errs *[]error, tags []*tagex.Tag, sc scope) error {
+	val := reflect.ValueOf(data)
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+		return &ProcessError{Stage: StageInput, Cause: &InvalidTargetError{Got: fmt.Sprintf("%T", data)}}
+	}
+	for _, tag := range tags {
+		if tag == nil {
+			return &ProcessError{Stage: StageInput, Cause: &NilTagError{}}
+		}
+	}
+	if err := sc.mask.check(val.Elem().Type()); err != nil {
+		return err
+	}
+
+	sc.tags = tags
+	return runHooks(data, errs, func() error {
+		return r.processStructFields(val.Elem(), "", 0, sc, errs)
+	})
+}
+
+// runHooks runs data's lifecycle hooks around fields, which validates data
+// the way validate describes. Field failures accumulated in errs are joined
+// into the cause the Failure hook sees.
+func runHooks(data any, errs *[]error, fields func() error) error {
+	if err := tagex.InvokePreProcessor(data); err != nil {
+		return &ProcessError{Stage: StagePre, Cause: &HookError{Hook: "Before", Err: err}}
+	}
+
+	cause := fields()
+	if cause == nil && errs != nil && len(*errs) > 0 {
+		cause = errors.Join(*errs...)
+	}
+
+	if cause != nil {
+		if err := tagex.InvokeFailurePostProcessor(data, cause); err != nil {
+			return &ProcessError{Stage: StagePost, Cause: &HookError{Hook: "Failure", Err: err, Cause: cause}}
+		}
+		return cause
+	}
+	if err := tagex.InvokeSuccessPostProcessor(data); err != nil {
+		return &ProcessError{Stage: StagePost, Cause: &HookError{Hook: "Success", Err: err}}
+	}
+	return nil
+}
+
+// processExtraTags runs the extra tags on one field, before its "val" chain,
+// as tagex.ProcessStruct does when it is handed several tags. Their directives
+// are private to each tagex.Tag, so tagex runs them: it is handed a pointer to
+// an unnamed struct{ V T } holding a copy of the field and its struct tag, so
+// no lifecycle hooks run. The copy is stored back for MutMode directives and
+// the "V" field path is replaced with the field's.
+func processExtraTags(field reflect.StructField, fieldValue reflect.Value, fieldPath string, tags []*tagex.Tag, errs *[]error) error {
+	if !hasTag(field.Tag, tags) {
+		return nil
+	}
+	holder := reflect.New(holderType(field, tags))
+	v := holder.Elem().Field(0)
+	v.Set(fieldValue)
+	var err error
+	if errs == nil {
+		err = tagex.ProcessStruct(holder.Interface(), tags...)
+	} else {
+		err = tagex.ProcessStructAll(holder.Interface(), tags...)
+	}
+	if v.Kind() == reflect.Interface && fieldValue.Kind() != reflect.Interface {
+		v = v.Elem()
+	}
+	if v.IsValid() && v.Type() == fieldValue.Type() && fieldValue.CanSet() {
+		fieldValue.Set(v)
+	}
+	if err == nil {
+		return nil
+	}
+	fixHolderError(err, fieldPath, fieldValue.Type())
+	// ProcessStructAll joins field failures; anything else stops the walk.
+	if j, ok := err.(interface{ Unwrap() []error }); ok && errs != nil {
+		*errs = append(*errs, j.Unwrap()...)
+		return nil
+	}
+	return err
+}
+
+func hasTag(st reflect.StructTag, tags []*tagex.Tag) bool {
+	for _, tag := range tags {
+		if _, ok := st.Lookup(tag.Key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var anyType = reflect.TypeFor[any]()
+
+type holderKey struct {
+	typ  reflect.Type
+	tag  reflect.StructTag
+	keys string
+}
+
+var holderTypes sync.Map // holderKey -> reflect.Type
+
+// holderType returns the struct{ V T } type processExtraTags hands tagex for
+// field. V has the field's type, unless a value of it can hold nested fields
+// with one of the tags: tagex would descend into those out of turn, so V is
+// an any, which tagex does not descend into, and which checks a directive's
+// type against the field's value instead of its declared type.
+func holderType(field reflect.StructField, tags []*tagex.Tag) reflect.Type {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tag.Key
+	}
+	k := holderKey{typ: field.Type, tag: field.Tag, keys: strings.Join(keys, "\x00")}
+	if t, ok := holderTypes.Load(k); ok {
+		return t.(reflect.Type)
+	}
+	typ := field.Type
+	if reachesKeys(typ, keys, make(map[reflect.Type]bool)) {
+		typ = anyType
+	}
+	t := reflect.StructOf([]reflect.StructField{{Name: "V", Type: typ, Tag: field.Tag}})
+	holderTypes.Store(k, t)
+	return t
+}
+
+// reachesKeys reports whether tagex, descending into a value of type t, can
+// reach an exported struct field tagged with one of keys.
+func reachesKeys(t reflect.Type, keys []string, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return reachesKeys(t.Elem(), keys, seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			for _, key := range keys {
+				if _, ok := f.Tag.Lookup(key); ok {
+					return true
+				}
+			}
+			if reachesKeys(f.Type, keys, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fixHolderError rewrites the errors tagex reports for a holder as the field's
+// own: the "V" path becomes fieldPath, and a type mismatch against an any
+// holder names the field's type, as tagex would for the field itself.
+func fixHolderError(err error, fieldPath string, typ reflect.Type) {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			fixHolderError(e, fieldPath, typ)
+		}
+		return
+	}
+	var pe *ProcessError
+	if errors.As(err, &pe) && pe.FieldPath == "V" {
+		pe.FieldPath = fieldPath
+	}
+	var tm *TypeMismatchError
+	if errors.As(err, &tm) && tm.Expected == anyType {
+		tm.Expected = typ
+	}
+}
+
+// scope is what one validate call covers: the extra tags, the selected groups
+// and, when mask is non-nil, the fields.
+type scope struct {
+	tags   []*tagex.Tag
+	groups []string
+	mask   fieldMask
+}
+
+// processStructFields applies each exported field's extra tags and "val" chain
+// and descends into its value. With a non-nil mask, only the fields it selects
+// are validated, and only those and the fields leading to them are descended
+// into.
+func (r *Registry) processStructFields(val reflect.Value, path string, depth int, sc scope, errs *[]error) error {
+	for n := 0; n < val.NumField(); n++ {
+		field := val.Type().Field(n)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		fieldValue := val.Field(n)
+		fieldPath := joinPath(path, field.Name)
+		below := sc
+		if sc.mask != nil {
+			switch {
+			case sc.mask.selects(fieldPath):
+				below.mask = nil // every field under a selected one
+			case sc.mask.leadsTo(fieldPath):
+				if err := r.processValue(fieldValue, fieldPath, depth+1, sc, errs); err != nil {
+					return err
+				}
+				continue
+			default:
+				continue
+			}
+		}
+
+		if err := processExtraTags(field, fieldValue, fieldPath, sc.tags, errs); err != nil {
+			return err
+		}
+		if tagValue, ok := field.Tag.Lookup(tagKey); ok {
+			if err := r.processChain(tagValue, fieldValue, sc.groups); err != nil {
+				e := &TagError{TagKey: tagKey, Err: wrapFieldError(fieldPath, err)}
+				if errs == nil {
+					return e
+				}
+				*errs = append(*errs, e)
+			}
+		}
+
+		if err := r.processValue(fieldValue, fieldPath, depth+1, below, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processValue descends into val to reach nested struct fields through
+// pointers, slices, arrays, and maps, with paths such as Items[2].SKU.
+func (r *Registry) processValue(val reflect.Value, path string, depth int, sc scope, errs *[]error) error {
+	if depth > maxDepth {
+		return &ProcessError{Stage: StageStruct, FieldPath: truncatePath(path), Cause: &MaxDepthError{Limit: maxDepth}}
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		return r.processStructFields(val, path, depth, sc, errs)
+	case reflect.Pointer:
+		if val.IsNil() {
+			return nil
+		}
+		return r.processValue(val.Elem(), path, depth+1, sc, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := r.processValue(val.Index(i), fmt.Sprintf("%s[%d]", path, i), depth+1, sc, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range val.MapKeys() {
+			// Map values are not addressable; process a copy and store it back
+			// so MutMode directives take effect.
+			elem := val.MapIndex(key)
+			c := reflect.New(elem.Type()).Elem()
+			c.Set(elem)
+			if err := r.processValue(c, fmt.Sprintf("%s[%v]", path, key.Interface()), depth+1, sc, errs); err != nil {
+				return err
+			}
+			val.SetMapIndex(key, c)
+		}
+	}
+	return nil
+}
+
+// processChain runs a "val" tag value's segments that apply to groups left to
+// right, expanding aliases, and stops at the first failure, or at omitempty
+// for an empty value.
+func (r *Registry) processChain(tagValue string, fieldValue reflect.Value, groups []string) error {
+	segs, err := parseChain(tagValue)
+	if err != nil {
+		return chainError(err)
+	}
+	segs, err = r.expand(segs)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segs {
+		if !seg.applies(groups) {
+			continue
+		}
+		if seg.name == omitEmpty {
+			if isEmpty(fieldValue) {
+				return nil
+			}
+			continue
+		}
+		d, err := r.directive(seg.name)
+		if err != nil {
+			return err
+		}
+		if err := d.run(seg.plainArgs(), fieldValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// directive looks up a registered directive, returning an *UnknownDirectiveError
+// the way tagex reports one when there is none.
+func (r *Registry) directive(name string) (*registered, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.directives[name]
+	if !ok {
+		return nil, &ProcessError{Stage: StageDirective, Directive: name, Cause: &UnknownDirectiveError{Name: name}}
+	}
+	return d, nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// truncatePath shortens the very long path a deep cycle produces, cutting on a
+// field boundary.
+func truncatePath(p string) string {
+	const max = 120
+	if len(p) <= max {
+		return p
+	}
+	if i := strings.LastIndexByte(p[:max], '.'); i > 0 {
+		return p[:i] + ".…(truncated)"
+	}
+	return p[:max] + "…(truncated)"
+}
+
+func wrapFieldError(fieldPath string, err error) error {
+	var pe *ProcessError
+	if errors.As(err, &pe) {
+		if pe.FieldPath == "" {
+			pe.FieldPath = fieldPath
+		}
+		return pe
+	}
+	return &ProcessError{Stage: StageDirective, FieldPath: fieldPath, Cause: err}
+}
@@ -0,0 +1,172 @@
+package valex
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/tedla-brandsema/valex/internal/convert"
+)
+
+// Refined is a value of type T that has passed the validator V. Unlike
+// ValidatedValue, the validator is part of the type, so a decoder can apply it:
+// Refined validates in UnmarshalJSON, UnmarshalText, and Scan, and an invalid
+// value is rejected at whichever boundary it arrives through.
+//
+//	type Contact struct {
+//		Email valex.Refined[string, validators.EmailRule] `json:"email"`
+//	}
+//
+// V is used as its zero value: a rule type with a value-receiver Validate
+// method, such as the validators package's *Rule types, or a pointer to a
+// directive type, such as *validators.UrlValidator, which gets a new zero
+// directive with default parameters. The zero
+// Refined holds the zero T without having validated it; every other way of
+// setting a value — Set, NewRefined, or decoding — validates.
+//
+// Refined marshals as the bare T. Text and SQL decoding defer to T's own
+// encoding.TextUnmarshaler or sql.Scanner when it has one, and otherwise handle
+// strings, booleans, integers, floats, and time.Duration. A SQL NULL is an
+// error; use sql.Null[Refined[T, V]] for a nullable column.
+type Refined[T any, V Validator[T]] struct {
+	value T
+}
+
+// NewRefined validates val with V and returns it as a Refined.
+func NewRefined[T any, V Validator[T]](val T) (Refined[T, V], error) {
+	var r Refined[T, V]
+	err := r.Set(val)
+	return r, err
+}
+
+// Set validates val with V and stores it. On failure the stored value is
+// unchanged.
+func (r *Refined[T, V]) Set(val T) error {
+	if err := refinedValidator[T, V]().Validate(val); err != nil {
+		return err
+	}
+	r.value = val
+	return nil
+}
+
+// refinedValidator returns the zero V, or a pointer to a new zero value when V
+// is a pointer type, whose zero value is nil.
+func refinedValidator[T any, V Validator[T]]() V {
+	var v V
+	if t := reflect.TypeFor[V](); t.Kind() == reflect.Pointer {
+		v = reflect.New(t.Elem()).Interface().(V)
+	}
+	return v
+}
+
+// Get returns the stored value.
+func (r Refined[T, V]) Get() T {
+	return r.value
+}
+
+// String returns the string representation of the stored value.
+func (r Refined[T, V]) String() string {
+	return fmt.Sprintf("%v", r.value)
+}
+
+// MarshalJSON encodes the stored value as T would be encoded.
+func (r Refined[T, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.value)
+}
+
+// UnmarshalJSON decodes data as a T and stores it if it passes V. A JSON null
+// decodes to the zero T, which must pass V too.
+func (r *Refined[T, V]) UnmarshalJSON(data []byte) error {
+	var val T
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	return r.Set(val)
+}
+
+// MarshalText encodes the stored value as text.
+func (r Refined[T, V]) MarshalText() ([]byte, error) {
+	s, err := convert.Format(reflect.ValueOf(r.value))
+	return []byte(s), err
+}
+
+// UnmarshalText decodes text as a T and stores it if it passes V.
+func (r *Refined[T, V]) UnmarshalText(text []byte) error {
+	var val T
+	if err := convert.Text(reflect.ValueOf(&val).Elem(), string(text)); err != nil {
+		return err
+	}
+	return r.Set(val)
+}
+
+// Scan implements sql.Scanner: it converts a column value to a T and stores it
+// if it passes V.
+func (r *Refined[T, V]) Scan(src any) error {
+	var val T
+	if err := scanValue(&val, src); err != nil {
+		return err
+	}
+	return r.Set(val)
+}
+
+// Value implements driver.Valuer, returning T's own driver value when it has
+// one and T's value converted to a driver type otherwise.
+func (r Refined[T, V]) Value() (driver.Value, error) {
+	if v, ok := any(r.value).(driver.Valuer); ok {
+		return v.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(r.value)
+}
+
+var errScanNull = errors.New("valex: cannot scan NULL into a Refined value; use sql.Null")
+
+// scanValue converts a driver value into *dst: through its sql.Scanner, by
+// assignment, from text, or by numeric conversion without overflow.
+func scanValue[T any](dst *T, src any) error {
+	if s, ok := any(dst).(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+	if src == nil {
+		return errScanNull
+	}
+	v := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src)
+	switch s := src.(type) {
+	case []byte:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), s...)) // the driver may reuse s
+			return nil
+		}
+		return convert.Text(v, string(s))
+	case string:
+		return convert.Text(v, s)
+	}
+	if sv.Type().AssignableTo(v.Type()) {
+		v.Set(sv)
+		return nil
+	}
+	switch {
+	case sv.CanInt() && v.CanInt():
+		if v.OverflowInt(sv.Int()) {
+			return fmt.Errorf("valex: value %d overflows %s", sv.Int(), v.Type())
+		}
+		v.SetInt(sv.Int())
+	case sv.CanInt() && v.CanUint():
+		if sv.Int() < 0 || v.OverflowUint(uint64(sv.Int())) {
+			return fmt.Errorf("valex: value %d overflows %s", sv.Int(), v.Type())
+		}
+		v.SetUint(uint64(sv.Int()))
+	case sv.CanInt() && v.CanFloat():
+		v.SetFloat(float64(sv.Int()))
+	case sv.CanFloat() && v.CanFloat():
+		v.SetFloat(sv.Float())
+	case sv.Kind() == reflect.Bool && v.Kind() == reflect.Bool:
+		v.SetBool(sv.Bool())
+	default:
+		return fmt.Errorf("valex: cannot scan %T into %s", src, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package valex_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+type contact struct {
+	Email valex.Refined[string, validators.EmailRule] `json:"email"`
+}
+
+func TestRefinedJSON(t *testing.T) {
+	var c contact
+	if err := json.Unmarshal([]byte(`{"email":"gopher@example.com"}`), &c); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+	if c.Email.Get() != "gopher@example.com" {
+		t.Errorf("expected decoded email, got %q", c.Email.Get())
+	}
+	out, err := json.Marshal(c)
+	if err != nil || string(out) != `{"email":"gopher@example.com"}` {
+		t.Errorf("expected round trip, got %s (err: %v)", out, err)
+	}
+
+	for _, doc := range []string{`{"email":"not-an-email"}`, `{"email":null}`, `{"email":42}`} {
+		var c contact
+		if err := json.Unmarshal([]byte(doc), &c); err == nil {
+			t.Errorf("json.Unmarshal(%s): expected error", doc)
+		}
+	}
+}
+
+type port struct{}
+
+func (port) Validate(n uint16) error {
+	return (&validators.CmpRangeValidator[uint16]{Min: 1, Max: 65535}).Validate(n)
+}
+
+func TestRefinedText(t *testing.T) {
+	var p valex.Refined[uint16, port]
+	if err := p.UnmarshalText([]byte("8080")); err != nil || p.Get() != 8080 {
+		t.Fatalf("UnmarshalText(8080): got %d (err: %v)", p.Get(), err)
+	}
+	for _, in := range []string{"0", "70000", "http"} {
+		if err := p.UnmarshalText([]byte(in)); err == nil {
+			t.Errorf("UnmarshalText(%q): expected error", in)
+		}
+	}
+	if p.Get() != 8080 {
+		t.Errorf("expected 8080 kept after failures, got %d", p.Get())
+	}
+	if text, err := p.MarshalText(); err != nil || string(text) != "8080" {
+		t.Errorf("MarshalText: got %q (err: %v)", text, err)
+	}
+
+	var d valex.Refined[time.Duration, positiveDuration]
+	if err := d.UnmarshalText([]byte("1m30s")); err != nil || d.Get() != 90*time.Second {
+		t.Errorf("UnmarshalText(1m30s): got %v (err: %v)", d.Get(), err)
+	}
+}
+
+type positiveDuration struct{}
+
+func (positiveDuration) Validate(d time.Duration) error {
+	return (&validators.PositiveDurationValidator{}).Validate(d)
+}
+
+func TestRefinedSQL(t *testing.T) {
+	tests := []struct {
+		src any
+		ok  bool
+	}{
+		{"gopher@example.com", true},
+		{[]byte("gopher@example.com"), true},
+		{"not-an-email", false},
+		{nil, false},
+		{int64(1), false},
+	}
+	for _, tt := range tests {
+		var e valex.Refined[string, validators.EmailRule]
+		err := e.Scan(tt.src)
+		if (err == nil) != tt.ok {
+			t.Errorf("Scan(%v): expected ok=%v, got ok=%v (err: %v)", tt.src, tt.ok, err == nil, err)
+		}
+	}
+
+	var n valex.Refined[int8, nonNegativeInt8]
+	if err := n.Scan(int64(300)); err == nil {
+		t.Error("Scan(300) into int8: expected overflow error")
+	}
+	if err := n.Scan(int64(7)); err != nil || n.Get() != 7 {
+		t.Errorf("Scan(7): got %d (err: %v)", n.Get(), err)
+	}
+	if v, err := n.Value(); err != nil || v != int64(7) {
+		t.Errorf("Value: got %v (err: %v)", v, err)
+	}
+
+	var null sql.Null[valex.Refined[string, validators.EmailRule]]
+	if err := null.Scan(nil); err != nil || null.Valid {
+		t.Errorf("sql.Null Scan(nil): got valid=%v (err: %v)", null.Valid, err)
+	}
+}
+
+type nonNegativeInt8 struct{}
+
+func (nonNegativeInt8) Validate(n int8) error {
+	return (&validators.CmpRangeValidator[int8]{Min: 0, Max: 127}).Validate(n)
+}
+
+func TestNewRefined(t *testing.T) {
+	if _, err := valex.NewRefined[string, validators.EmailRule]("gopher@example.com"); err != nil {
+		t.Errorf("expected valid, got %v", err)
+	}
+	e, err := valex.NewRefined[string, validators.EmailRule]("nope")
+	if err == nil || e.Get() != "" {
+		t.Errorf("expected error and zero value, got %q (err: %v)", e.Get(), err)
+	}
+}
+
+func TestRefinedPointerValidator(t *testing.T) {
+	var c struct {
+		Hook valex.Refined[string, *validators.UrlValidator] `json:"hook"`
+	}
+	if err := json.Unmarshal([]byte(`{"hook":"https://example.com/hook"}`), &c); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"hook":"not a url"}`), &c); err == nil {
+		t.Error("expected an invalid URL to be rejected")
+	}
+	if c.Hook.Get() != "https://example.com/hook" {
+		t.Errorf("a rejected value must not be stored, got %q", c.Hook.Get())
+	}
+}
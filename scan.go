@@ -0,0 +1,175 @@
+package valex
+
+import (
+	"strings"
+
+	"github.com/tedla-brandsema/valex/internal/scan"
+)
+
+// This file parses "val" tag values with the grammar tagex uses — segments
+// separated by ';', a directive name followed by ','-separated key=value
+// pairs, and single-quoted values with '' as an escaped quote — into segments
+// the Registry can expand aliases in and dispatch.
+
+// segment is one parsed "name,k=v,..." directive segment. groups holds the
+// names of a "group|group:" prefix; a segment without one applies always.
+type segment struct {
+	groups []string
+	name   string
+	args   []arg
+}
+
+// arg is one key=value pair. param is set when the value is an unquoted
+// placeholder such as $size, which means something only inside an alias chain.
+type arg struct {
+	key, value string
+	param      string
+}
+
+// plainArgs returns the args as the map tagex.ProcessParams takes; a repeated
+// key keeps its last value.
+func (s segment) plainArgs() map[string]string {
+	m := make(map[string]string, len(s.args))
+	for _, a := range s.args {
+		m[a.key] = a.value
+	}
+	return m
+}
+
+// lookup returns the value of the last arg named key.
+func (s segment) lookup(key string) (string, bool) {
+	for i := len(s.args) - 1; i >= 0; i-- {
+		if s.args[i].key == key {
+			return s.args[i].value, true
+		}
+	}
+	return "", false
+}
+
+// String formats s back into tag syntax, quoting values that need it.
+func (s segment) String() string {
+	var b strings.Builder
+	if len(s.groups) > 0 {
+		b.WriteString(strings.Join(s.groups, "|") + ":")
+	}
+	b.WriteString(s.name)
+	for _, a := range s.args {
+		b.WriteByte(',')
+		b.WriteString(a.key)
+		b.WriteByte('=')
+		if a.param != "" {
+			b.WriteString("$" + a.param)
+			continue
+		}
+		b.WriteString(quoteValue(a.value))
+	}
+	return b.String()
+}
+
+// segmentError records which segment failed to parse.
+type segmentError struct {
+	name string
+	err  error
+}
+
+func (e *segmentError) Error() string { return e.err.Error() }
+
+func (e *segmentError) Unwrap() error { return e.err }
+
+// parseChain splits a tag value into segments, dropping empty ones. It returns
+// a *segmentError wrapping a *DirectiveParseError or *ParamParseError.
+func parseChain(tagValue string) ([]segment, error) {
+	parts := scan.SplitTop(tagValue, ';', -1)
+	segs := make([]segment, 0, len(parts))
+	for _, p := range parts {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		seg, err := parseSegment(p)
+		if err != nil {
+			return nil, &segmentError{name: seg.name, err: err}
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// chainError turns a parseChain error into the *ProcessError tagex reports for
+// the same malformed tag.
+func chainError(err error) error {
+	se := err.(*segmentError)
+	stage := StageDirective
+	if _, ok := se.err.(*ParamParseError); ok {
+		stage = StageParam
+	}
+	return &ProcessError{Stage: stage, Directive: se.name, Cause: se.err}
+}
+
+func parseSegment(s string) (segment, error) {
+	parts := scan.SplitTop(s, ',', -1)
+	seg := segment{name: strings.TrimSpace(parts[0])}
+	if groups, name, ok := strings.Cut(seg.name, ":"); ok {
+		seg.name = strings.TrimSpace(name)
+		for _, g := range strings.Split(groups, "|") {
+			g = strings.TrimSpace(g)
+			if g == "" {
+				return seg, &DirectiveParseError{TagValue: s}
+			}
+			seg.groups = append(seg.groups, g)
+		}
+	}
+	if seg.name == "" {
+		return seg, &DirectiveParseError{TagValue: s}
+	}
+	for _, pair := range parts[1:] {
+		a, err := parseArg(pair)
+		if err != nil {
+			return seg, err
+		}
+		seg.args = append(seg.args, a)
+	}
+	return seg, nil
+}
+
+// parseArg splits a pair on its first top-level '='. A bare "key=" is a
+// *ParamParseError; a pair of quotes with nothing between them is an explicit
+// empty string.
+func parseArg(pair string) (arg, error) {
+	parts := scan.SplitTop(pair, '=', 2)
+	if len(parts) == 2 {
+		k := strings.TrimSpace(parts[0])
+		raw := strings.TrimSpace(parts[1])
+		v := scan.Unquote(raw)
+		if k != "" && (v != "" || scan.IsQuoted(raw)) {
+			a := arg{key: k, value: v}
+			if !scan.IsQuoted(raw) && isPlaceholder(raw) {
+				a.param = raw[1:]
+			}
+			return a, nil
+		}
+	}
+	return arg{}, &ParamParseError{Pair: strings.TrimSpace(pair)}
+}
+
+// isPlaceholder reports whether s is $ followed by letters, digits, and
+// underscores.
+func isPlaceholder(s string) bool {
+	if len(s) < 2 || s[0] != '$' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteValue quotes v when it would not survive parseArg unquoted.
+func quoteValue(v string) string {
+	if isPlaceholder(v) {
+		return "'" + v + "'"
+	}
+	return scan.Quote(v, ",;=")
+}
@@ -0,0 +1,42 @@
+// Package stream validates large JSON inputs record by record with the valex
+// engine's "val" tag, without holding the input in memory.
+//
+// Decode reads NDJSON (one JSON value per line) or a top-level JSON array,
+// decodes each record into a struct type, validates it with
+// ValidateStructAll, and passes the result to a callback in stream order:
+//
+//	type Event struct {
+//		ID    string `json:"id" val:"uuid"`
+//		Email string `json:"email" val:"email"`
+//	}
+//
+//	err := stream.Decode(f, func(rec stream.Record[Event]) error {
+//		if rec.Err != nil {
+//			log.Printf("line %d: %v", rec.Line, rec.Fields)
+//			return nil // skip it and keep going
+//		}
+//		return store(rec.Value)
+//	}, stream.WithWorkers(runtime.NumCPU()))
+//
+// # Records
+//
+// Each Record carries the record's index and the line it starts on, the
+// decoded value, and its errors: a decoding failure such as a string where
+// the struct has an int, or the validation failures, also keyed by field path
+// in Fields. Neither stops the stream. Returning an error from the callback
+// does, and Decode returns it; so does malformed JSON, which Decode returns
+// as a *DecodeError with the line it was found on.
+//
+// # Memory and workers
+//
+// Records are read with encoding/json's streaming decoder, one at a time, so
+// memory grows with the largest record rather than the input. WithWorkers
+// decodes and validates on a pool of goroutines while one goroutine reads;
+// results are still delivered one at a time, in order, and at most twice the
+// number of workers are held at once. The callback always runs on the
+// goroutine that called Decode.
+//
+// WithFormat fixes the layout instead of detecting it from the first byte,
+// and WithRegistry validates against an isolated *valex.Registry instead of
+// the default.
+package stream
@@ -0,0 +1,44 @@
+package stream_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/stream"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+// Decode validates each NDJSON line into the struct and reports the invalid
+// ones by line without stopping.
+func ExampleDecode() {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.EmailValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+
+	type Signup struct {
+		Email string `json:"email" val:"email"`
+		Age   int    `json:"age" val:"rangeint,min=18,max=130"`
+	}
+
+	input := `{"email":"ada@example.com","age":36}
+{"email":"not-an-email","age":36}
+{"email":"bo@example.com","age":12}
+`
+	valid := 0
+	err := stream.Decode(strings.NewReader(input), func(rec stream.Record[Signup]) error {
+		if rec.Err != nil {
+			for field := range rec.Fields {
+				fmt.Printf("line %d: %s is invalid\n", rec.Line, field)
+			}
+			return nil
+		}
+		valid++
+		return nil
+	}, stream.WithRegistry(reg), stream.WithWorkers(4))
+	fmt.Println(valid, "valid, err:", err)
+	// Output:
+	// line 2: Email is invalid
+	// line 3: Age is invalid
+	// 1 valid, err: <nil>
+}
@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// source reads the raw records of a stream one at a time, keeping only the
+// record being read and the decoder's buffer in memory.
+type source struct {
+	dec    *json.Decoder
+	lines  *lineCounter
+	skip   int64 // bytes consumed before the decoder started
+	array  bool
+	index  int
+	closed bool // no records remain
+}
+
+func newSource(r io.Reader, format Format) (*source, error) {
+	lines := &lineCounter{r: r, line: 1}
+	br := bufio.NewReader(lines)
+	s := &source{lines: lines}
+
+	// The first byte after any whitespace tells an array from NDJSON.
+	c, err := s.firstByte(br)
+	if errors.Is(err, io.EOF) {
+		if format == Array {
+			return nil, &DecodeError{Line: lines.at(s.skip), Err: io.ErrUnexpectedEOF}
+		}
+		s.closed = true // an empty NDJSON stream has no records
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case Auto:
+		s.array = c == '['
+	case Array:
+		if c != '[' {
+			return nil, &DecodeError{Line: lines.at(s.skip), Err: fmt.Errorf("stream starts with %q, not '['", c)}
+		}
+		s.array = true
+	}
+
+	s.dec = json.NewDecoder(br)
+	if s.array {
+		if _, err := s.dec.Token(); err != nil { // the opening bracket
+			return nil, &DecodeError{Line: lines.at(s.skip), Err: err}
+		}
+	}
+	return s, nil
+}
+
+// firstByte returns the first byte of br that is not JSON whitespace, leaving
+// it unread, and counts the whitespace in s.skip.
+func (s *source) firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c, br.UnreadByte()
+		}
+		s.skip++
+	}
+}
+
+// next returns the next record's bytes and the line it starts on, or io.EOF
+// after the last one. A malformed stream returns a *DecodeError.
+func (s *source) next() (raw json.RawMessage, line int, err error) {
+	if s.closed {
+		return nil, 0, io.EOF
+	}
+	if s.array && !s.dec.More() {
+		if _, err := s.dec.Token(); err != nil { // the closing bracket
+			return nil, 0, s.errorf(err)
+		}
+		s.closed = true
+		if _, err := s.dec.Token(); !errors.Is(err, io.EOF) {
+			if err == nil {
+				err = errors.New("data after the closing ']'")
+			}
+			return nil, 0, s.errorf(err)
+		}
+		return nil, 0, io.EOF
+	}
+	if err := s.dec.Decode(&raw); err != nil {
+		if !s.array && errors.Is(err, io.EOF) {
+			s.closed = true
+			return nil, 0, io.EOF
+		}
+		return nil, 0, s.errorf(err)
+	}
+	// The decoder has just read raw, which excludes the whitespace before it.
+	start := s.skip + s.dec.InputOffset() - int64(len(raw))
+	s.index++
+	return raw, s.lines.at(start), nil
+}
+
+// errorf returns err as a *DecodeError on the line where the record that
+// failed starts: the first byte the decoder has not consumed that is not
+// whitespace.
+func (s *source) errorf(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	offset := s.skip + s.dec.InputOffset()
+	buffered, _ := io.ReadAll(s.dec.Buffered())
+	for _, c := range buffered {
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			break
+		}
+		offset++
+	}
+	return &DecodeError{Index: s.index, Line: s.lines.at(offset), Err: err}
+}
+
+// lineCounter reads through r, noting where each newline is so that the line
+// of a byte offset can be found later. Offsets are queried in increasing
+// order, so newlines before the last query are forgotten, keeping memory
+// bounded by what the decoder has read ahead.
+type lineCounter struct {
+	r        io.Reader
+	read     int64   // bytes read so far
+	newlines []int64 // offsets of newlines not yet passed by a query
+	line     int     // line of the last queried offset
+}
+
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			c.newlines = append(c.newlines, c.read+int64(i))
+		}
+	}
+	c.read += int64(n)
+	return n, err
+}
+
+// at returns the 1-based line holding the byte at offset.
+func (c *lineCounter) at(offset int64) int {
+	i := 0
+	for i < len(c.newlines) && c.newlines[i] < offset {
+		i++
+	}
+	c.line += i
+	c.newlines = c.newlines[i:]
+	return c.line
+}
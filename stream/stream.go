@@ -0,0 +1,233 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+// Format is the layout of a stream.
+type Format int
+
+const (
+	// Auto reads a stream whose first non-whitespace byte is '[' as an Array
+	// and any other stream as NDJSON.
+	Auto Format = iota
+	// NDJSON is a sequence of JSON values separated by whitespace, typically
+	// one per line.
+	NDJSON
+	// Array is a single top-level JSON array whose elements are the records.
+	Array
+)
+
+// Record is the result for one record of a stream.
+type Record[T any] struct {
+	Index int // position in the stream, from 0
+	Line  int // line the record starts on, from 1
+	Value T   // the decoded value, normalized by MutMode directives
+
+	// Err is nil for a valid record. Otherwise it is the error decoding the
+	// record into T, such as a *json.UnmarshalTypeError, or the errors.Join
+	// of its validation failures, as ValidateStructAll returns them.
+	Err error
+
+	// Fields is valex.FieldErrors(Err): each validation failure keyed by
+	// field path. It is nil for a valid record or a decoding failure.
+	Fields map[string]error
+}
+
+// DecodeError reports a stream that is not well-formed JSON, or a read
+// error. The record at Index and those after it are not delivered.
+type DecodeError struct {
+	Index int // records delivered before the error
+	Line  int // line the error was found on, from 1
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("stream: record %d (line %d): %v", e.Index, e.Line, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// Option configures Decode.
+type Option func(*config)
+
+type config struct {
+	format  Format
+	workers int
+	reg     *valex.Registry // nil uses valex's default registry
+}
+
+// WithFormat reads the stream as format instead of detecting it.
+func WithFormat(format Format) Option {
+	return func(c *config) {
+		c.format = format
+	}
+}
+
+// WithWorkers decodes and validates records on n goroutines. Records are
+// still delivered one at a time and in stream order; at most 2n are held in
+// memory. n < 2 does the work on the calling goroutine, the default.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// WithRegistry validates against reg instead of valex's default registry. A
+// nil reg uses the default.
+func WithRegistry(reg *valex.Registry) Option {
+	return func(c *config) {
+		c.reg = reg
+	}
+}
+
+// Decode reads records of type T, a struct type, from r, validates each with
+// ValidateStructAll, and calls fn with the result, in stream order. An
+// invalid record does not stop the stream; fn decides what to do with it.
+//
+// Decode returns nil at the end of the stream, the error fn returns, which
+// stops the stream, or a *DecodeError if the stream is malformed or cannot
+// be read. With WithWorkers, Decode returns fn's error without waiting for a
+// pending read of r; the goroutine reading r exits once that read returns.
+func Decode[T any](r io.Reader, fn func(Record[T]) error, opts ...Option) error {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if k := reflect.TypeFor[T]().Kind(); k != reflect.Struct {
+		return fmt.Errorf("stream: record type must be a struct, got %s", reflect.TypeFor[T]())
+	}
+	src, err := newSource(r, c.format)
+	if err != nil {
+		return err
+	}
+	if c.workers < 2 {
+		return decodeSerial(src, fn, c)
+	}
+	return decodeParallel(src, fn, c)
+}
+
+// job is a record read from the stream, waiting to be decoded.
+type job struct {
+	index int
+	line  int
+	raw   json.RawMessage
+}
+
+func decodeSerial[T any](src *source, fn func(Record[T]) error, c *config) error {
+	for index := 0; ; index++ {
+		raw, line, err := src.next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(process[T](job{index: index, line: line, raw: raw}, c)); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeParallel reads records on one goroutine, processes them on
+// c.workers, and delivers them in order on the caller's. A token is taken for
+// each record read and returned when it is delivered, which bounds the
+// records held at once. When fn fails, the goroutines exit on their own: the
+// workers at once, the reader when its current read returns.
+func decodeParallel[T any](src *source, fn func(Record[T]) error, c *config) error {
+	jobs := make(chan job)
+	results := make(chan Record[T], c.workers)
+	tokens := make(chan struct{}, 2*c.workers)
+	done := make(chan struct{})
+	var readErr error
+
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+			raw, line, err := src.next()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					readErr = err
+				}
+				return
+			}
+			select {
+			case jobs <- job{index: index, line: line, raw: raw}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				select {
+				case results <- process[T](j, c):
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]Record[T])
+	next := 0
+	for rec := range results {
+		pending[rec.Index] = rec
+		for {
+			rec, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-tokens
+			if err := fn(rec); err != nil {
+				// Return without waiting: the reader may be blocked in a read
+				// that does not return until r delivers more data.
+				close(done)
+				return err
+			}
+		}
+	}
+	// results is closed only after the reader has stopped and set readErr.
+	return readErr
+}
+
+// process decodes and validates one record.
+func process[T any](j job, c *config) Record[T] {
+	rec := Record[T]{Index: j.index, Line: j.line}
+	if err := json.Unmarshal(j.raw, &rec.Value); err != nil {
+		rec.Err = err
+		return rec
+	}
+	if c.reg != nil {
+		rec.Err = c.reg.ValidateStructAll(&rec.Value)
+	} else {
+		rec.Err = valex.ValidateStructAll(&rec.Value)
+	}
+	if rec.Err != nil {
+		rec.Fields = valex.FieldErrors(rec.Err)
+	}
+	return rec
+}
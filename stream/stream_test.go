@@ -0,0 +1,202 @@
+package stream_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/stream"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+type user struct {
+	Name string `json:"name" val:"min,size=2"`
+	Age  int    `json:"age" val:"rangeint,min=0,max=150"`
+}
+
+func newRegistry() *valex.Registry {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.MinLengthValidator{})
+	valex.MustRegisterDirectiveTo(reg, &validators.IntRangeValidator{})
+	return reg
+}
+
+// collect decodes input into users and summarizes each record as its index,
+// line, and name, failing fields, or "decode".
+func collect(t *testing.T, input string, opts ...stream.Option) ([]string, error) {
+	t.Helper()
+	var got []string
+	err := stream.Decode(strings.NewReader(input), func(rec stream.Record[user]) error {
+		s := fmt.Sprintf("%d@%d", rec.Index, rec.Line)
+		switch {
+		case rec.Err != nil && rec.Fields == nil:
+			s += " decode"
+		case rec.Err != nil:
+			keys := make([]string, 0, len(rec.Fields))
+			for k := range rec.Fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			s += " " + strings.Join(keys, ",")
+		default:
+			s += " " + rec.Value.Name
+		}
+		got = append(got, s)
+		return nil
+	}, append([]stream.Option{stream.WithRegistry(newRegistry())}, opts...)...)
+	return got, err
+}
+
+const ndjson = `{"name":"ada","age":36}
+{"name":"b","age":200}
+
+{"name":"cy","age":"old"}
+{"name":"dee",
+ "age":7}
+`
+
+const array = `[
+  {"name":"ada","age":36},
+  {"name":"b","age":200},
+  {"name":"cy","age":"old"},
+  {"name":"dee", "age":7}
+]`
+
+func TestDecode(t *testing.T) {
+	wantNDJSON := []string{"0@1 ada", "1@2 Age,Name", "2@4 decode", "3@5 dee"}
+	wantArray := []string{"0@2 ada", "1@3 Age,Name", "2@4 decode", "3@5 dee"}
+	tests := []struct {
+		name  string
+		input string
+		opts  []stream.Option
+		want  []string
+	}{
+		{"ndjson", ndjson, nil, wantNDJSON},
+		{"array", array, nil, wantArray},
+		{"ndjson format", ndjson, []stream.Option{stream.WithFormat(stream.NDJSON)}, wantNDJSON},
+		{"array format", array, []stream.Option{stream.WithFormat(stream.Array)}, wantArray},
+		{"ndjson workers", ndjson, []stream.Option{stream.WithWorkers(3)}, wantNDJSON},
+		{"array workers", array, []stream.Option{stream.WithWorkers(3)}, wantArray},
+		{"empty", "  \n", nil, nil},
+		{"empty array", "[]", nil, nil},
+		{"empty ndjson", "", []stream.Option{stream.WithFormat(stream.NDJSON)}, nil},
+	}
+	for _, tt := range tests {
+		got, err := collect(t, tt.input, tt.opts...)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q (err: %v), want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  []stream.Option
+		index int
+		line  int
+	}{
+		{"{\"name\":\"ada\"}\n{\"name\":", nil, 1, 2},
+		{"[{\"name\":\"ada\"},\n{\"name\":\"bo\"}", nil, 2, 2},
+		{"[{\"name\":\"ada\"}\n{\"name\":\"bo\"}]", nil, 1, 2},
+		{"[{\"name\":\"ada\"}] {}", nil, 1, 1},
+		{"{\"name\":\"ada\"}", []stream.Option{stream.WithFormat(stream.Array)}, 0, 1},
+		{"", []stream.Option{stream.WithFormat(stream.Array)}, 0, 1},
+	}
+	for _, tt := range tests {
+		for _, workers := range []int{1, 2} {
+			opts := append([]stream.Option{stream.WithWorkers(workers)}, tt.opts...)
+			got, err := collect(t, tt.input, opts...)
+			var de *stream.DecodeError
+			if !errors.As(err, &de) || de.Index != tt.index || de.Line != tt.line || len(got) != tt.index {
+				t.Errorf("%q (workers %d): want a *DecodeError at record %d line %d, got %v after %q", tt.input, workers, tt.index, tt.line, err, got)
+			}
+		}
+	}
+}
+
+func TestDecodeStop(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&b, "{\"name\":\"user%d\",\"age\":%d}\n", i, i%200)
+	}
+	stop := errors.New("stop")
+	for _, workers := range []int{1, 4} {
+		var seen []int
+		err := stream.Decode(strings.NewReader(b.String()), func(rec stream.Record[user]) error {
+			seen = append(seen, rec.Index)
+			if rec.Err != nil {
+				return stop
+			}
+			return nil
+		}, stream.WithRegistry(newRegistry()), stream.WithWorkers(workers))
+		if !errors.Is(err, stop) || len(seen) != 152 {
+			t.Errorf("workers %d: want stop after record 151, got %v after %d records", workers, err, len(seen))
+		}
+		for i, index := range seen {
+			if i != index {
+				t.Fatalf("workers %d: record %d delivered as %d", workers, index, i)
+			}
+		}
+	}
+}
+
+// TestDecodeStopPipe stops on a stream whose writer never writes again, so
+// Decode must not wait for the read in progress.
+func TestDecodeStopPipe(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go func() {
+		fmt.Fprintln(pw, `{"name":"ok","age":1}`)
+		fmt.Fprintln(pw, `{"name":"x","age":1}`)
+	}()
+	stop := errors.New("stop")
+	errc := make(chan error, 1)
+	go func() {
+		errc <- stream.Decode(pr, func(rec stream.Record[user]) error {
+			if rec.Err != nil {
+				return stop
+			}
+			return nil
+		}, stream.WithRegistry(newRegistry()), stream.WithWorkers(4))
+	}()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, stop) {
+			t.Fatalf("want stop, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Decode is blocked on the pending read")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	err := stream.Decode(strings.NewReader("1"), func(stream.Record[int]) error { return nil })
+	if err == nil {
+		t.Error("expected an error for a non-struct record type")
+	}
+
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader(`{"name":"ada"}`+"\n"), errReader{readErr})
+	err = stream.Decode(r, func(stream.Record[user]) error { return nil }, stream.WithRegistry(newRegistry()))
+	if !errors.Is(err, readErr) {
+		t.Errorf("want the read error, got %v", err)
+	}
+
+	var syntaxErr *json.SyntaxError
+	_, err = collect(t, "{]")
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("want a wrapped *json.SyntaxError, got %v", err)
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,179 @@
+package valex
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// SyncValidatedValue is a ValidatedValue that is safe for concurrent use, for
+// values such as hot-reloadable configuration that one goroutine replaces while
+// others read. Get is a lock-free atomic load; Set, CompareAndSet, and Update
+// validate the candidate and swap it in only if it passes, so readers never see
+// an invalid value. Functions registered with Subscribe are called after every
+// successful change.
+//
+// The zero value holds the zero T and has no Validator; set Validator before
+// first use and do not change it afterwards. A SyncValidatedValue must not be
+// copied after first use.
+type SyncValidatedValue[T any] struct {
+	Validator Validator[T]
+
+	value     atomic.Pointer[T]
+	mu        sync.Mutex // serializes changes and guards the fields below
+	subs      []*subscription[T]
+	nextID    uint64
+	pending   []change[T] // changes not yet delivered to subscribers
+	notifying bool        // a goroutine is delivering pending
+}
+
+type subscription[T any] struct {
+	id uint64
+	fn func(old, new T)
+}
+
+// change is a successful change, with the subscribers at the time it was made.
+type change[T any] struct {
+	old, new T
+	subs     []*subscription[T]
+}
+
+// Set validates val and stores it. On failure the stored value is unchanged
+// and the validation error is returned.
+func (v *SyncValidatedValue[T]) Set(val T) error {
+	if v.Validator == nil {
+		return ErrNoValidator
+	}
+	if err := v.Validator.Validate(val); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	v.swap(val)
+	v.mu.Unlock()
+	v.notify()
+	return nil
+}
+
+// Get returns the stored value.
+func (v *SyncValidatedValue[T]) Get() T {
+	if p := v.value.Load(); p != nil {
+		return *p
+	}
+	var zero T
+	return zero
+}
+
+// CompareAndSet stores new if the stored value equals old and new passes
+// validation. It reports whether new was stored; a false result with a nil
+// error means the stored value was not old. Values are compared with ==, so,
+// as with atomic.Value.CompareAndSwap, it panics if T's values are not
+// comparable.
+func (v *SyncValidatedValue[T]) CompareAndSet(old, new T) (bool, error) {
+	if v.Validator == nil {
+		return false, ErrNoValidator
+	}
+	v.mu.Lock()
+	if any(v.Get()) != any(old) {
+		v.mu.Unlock()
+		return false, nil
+	}
+	if err := v.Validator.Validate(new); err != nil {
+		v.mu.Unlock()
+		return false, err
+	}
+	v.swap(new)
+	v.mu.Unlock()
+	v.notify()
+	return true, nil
+}
+
+// Update replaces the stored value with fn applied to it, if the result passes
+// validation. fn runs with other changes held off, so read-modify-write updates
+// are not lost to concurrent ones; it must not call Set, CompareAndSet, or
+// Update on v.
+func (v *SyncValidatedValue[T]) Update(fn func(T) T) error {
+	if v.Validator == nil {
+		return ErrNoValidator
+	}
+	v.mu.Lock()
+	val := fn(v.Get())
+	if err := v.Validator.Validate(val); err != nil {
+		v.mu.Unlock()
+		return err
+	}
+	v.swap(val)
+	v.mu.Unlock()
+	v.notify()
+	return nil
+}
+
+// Subscribe registers fn to be called with the previous and the new value
+// after each successful Set, CompareAndSet, or Update, and returns a function
+// that removes it. Subscribers run in the order they subscribed, after the
+// change has released v's lock, so they may call any method of v, including
+// changing it or unsubscribing. Changes are delivered one at a time in the
+// order they were made: a change made while another is being delivered, by a
+// subscriber or another goroutine, is delivered after it by the delivering
+// goroutine, so Set can return before its own notification runs.
+func (v *SyncValidatedValue[T]) Subscribe(fn func(old, new T)) (unsubscribe func()) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.nextID++
+	id := v.nextID
+	v.subs = append(v.subs, &subscription[T]{id: id, fn: fn})
+	return func() {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		for i, s := range v.subs {
+			if s.id == id {
+				v.subs = append(v.subs[:i:i], v.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// String returns the string representation of the stored value.
+func (v *SyncValidatedValue[T]) String() string {
+	return fmt.Sprintf("%v", v.Get())
+}
+
+// swap stores val and queues the change for notify. v.mu must be held.
+func (v *SyncValidatedValue[T]) swap(val T) {
+	old := v.Get()
+	v.value.Store(&val)
+	if len(v.subs) > 0 {
+		v.pending = append(v.pending, change[T]{old: old, new: val, subs: v.subs})
+	}
+}
+
+// notify delivers pending changes without holding v.mu, unless another call
+// is already delivering them. v.mu must not be held.
+func (v *SyncValidatedValue[T]) notify() {
+	v.mu.Lock()
+	if v.notifying {
+		v.mu.Unlock()
+		return
+	}
+	v.notifying = true
+	done := false
+	defer func() {
+		if !done { // a subscriber panicked
+			v.mu.Lock()
+			v.notifying = false
+			v.mu.Unlock()
+		}
+	}()
+	for len(v.pending) > 0 {
+		c := v.pending[0]
+		v.pending = v.pending[1:]
+		v.mu.Unlock()
+		for _, s := range c.subs {
+			s.fn(c.old, c.new)
+		}
+		v.mu.Lock()
+	}
+	v.notifying = false
+	done = true
+	v.mu.Unlock()
+}
@@ -0,0 +1,144 @@
+package valex_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+var nonNegative = valex.ValidatorFunc[int](func(val int) error {
+	if val < 0 {
+		return errors.New("must be non-negative")
+	}
+	return nil
+})
+
+func TestSyncValidatedValue(t *testing.T) {
+	v := &valex.SyncValidatedValue[int]{Validator: nonNegative}
+	if v.Get() != 0 {
+		t.Fatalf("expected zero value, got %d", v.Get())
+	}
+	if err := v.Set(5); err != nil || v.Get() != 5 {
+		t.Fatalf("Set(5): got %d (err: %v)", v.Get(), err)
+	}
+	if err := v.Set(-1); err == nil || v.Get() != 5 {
+		t.Fatalf("Set(-1): expected error and 5 kept, got %d (err: %v)", v.Get(), err)
+	}
+
+	if ok, err := v.CompareAndSet(4, 6); ok || err != nil {
+		t.Errorf("CompareAndSet(4, 6): expected false, nil, got %v, %v", ok, err)
+	}
+	if ok, err := v.CompareAndSet(5, -6); ok || err == nil {
+		t.Errorf("CompareAndSet(5, -6): expected validation error, got %v, %v", ok, err)
+	}
+	if ok, err := v.CompareAndSet(5, 6); !ok || err != nil || v.Get() != 6 {
+		t.Errorf("CompareAndSet(5, 6): expected 6 stored, got %d (%v, %v)", v.Get(), ok, err)
+	}
+
+	if err := v.Update(func(n int) int { return n - 10 }); err == nil || v.Get() != 6 {
+		t.Errorf("Update to -4: expected error and 6 kept, got %d (err: %v)", v.Get(), err)
+	}
+	if err := v.Update(func(n int) int { return n * 2 }); err != nil || v.Get() != 12 {
+		t.Errorf("Update to 12: got %d (err: %v)", v.Get(), err)
+	}
+
+	var unset valex.SyncValidatedValue[int]
+	if err := unset.Set(1); !errors.Is(err, valex.ErrNoValidator) {
+		t.Errorf("expected ErrNoValidator, got %v", err)
+	}
+}
+
+func TestSyncValidatedValueSubscribe(t *testing.T) {
+	v := &valex.SyncValidatedValue[int]{Validator: nonNegative}
+	var got [][2]int
+	unsubscribe := v.Subscribe(func(old, new int) {
+		if v.Get() != new {
+			t.Errorf("subscriber saw Get() = %d, want %d", v.Get(), new)
+		}
+		got = append(got, [2]int{old, new})
+	})
+
+	_ = v.Set(1)
+	_ = v.Set(-1) // rejected: no notification
+	_, _ = v.CompareAndSet(1, 2)
+	_ = v.Update(func(n int) int { return n + 1 })
+	unsubscribe()
+	_ = v.Set(4)
+
+	want := [][2]int{{0, 1}, {1, 2}, {2, 3}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("notification %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+// Run with -race: concurrent Update calls must not lose increments while
+// readers load the value.
+func TestSyncValidatedValueConcurrent(t *testing.T) {
+	v := &valex.SyncValidatedValue[int]{Validator: nonNegative}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = v.Update(func(n int) int { return n + 1 })
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if v.Get() < 0 {
+					t.Error("read an invalid value")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v.Get() != 800 {
+		t.Errorf("expected 800, got %d", v.Get())
+	}
+}
+
+// Subscribers may use v: reading it, changing it, subscribing, and
+// unsubscribing must not deadlock, and changes keep their order.
+func TestSyncValidatedValueReentrantSubscriber(t *testing.T) {
+	v := &valex.SyncValidatedValue[int]{Validator: nonNegative}
+	var got [][2]int
+	var unsubscribe func()
+	unsubscribe = v.Subscribe(func(old, new int) {
+		got = append(got, [2]int{old, new})
+		if new == 1 {
+			_ = v.Set(v.Get() + 1)
+			v.Subscribe(func(old, new int) { got = append(got, [2]int{-old, -new}) })
+		}
+		if new == 2 {
+			unsubscribe()
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = v.Set(1)
+		_ = v.Set(3)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a re-entrant subscriber deadlocked")
+	}
+
+	want := [][2]int{{0, 1}, {1, 2}, {-2, -3}} // the new subscriber sees only later changes
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -1,6 +1,9 @@
 package valex
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/tedla-brandsema/tagex"
 )
 
@@ -12,22 +15,38 @@ const tagKey = "val"
 // when you need an independent one — for test isolation, or to run two
 // differently-configured validators in the same process.
 type Registry struct {
-	tag *tagex.Tag
+	mu         sync.RWMutex
+	directives map[string]*registered
+	aliases    map[string]*alias
+	env        *Env
+}
+
+// NewRegistry returns a new, empty Registry with its own directive set,
+// configured by opts (for example WithClock).
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{directives: make(map[string]*registered), env: &Env{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// NewRegistry returns a new, empty Registry with its own directive set.
-func NewRegistry() *Registry {
-	return &Registry{tag: tagex.NewTag(tagKey)}
+// Env returns the environment the registry hands to directives that implement
+// EnvReceiver.
+func (r *Registry) Env() *Env {
+	return r.env
 }
 
 // defaultRegistry backs the package-level functions.
 var defaultRegistry = NewRegistry()
 
-// ValidateStruct validates struct fields against the registry's "val" directives.
-// It returns nil when the struct is valid. Additional tagex.Tag values can be
-// provided to process more tags in the same pass.
+// ValidateStruct validates struct fields against the registry's "val" directives
+// and aliases. It returns nil when the struct is valid. Additional tagex.Tag
+// values can be provided to process more tags in the same call; they run on each
+// field before its "val" directives. Only segments without a group prefix run; see
+// ValidateStructWith to select groups.
 func (r *Registry) ValidateStruct(data any, tags ...*tagex.Tag) error {
-	return tagex.ProcessStruct(data, append(tags, r.tag)...)
+	return r.validate(data, nil, tags, scope{})
 }
 
 // ValidateStructAll is like ValidateStruct but does not stop at the first
@@ -35,27 +54,58 @@ func (r *Registry) ValidateStruct(data any, tags ...*tagex.Tag) error {
 // errors (nil when all pass). Use FieldErrors to turn the result into a map
 // keyed by field path.
 func (r *Registry) ValidateStructAll(data any, tags ...*tagex.Tag) error {
-	return tagex.ProcessStructAll(data, append(tags, r.tag)...)
+	errs := make([]error, 0)
+	return r.validate(data, &errs, tags, scope{})
+}
+
+// ValidateStructWith is like ValidateStruct but configured by options: Groups
+// selects the groups whose rules run, Fields the fields to validate, and
+// WithTags processes more tags in the same call.
+func (r *Registry) ValidateStructWith(data any, opts ...ValidateOption) error {
+	c := newValidateConfig(opts)
+	return r.validate(data, nil, c.tags, c.scope())
+}
+
+// ValidateStructAllWith is like ValidateStructWith but does not stop at the
+// first failure, in the way of ValidateStructAll.
+func (r *Registry) ValidateStructAllWith(data any, opts ...ValidateOption) error {
+	c := newValidateConfig(opts)
+	errs := make([]error, 0)
+	return r.validate(data, &errs, c.tags, c.scope())
 }
 
 // RegisterDirectiveTo registers a directive on r. It is a free function rather
 // than a method because Go methods cannot have type parameters. It returns
 // *EmptyDirectiveNameError if the directive's Name is blank, or
-// *DuplicateDirectiveError if that name is already registered on r; use
-// MustRegisterDirectiveTo to panic on these instead.
+// *DuplicateDirectiveError if that name is already registered on r as a
+// directive or an alias; use MustRegisterDirectiveTo to panic on these instead.
+// A directive implementing EnvReceiver is registered as a copy bound to r's Env.
 func RegisterDirectiveTo[T any](r *Registry, d tagex.Directive[T]) error {
-	return tagex.RegisterDirective(r.tag, d)
+	name := d.Name()
+	if strings.TrimSpace(name) == "" {
+		return &EmptyDirectiveNameError{}
+	}
+	entry := newRegistered(bindEnv(r.env, d))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.nameTaken(name) {
+		return &DuplicateDirectiveError{Name: name}
+	}
+	r.directives[name] = entry
+	return nil
 }
 
 // MustRegisterDirectiveTo is like RegisterDirectiveTo but panics if registration
 // fails — the convenient choice for registering directives once at startup.
 func MustRegisterDirectiveTo[T any](r *Registry, d tagex.Directive[T]) {
-	tagex.MustRegisterDirective(r.tag, d)
+	if err := RegisterDirectiveTo(r, d); err != nil {
+		panic(err)
+	}
 }
 
 // ValidateStruct validates struct fields using the default registry's "val"
-// directives. It returns nil when the struct is valid. Additional tagex.Tag
-// values can be provided to process more tags in the same pass.
+// directives and aliases. It returns nil when the struct is valid. Additional
+// tagex.Tag values can be provided to process more tags in the same call.
 func ValidateStruct(data any, tags ...*tagex.Tag) error {
 	return defaultRegistry.ValidateStruct(data, tags...)
 }
@@ -68,6 +118,18 @@ func ValidateStructAll(data any, tags ...*tagex.Tag) error {
 	return defaultRegistry.ValidateStructAll(data, tags...)
 }
 
+// ValidateStructWith validates data against the default registry, configured
+// by options; see Registry.ValidateStructWith.
+func ValidateStructWith(data any, opts ...ValidateOption) error {
+	return defaultRegistry.ValidateStructWith(data, opts...)
+}
+
+// ValidateStructAllWith is like ValidateStructWith but does not stop at the
+// first failure; see Registry.ValidateStructAllWith.
+func ValidateStructAllWith(data any, opts ...ValidateOption) error {
+	return defaultRegistry.ValidateStructAllWith(data, opts...)
+}
+
 // RegisterDirective registers a directive on the default registry for use with
 // the "val" struct tag. It returns *EmptyDirectiveNameError if the directive's
 // Name is blank, or *DuplicateDirectiveError if that name is already registered
@@ -2,8 +2,10 @@ package valex_test
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tedla-brandsema/tagex"
 	"github.com/tedla-brandsema/valex"
@@ -197,3 +199,111 @@ func TestRegistryIsolation(t *testing.T) {
 		t.Fatal("empty registry should not know the directive")
 	}
 }
+
+// nowDirective ("valex_test_now") passes only when the field equals the
+// registry clock's current time, so a test can tell which Env it was bound to.
+type nowDirective struct {
+	env *valex.Env
+}
+
+func (d *nowDirective) SetEnv(env *valex.Env)     { d.env = env }
+func (d *nowDirective) Name() string              { return "valex_test_now" }
+func (d *nowDirective) Mode() tagex.DirectiveMode { return tagex.EvalMode }
+func (d *nowDirective) Handle(val time.Time) (time.Time, error) {
+	if now := d.env.Now(); !val.Equal(now) {
+		return val, fmt.Errorf("got %v, registry clock says %v", val, now)
+	}
+	return val, nil
+}
+
+func TestRegistryClock(t *testing.T) {
+	type Stamp struct {
+		At time.Time `val:"valex_test_now"`
+	}
+	t1 := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	// One directive value registered on two registries is bound to each one's
+	// clock independently.
+	d := &nowDirective{}
+	a := valex.NewRegistry(valex.WithClock(valex.ClockFunc(func() time.Time { return t1 })))
+	b := valex.NewRegistry(valex.WithClock(valex.ClockFunc(func() time.Time { return t2 })))
+	valex.MustRegisterDirectiveTo(a, d)
+	valex.MustRegisterDirectiveTo(b, d)
+
+	if err := a.ValidateStruct(&Stamp{At: t1}); err != nil {
+		t.Fatalf("a should see its own clock: %v", err)
+	}
+	if err := b.ValidateStruct(&Stamp{At: t2}); err != nil {
+		t.Fatalf("b should see its own clock: %v", err)
+	}
+	if err := a.ValidateStruct(&Stamp{At: t2}); err == nil {
+		t.Fatal("a must not see b's clock")
+	}
+	if d.env != nil {
+		t.Fatal("registering must not modify the caller's directive")
+	}
+	if got := a.Env().Now(); !got.Equal(t1) {
+		t.Fatalf("a.Env().Now() = %v, want %v", got, t1)
+	}
+}
+
+func TestEnvDefaultsToSystemClock(t *testing.T) {
+	var nilEnv *valex.Env
+	for _, env := range []*valex.Env{nilEnv, valex.NewRegistry().Env()} {
+		before := time.Now()
+		got := env.Now()
+		if got.Before(before) || got.After(time.Now()) {
+			t.Fatalf("Env.Now() = %v, expected the system time", got)
+		}
+	}
+}
+
+type envKey struct{}
+
+// lookupDirective ("valex_test_lookup") accepts only values present in the
+// allowlist the registry's environment carries under envKey.
+type lookupDirective struct {
+	env *valex.Env
+}
+
+func (d *lookupDirective) SetEnv(env *valex.Env)     { d.env = env }
+func (d *lookupDirective) Name() string              { return "valex_test_lookup" }
+func (d *lookupDirective) Mode() tagex.DirectiveMode { return tagex.EvalMode }
+func (d *lookupDirective) Handle(val string) (string, error) {
+	allowed, _ := d.env.Value(envKey{}).(map[string]bool)
+	if !allowed[val] {
+		return val, fmt.Errorf("%q is not allowed", val)
+	}
+	return val, nil
+}
+
+func TestRegistryWithValue(t *testing.T) {
+	type Box struct {
+		S string `val:"valex_test_lookup"`
+	}
+	reg := valex.NewRegistry(
+		valex.WithValue(envKey{}, map[string]bool{"old": true}),
+		valex.WithValue(envKey{}, map[string]bool{"a": true}), // later value wins
+	)
+	valex.MustRegisterDirectiveTo(reg, &lookupDirective{})
+
+	if err := reg.ValidateStruct(&Box{S: "a"}); err != nil {
+		t.Fatalf("expected a to be allowed, got %v", err)
+	}
+	if err := reg.ValidateStruct(&Box{S: "old"}); err == nil {
+		t.Fatal("expected the replaced value to be gone")
+	}
+
+	// A registry without the value rejects everything.
+	other := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(other, &lookupDirective{})
+	if err := other.ValidateStruct(&Box{S: "a"}); err == nil {
+		t.Fatal("expected a registry without the value to reject")
+	}
+
+	var nilEnv *valex.Env
+	if got := nilEnv.Value(envKey{}); got != nil {
+		t.Fatalf("nil Env Value = %v, want nil", got)
+	}
+}
@@ -0,0 +1,434 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// LuhnValidator validates that a string of digits passes the Luhn (mod 10)
+// checksum used by card numbers, IMEIs, and many national identifiers.
+type LuhnValidator struct{}
+
+// Validate checks whether the value passes the Luhn checksum.
+func (v *LuhnValidator) Validate(val string) error {
+	if !isDigits(val) || len(val) < 2 {
+		return fmt.Errorf("value %q is not a string of digits", val)
+	}
+	if !luhnValid(val) {
+		return fmt.Errorf("value %q fails the Luhn checksum", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *LuhnValidator) Name() string {
+	return "luhn"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *LuhnValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *LuhnValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// Mod11Validator validates that a string passes the weighted mod-11 checksum:
+// the last character is the check digit, and the payload digits are weighted
+// 2, 3, 4, … from the right. A check value of 10 is written 'X', as in ISBN-10.
+type Mod11Validator struct{}
+
+// Validate checks whether the value passes the mod-11 checksum.
+func (v *Mod11Validator) Validate(val string) error {
+	if len(val) < 2 || !isDigits(val[:len(val)-1]) {
+		return fmt.Errorf("value %q is not a string of digits", val)
+	}
+	if !mod11Valid(val) {
+		return fmt.Errorf("value %q fails the mod-11 checksum", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *Mod11Validator) Name() string {
+	return "mod11"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *Mod11Validator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *Mod11Validator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// Card brands reported by CardBrand and accepted by CreditCardValidator's
+// brands parameter.
+const (
+	BrandVisa       = "visa"
+	BrandMastercard = "mastercard"
+	BrandAmex       = "amex"
+	BrandDiscover   = "discover"
+	BrandDiners     = "diners"
+	BrandJCB        = "jcb"
+	BrandUnionPay   = "unionpay"
+	BrandMaestro    = "maestro"
+)
+
+// cardBrands lists issuer prefix ranges and valid lengths. Order matters:
+// more specific ranges come before broader ones that overlap them.
+var cardBrands = []struct {
+	brand   string
+	ranges  [][2]int // inclusive prefix ranges, compared on the prefix's own digit count
+	lengths []int
+}{
+	{BrandAmex, [][2]int{{34, 34}, {37, 37}}, []int{15}},
+	{BrandDiners, [][2]int{{300, 305}, {36, 36}, {38, 39}}, []int{14, 16, 17, 18, 19}},
+	{BrandJCB, [][2]int{{3528, 3589}}, []int{16, 17, 18, 19}},
+	{BrandDiscover, [][2]int{{6011, 6011}, {644, 649}, {65, 65}}, []int{16, 17, 18, 19}},
+	{BrandUnionPay, [][2]int{{62, 62}}, []int{16, 17, 18, 19}},
+	{BrandMastercard, [][2]int{{51, 55}, {2221, 2720}}, []int{16}},
+	{BrandMaestro, [][2]int{{50, 50}, {56, 69}}, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{BrandVisa, [][2]int{{4, 4}}, []int{13, 16, 19}},
+}
+
+// CardBrand returns the brand of a card number (one of the Brand constants) by
+// its issuer prefix and length, or "" when no known brand matches. Spaces and
+// hyphens are ignored. It does not check the Luhn digit.
+func CardBrand(number string) string {
+	digits := stripSeparators(number)
+	if !isDigits(digits) {
+		return ""
+	}
+	for _, b := range cardBrands {
+		if !slices.Contains(b.lengths, len(digits)) {
+			continue
+		}
+		for _, r := range b.ranges {
+			if prefixInRange(digits, r) {
+				return b.brand
+			}
+		}
+	}
+	return ""
+}
+
+func prefixInRange(digits string, r [2]int) bool {
+	n := len(fmt.Sprint(r[0]))
+	if len(digits) < n {
+		return false
+	}
+	p := 0
+	for _, c := range digits[:n] {
+		p = p*10 + int(c-'0')
+	}
+	return p >= r[0] && p <= r[1]
+}
+
+// CreditCardValidator validates a payment card number: 12 to 19 digits
+// (spaces and hyphens allowed as separators) passing the Luhn checksum. If
+// Brands is set, the number's brand (see CardBrand) must be one of them.
+type CreditCardValidator struct {
+	Brands []string `param:"brands,required=false"`
+}
+
+// Validate checks whether the value is a valid card number.
+func (v *CreditCardValidator) Validate(val string) error {
+	digits := stripSeparators(val)
+	if !isDigits(digits) || len(digits) < 12 || len(digits) > 19 {
+		return fmt.Errorf("value is not a valid card number")
+	}
+	if !luhnValid(digits) {
+		return fmt.Errorf("card number fails the Luhn checksum")
+	}
+	if len(v.Brands) > 0 {
+		brand := CardBrand(digits)
+		if brand == "" || !slices.Contains(v.Brands, brand) {
+			return fmt.Errorf("card brand is not one of %s", strings.Join(v.Brands, ", "))
+		}
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *CreditCardValidator) Name() string {
+	return "creditcard"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *CreditCardValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the brands parameter.
+func (v *CreditCardValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return parsePipeList(v, field, fieldValue, raw, func(item string) (string, error) {
+		item = strings.ToLower(item)
+		for _, b := range cardBrands {
+			if b.brand == item {
+				return item, nil
+			}
+		}
+		return "", fmt.Errorf("unknown card brand %q", item)
+	})
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *CreditCardValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// ibanLengths is the IBAN length per country, from the SWIFT IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30,
+	"KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21,
+	"LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27,
+	"SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29,
+	"VA": 22, "VG": 24, "XK": 20,
+}
+
+// IBANValidator validates an International Bank Account Number: a known
+// country code, that country's length, and the ISO 7064 mod-97 checksum.
+// Spaces (as in the printed form "NL91 ABNA 0417 1643 00") are ignored, and
+// letters are compared case-insensitively.
+type IBANValidator struct{}
+
+// Validate checks whether the value is a valid IBAN.
+func (v *IBANValidator) Validate(val string) error {
+	iban := strings.ToUpper(strings.ReplaceAll(val, " ", ""))
+	if len(iban) < 4 {
+		return fmt.Errorf("value %q is not a valid IBAN", val)
+	}
+	want, ok := ibanLengths[iban[:2]]
+	if !ok {
+		return fmt.Errorf("IBAN country code %q is not supported", iban[:2])
+	}
+	if len(iban) != want {
+		return fmt.Errorf("IBAN for %s must be %d characters, got %d", iban[:2], want, len(iban))
+	}
+	if !isDigits(iban[2:4]) {
+		return fmt.Errorf("value %q is not a valid IBAN", val)
+	}
+	// Move the country code and check digits to the end, map letters to
+	// 10..35, and reduce mod 97 digit by digit to avoid big integers.
+	rem := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		default:
+			return fmt.Errorf("value %q is not a valid IBAN", val)
+		}
+	}
+	if rem != 1 {
+		return fmt.Errorf("IBAN %q fails the mod-97 checksum", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *IBANValidator) Name() string {
+	return "iban"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *IBANValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *IBANValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// ISBNValidator validates an ISBN-10 or ISBN-13, ignoring hyphens and spaces.
+// If Version is 10 or 13, only that form is accepted; 0 accepts either.
+type ISBNValidator struct {
+	Version int `param:"version,required=false"`
+}
+
+// Validate checks whether the value is a valid ISBN.
+func (v *ISBNValidator) Validate(val string) error {
+	isbn := stripSeparators(val)
+	switch v.Version {
+	case 0, 10, 13:
+	default:
+		return fmt.Errorf("invalid ISBN version %d", v.Version)
+	}
+	switch {
+	case len(isbn) == 10 && v.Version != 13:
+		if isDigits(isbn[:9]) && mod11Valid(isbn) {
+			return nil
+		}
+	case len(isbn) == 13 && v.Version != 10:
+		if isDigits(isbn) && gtinValid(isbn) && (strings.HasPrefix(isbn, "978") || strings.HasPrefix(isbn, "979")) {
+			return nil
+		}
+	}
+	if v.Version != 0 {
+		return fmt.Errorf("value %q is not a valid ISBN-%d", val, v.Version)
+	}
+	return fmt.Errorf("value %q is not a valid ISBN", val)
+}
+
+// Name returns the directive identifier.
+func (v *ISBNValidator) Name() string {
+	return "isbn"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *ISBNValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *ISBNValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// EANValidator validates an EAN-8 or EAN-13 barcode number and its check digit.
+type EANValidator struct{}
+
+// Validate checks whether the value is a valid EAN-8 or EAN-13.
+func (v *EANValidator) Validate(val string) error {
+	if (len(val) != 8 && len(val) != 13) || !isDigits(val) || !gtinValid(val) {
+		return fmt.Errorf("value %q is not a valid EAN", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *EANValidator) Name() string {
+	return "ean"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *EANValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *EANValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// UPCValidator validates a 12-digit UPC-A barcode number and its check digit.
+type UPCValidator struct{}
+
+// Validate checks whether the value is a valid UPC-A.
+func (v *UPCValidator) Validate(val string) error {
+	if len(val) != 12 || !isDigits(val) || !gtinValid(val) {
+		return fmt.Errorf("value %q is not a valid UPC", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *UPCValidator) Name() string {
+	return "upc"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *UPCValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *UPCValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// stripSeparators removes the spaces and hyphens people type between digit
+// groups.
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// luhnValid reports whether an all-digit string passes the Luhn checksum.
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// mod11Valid reports whether s (digits, with a final digit or 'X' check
+// character) passes the weighted mod-11 checksum.
+func mod11Valid(s string) bool {
+	check := s[len(s)-1]
+	var want int
+	switch {
+	case check >= '0' && check <= '9':
+		want = int(check - '0')
+	case check == 'X' || check == 'x':
+		want = 10
+	default:
+		return false
+	}
+	sum := 0
+	weight := 2
+	for i := len(s) - 2; i >= 0; i-- {
+		sum += int(s[i]-'0') * weight
+		weight++
+	}
+	return (11-sum%11)%11 == want
+}
+
+// gtinValid reports whether an all-digit EAN/UPC/GTIN passes its mod-10 check:
+// digits are weighted 3 and 1 alternately from the right of the payload.
+func gtinValid(digits string) bool {
+	sum := 0
+	weight := 3
+	for i := len(digits) - 2; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight = 4 - weight
+	}
+	return (10-sum%10)%10 == int(digits[len(digits)-1]-'0')
+}
@@ -0,0 +1,198 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestLuhnValidator(t *testing.T) {
+	v := &LuhnValidator{}
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"79927398713", true},
+		{"79927398710", false},
+		{"4111111111111111", true},
+		{"4111 1111 1111 1111", false},
+		{"", false},
+		{"0", false},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestMod11Validator(t *testing.T) {
+	v := &Mod11Validator{}
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"0306406152", true},
+		{"080442957X", true},
+		{"0306406153", false},
+		{"X", false},
+		{"12A4", false},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestCardBrand(t *testing.T) {
+	tests := []struct {
+		input string
+		brand string
+	}{
+		{"4111111111111111", BrandVisa},
+		{"378282246310005", BrandAmex},
+		{"5555555555554444", BrandMastercard},
+		{"2223003122003222", BrandMastercard},
+		{"6011111111111117", BrandDiscover},
+		{"3530111333300000", BrandJCB},
+		{"30569309025904", BrandDiners},
+		{"6200000000000005", BrandUnionPay},
+		{"6759 6498 2643 8453", BrandMaestro},
+		{"9999999999999999", ""},
+		{"4111", ""},
+	}
+	for _, tc := range tests {
+		if got := CardBrand(tc.input); got != tc.brand {
+			t.Errorf("CardBrand(%q) = %q, want %q", tc.input, got, tc.brand)
+		}
+	}
+}
+
+func TestCreditCardValidator(t *testing.T) {
+	tests := []struct {
+		v     *CreditCardValidator
+		input string
+		ok    bool
+	}{
+		{&CreditCardValidator{}, "4111111111111111", true},
+		{&CreditCardValidator{}, "4111-1111-1111-1111", true},
+		{&CreditCardValidator{}, "4111111111111112", false},
+		{&CreditCardValidator{}, "41111111", false},
+		{&CreditCardValidator{}, "4111a11111111111", false},
+		{&CreditCardValidator{Brands: []string{BrandVisa, BrandMastercard}}, "5555555555554444", true},
+		{&CreditCardValidator{Brands: []string{BrandVisa}}, "378282246310005", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestIBANValidator(t *testing.T) {
+	v := &IBANValidator{}
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"GB82WEST12345698765432", true},
+		{"NL91 ABNA 0417 1643 00", true},
+		{"DE89370400440532013000", true},
+		{"DE89370400440532013001", false},
+		{"NL91ABNA04171643", false},
+		{"ZZ91ABNA0417164300", false},
+		{"gb82west12345698765432", true},
+		{"nl91abna0417164300", true},
+		{"nl91abna0417164301", false},
+		{"GB", false},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestISBNValidator(t *testing.T) {
+	tests := []struct {
+		v     *ISBNValidator
+		input string
+		ok    bool
+	}{
+		{&ISBNValidator{}, "0-306-40615-2", true},
+		{&ISBNValidator{}, "080442957X", true},
+		{&ISBNValidator{}, "978-0-306-40615-7", true},
+		{&ISBNValidator{}, "9780306406158", false},
+		{&ISBNValidator{}, "4006381333931", false}, // valid EAN, not Bookland
+		{&ISBNValidator{Version: 10}, "9780306406157", false},
+		{&ISBNValidator{Version: 13}, "9780306406157", true},
+		{&ISBNValidator{Version: 12}, "9780306406157", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestEANAndUPCValidators(t *testing.T) {
+	ean := &EANValidator{}
+	upc := &UPCValidator{}
+	tests := []struct {
+		v     interface{ Validate(string) error }
+		input string
+		ok    bool
+	}{
+		{ean, "4006381333931", true},
+		{ean, "96385074", true},
+		{ean, "4006381333932", false},
+		{ean, "036000291452", false},
+		{upc, "036000291452", true},
+		{upc, "036000291453", false},
+		{upc, "03600029145", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestChecksumTags(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &CreditCardValidator{})
+	valex.MustRegisterDirectiveTo(reg, &IBANValidator{})
+	valex.MustRegisterDirectiveTo(reg, &ISBNValidator{})
+
+	type payment struct {
+		Card string `val:"creditcard,brands=visa|mastercard"`
+		IBAN string `val:"iban"`
+		Book string `val:"isbn,version=13"`
+	}
+	if err := reg.ValidateStruct(&payment{"5555555555554444", "NL91ABNA0417164300", "9780306406157"}); err != nil {
+		t.Fatalf("expected valid payment, got %v", err)
+	}
+	if err := reg.ValidateStruct(&payment{"378282246310005", "NL91ABNA0417164300", "9780306406157"}); err == nil {
+		t.Fatal("expected amex card to be rejected by brands=visa|mastercard")
+	}
+
+	type badBrand struct {
+		Card string `val:"creditcard,brands=visa|acme"`
+	}
+	if err := reg.ValidateStruct(&badBrand{"4111111111111111"}); err == nil {
+		t.Fatal("expected unknown brand parameter to fail")
+	}
+}
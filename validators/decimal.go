@@ -0,0 +1,239 @@
+package validators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// DecimalValidator validates a decimal number written as a string: an optional
+// sign, digits, and optionally a point followed by more digits ("-12.50"), with
+// no exponent and no grouping separators. Prices and quantities kept as strings
+// never pass through float64, so the bounds are compared exactly:
+//
+//	val:"decimal,precision=10,scale=2,min=0.01,max=99999999.99"
+//	val:"decimal,scale=2,step=0.05"
+//
+// Precision and Scale follow SQL's NUMERIC(precision, scale): Scale bounds the
+// digits written after the point (so "1.500" has scale 3), and Precision the
+// significant digits in total, with at most Precision-Scale before the point.
+// Zero Precision and a nil Scale leave them unbounded, so the zero value
+// accepts any decimal; scale=0 allows integers only. Min, Max, and Step are
+// decimals too; Step requires the value to be a whole multiple of it.
+type DecimalValidator struct {
+	Precision int      `param:"precision,required=false"`
+	Scale     *int     `param:"scale,required=false"`
+	Min       *big.Rat `param:"min,required=false"`
+	Max       *big.Rat `param:"max,required=false"`
+	Step      *big.Rat `param:"step,required=false"`
+}
+
+// Validate checks whether the value is a decimal within the configured bounds.
+func (v *DecimalValidator) Validate(val string) error {
+	if v.Scale != nil && *v.Scale < 0 {
+		return fmt.Errorf("scale must not be negative, got %d", *v.Scale)
+	}
+	if v.Scale != nil && v.Precision > 0 && *v.Scale > v.Precision {
+		return fmt.Errorf("scale %d exceeds precision %d", *v.Scale, v.Precision)
+	}
+	d, err := parseDecimal(val)
+	if err != nil {
+		return err
+	}
+	if v.Scale != nil && d.scale > *v.Scale {
+		return fmt.Errorf("value %s has %d decimal places, more than scale %d", val, d.scale, *v.Scale)
+	}
+	if v.Precision > 0 {
+		if d.intDigits+d.scale > v.Precision {
+			return fmt.Errorf("value %s has more than %d significant digits", val, v.Precision)
+		}
+		if v.Scale != nil && d.intDigits > v.Precision-*v.Scale {
+			return fmt.Errorf("value %s has more than %d digits before the decimal point", val, v.Precision-*v.Scale)
+		}
+	}
+	if v.Min != nil && d.value.Cmp(v.Min) < 0 {
+		return fmt.Errorf("value %s is less than minimum %s", val, formatDecimal(v.Min))
+	}
+	if v.Max != nil && d.value.Cmp(v.Max) > 0 {
+		return fmt.Errorf("value %s is greater than maximum %s", val, formatDecimal(v.Max))
+	}
+	if v.Step != nil && !isMultipleOf(d.value, v.Step) {
+		return fmt.Errorf("value %s is not a multiple of %s", val, formatDecimal(v.Step))
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *DecimalValidator) Name() string {
+	return "decimal"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *DecimalValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the scale, min, max, and step parameters.
+func (v *DecimalValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	if fieldValue.Type() == reflect.TypeOf((*int)(nil)) {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return tagex.NewConversionError(field, raw, "int")
+		}
+		fieldValue.Set(reflect.ValueOf(&n))
+		return nil
+	}
+	if fieldValue.Type() != reflect.TypeOf((*big.Rat)(nil)) {
+		return tagex.DefaultConvert(fieldValue, raw, paramName(field))
+	}
+	r, err := ParseDecimal(raw)
+	if err != nil {
+		return tagex.NewConversionError(field, raw, "decimal")
+	}
+	if field.Name == "Step" && r.Sign() <= 0 {
+		return fmt.Errorf("step must be positive, got %s", raw)
+	}
+	fieldValue.Set(reflect.ValueOf(r))
+	return nil
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *DecimalValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// ParseDecimal parses a decimal string in the form DecimalValidator accepts
+// and returns its exact value.
+func ParseDecimal(s string) (*big.Rat, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return nil, err
+	}
+	return d.value, nil
+}
+
+type decimal struct {
+	value     *big.Rat
+	intDigits int // digits before the point, without leading zeros
+	scale     int // digits written after the point
+}
+
+func parseDecimal(s string) (decimal, error) {
+	invalid := fmt.Errorf("value %q is not a valid decimal", s)
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	if len(digits) < len(s)-1 {
+		return decimal{}, invalid
+	}
+	intPart, frac, hasPoint := strings.Cut(digits, ".")
+	if intPart == "" || !isDigits(intPart) || hasPoint && (frac == "" || !isDigits(frac)) {
+		return decimal{}, invalid
+	}
+	value, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return decimal{}, invalid
+	}
+	return decimal{
+		value:     value,
+		intDigits: len(strings.TrimLeft(intPart, "0")),
+		scale:     len(frac),
+	}, nil
+}
+
+// formatDecimal renders r, which came from a decimal string, without
+// trailing zeros.
+func formatDecimal(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	s := r.FloatString(64)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+func isMultipleOf(x, step *big.Rat) bool {
+	return new(big.Rat).Quo(x, step).IsInt()
+}
+
+// StepIntValidator validates that an int is a whole multiple of Step.
+type StepIntValidator struct {
+	Step int `param:"step"`
+}
+
+// Validate checks whether the value is a multiple of Step.
+func (v *StepIntValidator) Validate(val int) error {
+	if v.Step <= 0 {
+		return fmt.Errorf("step must be positive, got %d", v.Step)
+	}
+	if val%v.Step != 0 {
+		return fmt.Errorf("value %d is not a multiple of %d", val, v.Step)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *StepIntValidator) Name() string {
+	return "stepint"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *StepIntValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *StepIntValidator) Handle(val int) (int, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// StepFloat64Validator validates that a float64 is a whole multiple of Step.
+// Both are compared as the shortest decimals that round-trip to them, so 0.3 is
+// a multiple of 0.1 even though 0.3/0.1 is 2.9999999999999996 in float64.
+type StepFloat64Validator struct {
+	Step float64 `param:"step"`
+}
+
+// Validate checks whether the value is a multiple of Step.
+func (v *StepFloat64Validator) Validate(val float64) error {
+	if !(v.Step > 0) || math.IsInf(v.Step, 0) {
+		return fmt.Errorf("step must be positive and finite, got %g", v.Step)
+	}
+	x, ok := floatDecimal(val)
+	if !ok {
+		return fmt.Errorf("value %g is not a finite number", val)
+	}
+	step, _ := floatDecimal(v.Step)
+	if !isMultipleOf(x, step) {
+		return fmt.Errorf("value %g is not a multiple of %g", val, v.Step)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *StepFloat64Validator) Name() string {
+	return "stepfloat"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *StepFloat64Validator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *StepFloat64Validator) Handle(val float64) (float64, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// floatDecimal returns the shortest decimal that round-trips to f, exactly.
+func floatDecimal(f float64) (*big.Rat, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+}
@@ -0,0 +1,163 @@
+package validators
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func scale(n int) *int { return &n }
+
+func rat(s string) *big.Rat {
+	r, _ := new(big.Rat).SetString(s)
+	return r
+}
+
+func TestDecimalValidator(t *testing.T) {
+	tests := []struct {
+		v     *DecimalValidator
+		input string
+		ok    bool
+	}{
+		{&DecimalValidator{}, "1.5", true}, // the zero value is unbounded
+		{&DecimalValidator{}, "12.50", true},
+		{&DecimalValidator{}, "-0.001", true},
+		{&DecimalValidator{}, "+7", true},
+		{&DecimalValidator{}, "007", true},
+		{&DecimalValidator{}, "", false},
+		{&DecimalValidator{}, ".5", false},
+		{&DecimalValidator{}, "5.", false},
+		{&DecimalValidator{}, "1e3", false},
+		{&DecimalValidator{}, "1,000.00", false},
+		{&DecimalValidator{}, "--1", false},
+		{&DecimalValidator{}, "1/2", false},
+		{&DecimalValidator{}, "NaN", false},
+		{&DecimalValidator{Scale: scale(2)}, "19.99", true},
+		{&DecimalValidator{Scale: scale(2)}, "19.9", true},
+		{&DecimalValidator{Scale: scale(2)}, "19.999", false},
+		{&DecimalValidator{Scale: scale(2)}, "1.500", false},
+		{&DecimalValidator{Scale: scale(0)}, "42", true},
+		{&DecimalValidator{Scale: scale(0)}, "42.0", false},
+		{&DecimalValidator{Precision: 5, Scale: scale(2)}, "999.99", true},
+		{&DecimalValidator{Precision: 5, Scale: scale(2)}, "1000", false},
+		{&DecimalValidator{Precision: 5, Scale: scale(2)}, "1234.5", false},
+		{&DecimalValidator{Precision: 5, Scale: scale(2)}, "00012.34", true},
+		{&DecimalValidator{Precision: 3}, "0.123", true},
+		{&DecimalValidator{Precision: 3}, "1.234", false},
+		{&DecimalValidator{Precision: 2, Scale: scale(3)}, "0.1", false},
+		{&DecimalValidator{Scale: scale(-1)}, "1", false},
+		{&DecimalValidator{Min: rat("0.01")}, "0.01", true},
+		{&DecimalValidator{Min: rat("0.01")}, "0.009", false},
+		{&DecimalValidator{Max: rat("9007199254740993")}, "9007199254740993", true},
+		{&DecimalValidator{Max: rat("9007199254740993")}, "9007199254740994", false}, // equal as float64
+		{&DecimalValidator{Max: rat("0.3")}, "0.30000000000000001", false},
+		{&DecimalValidator{Step: rat("0.05")}, "19.95", true},
+		{&DecimalValidator{Step: rat("0.05")}, "-0.15", true},
+		{&DecimalValidator{Step: rat("0.05")}, "19.99", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%+v(%q): expected ok=%v, got ok=%v (err: %v)", *tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestStepValidators(t *testing.T) {
+	intTests := []struct {
+		step, input int
+		ok          bool
+	}{
+		{5, 15, true},
+		{5, -10, true},
+		{5, 0, true},
+		{5, 12, false},
+		{0, 10, false},
+		{-5, 10, false},
+	}
+	for _, tc := range intTests {
+		err := (&StepIntValidator{Step: tc.step}).Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("StepIntValidator{%d}(%d): expected ok=%v, got ok=%v (err: %v)", tc.step, tc.input, tc.ok, ok, err)
+		}
+	}
+
+	floatTests := []struct {
+		step, input float64
+		ok          bool
+	}{
+		{0.1, 0.3, true}, // 0.3/0.1 != 3 in float64
+		{0.05, 19.95, true},
+		{0.05, 0.15, true},
+		{0.01, 1.13, true},
+		{0.25, 2.75, true},
+		{0.05, 19.99, false},
+		{0.1, 0.30000000000000004, false}, // 0.1+0.2 is not 0.3
+		{0.1, math.NaN(), false},
+		{0.1, math.Inf(1), false},
+		{0, 1, false},
+		{math.Inf(1), 1, false},
+	}
+	for _, tc := range floatTests {
+		err := (&StepFloat64Validator{Step: tc.step}).Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("StepFloat64Validator{%g}(%g): expected ok=%v, got ok=%v (err: %v)", tc.step, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestDecimalTags(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &DecimalValidator{})
+	valex.MustRegisterDirectiveTo(reg, &StepIntValidator{})
+	valex.MustRegisterDirectiveTo(reg, &StepFloat64Validator{})
+
+	type lineItem struct {
+		Price    string  `val:"decimal,precision=10,scale=2,min=0.01,max=99999999.99"`
+		Discount string  `val:"decimal,scale=2,step=0.05"`
+		Qty      int     `val:"stepint,step=6"`
+		Weight   float64 `val:"stepfloat,step=0.1"`
+	}
+	good := lineItem{"19.99", "0.15", 12, 0.3}
+	if err := reg.ValidateStruct(&good); err != nil {
+		t.Fatalf("expected valid line item, got %v", err)
+	}
+	for _, bad := range []lineItem{
+		{"0.00", "0.15", 12, 0.3},
+		{"19.999", "0.15", 12, 0.3},
+		{"100000000.00", "0.15", 12, 0.3},
+		{"19.99", "0.12", 12, 0.3},
+		{"19.99", "0.15", 10, 0.3},
+		{"19.99", "0.15", 12, 0.35},
+	} {
+		if err := reg.ValidateStruct(&bad); err == nil {
+			t.Errorf("expected %+v to fail", bad)
+		}
+	}
+
+	// Defaults leave scale and precision unbounded.
+	type amount struct {
+		V string `val:"decimal"`
+	}
+	if err := reg.ValidateStruct(&amount{"123456789.123456789"}); err != nil {
+		t.Errorf("expected an unbounded decimal to pass, got %v", err)
+	}
+
+	type badParam struct {
+		V string `val:"decimal,min=1e3"`
+	}
+	if err := reg.ValidateStruct(&badParam{"1"}); err == nil {
+		t.Error("expected an exponent in min to be rejected")
+	}
+	type badStep struct {
+		V string `val:"decimal,step=-0.05"`
+	}
+	if err := reg.ValidateStruct(&badStep{"1"}); err == nil {
+		t.Error("expected a negative step to be rejected")
+	}
+}
@@ -20,6 +20,17 @@
 // (for example "rangeint,min=0,max=120"). Where a directive takes a list, the
 // values are pipe-separated (for example "oneof,values=a|b|c").
 //
+// The string length directives (min, max, len) count bytes by default. Pass
+// unit=runes to count code points, or unit=graphemes to count user-perceived
+// characters, so "Zoë" is 3 rather than 4 and CJK names are not cut short
+// ("max,size=20,unit=runes").
+//
+// The time-relative directives (future, past, within, notolderthan) read "now"
+// from the registry they are registered on, so a registry created with
+// valex.WithClock makes them deterministic; used programmatically they fall back
+// to time.Now. Likewise, WithHostResolver and WithMXResolver give a registry the
+// DNS resolver its url and email directives use, so tests can stub lookups.
+//
 // # Catalog
 //
 // The "val" tag directives, grouped by the Go type of the field they validate.
@@ -35,6 +46,7 @@
 //	negint         NonPositiveIntValidator       -            non-positive
 //	!zeroint       NonZeroIntValidator           -            not zero
 //	oneofint       OneOfIntValidator             values       one of a pipe-separated list
+//	stepint        StepIntValidator              step         a multiple of step
 //	-- float64 --
 //	rangefloat     Float64RangeValidator         min, max     inclusive range
 //	minfloat       MinFloat64Validator           min          value >= min
@@ -43,36 +55,106 @@
 //	negfloat       NonPositiveFloat64Validator   -            non-positive
 //	!zerofloat     NonZeroFloat64Validator       -            not zero
 //	oneoffloat     OneOfFloat64Validator         values       one of a pipe-separated list
+//	stepfloat      StepFloat64Validator          step         a multiple of step, compared in decimal
+//	latitude       LatitudeValidator             -            latitude in [-90, 90]
+//	longitude      LongitudeValidator            -            longitude in [-180, 180]
 //	-- string --
-//	url            UrlValidator                  -            valid absolute URL
-//	email          EmailValidator                -            valid email address
+//	url            UrlValidator                  schemes,     valid URL; optional scheme allowlist,
+//	                                             requirehost, host requirement, userinfo and port
+//	                                             nouserinfo,  rules, SSRF-safe host checks, and
+//	                                             ports,       DNS resolution via Resolver
+//	                                             denyports,
+//	                                             ssrfsafe,
+//	                                             resolve
+//	email          EmailValidator                strict,      valid email address; optional bare-
+//	                                             maxlocal,    address, length, IDN, domain allow/
+//	                                             maxdomain,   deny, and MX rules, and a Disposable
+//	                                             idn,         domain list
+//	                                             domains,
+//	                                             denydomains,
+//	                                             mx
 //	!empty         NonEmptyStringValidator       -            non-empty
-//	min            MinLengthValidator            size         length >= size
-//	max            MaxLengthValidator            size         length <= size
-//	len            LengthRangeValidator          min, max     length within [min, max]
+//	min            MinLengthValidator            size, unit   length >= size
+//	max            MaxLengthValidator            size, unit   length <= size
+//	len            LengthRangeValidator          min, max,    length within [min, max]
+//	                                             unit
 //	regex          RegexValidator                pattern      matches regular expression
 //	prefix         PrefixValidator               value        has prefix
 //	suffix         SuffixValidator               value        has suffix
 //	contains       ContainsValidator             value        contains substring
 //	oneof          OneOfStringValidator          values       one of a pipe-separated list
 //	alphanum       AlphaNumericValidator         -            alphanumeric
+//	alpha          AlphaValidator                -            ASCII letters only
+//	numeric        NumericValidator              -            ASCII digits only
+//	alphaunicode   UnicodeLetterValidator        -            Unicode letters (and marks) only
+//	printable      PrintableValidator            -            printable characters only
+//	ascii          ASCIIValidator                -            ASCII characters only
+//	!control       NoControlCharValidator        -            no control characters
 //	mac            MACAddressValidator           -            valid MAC address
 //	ip             IpValidator                   -            valid IP address
 //	ipv4           IPv4Validator                 -            valid IPv4 address
 //	ipv6           IPv6Validator                 -            valid IPv6 address
 //	hostname       HostnameValidator             -            valid hostname
-//	cidr           IPCIDRValidator               -            valid CIDR notation
+//	cidr           IPCIDRValidator               minbits,     valid CIDR notation, optional prefix
+//	                                             maxbits      length bounds
 //	uuid           UUIDValidator                 version (4)  RFC 4122 UUID, optional version
 //	base64         Base64Validator               -            valid base64 (standard or raw)
 //	hex            HexValidator                  -            valid hex (optional 0x prefix)
-//	xml            XMLValidator                  -            well-formed XML
-//	json           JSONValidator                 -            valid JSON
+//	xml            XMLValidator                  maxbytes,    well-formed XML, optional size and
+//	                                             maxdepth     nesting limits
+//	json           JSONValidator                 maxbytes,    valid JSON, optional size and
+//	                                             maxdepth     nesting limits
+//	jsonschema     JSONSchemaValidator           schema,      JSON conforming to a named schema
+//	                                             maxbytes,    (see LoadJSONSchemas,
+//	                                             maxdepth     WithJSONSchemas)
 //	time           TimeValidator                 format       valid time for the layout (default RFC3339)
+//	password       PasswordValidator             min, max,    password policy; reports every unmet
+//	                                             upper,       rule (*PasswordError), optional
+//	                                             lower,       Denylist
+//	                                             digit,
+//	                                             symbol,
+//	                                             entropy
+//	creditcard     CreditCardValidator           brands       card number: Luhn, optional brand list
+//	iban           IBANValidator                 -            IBAN: country length and mod-97
+//	isbn           ISBNValidator                 version      ISBN-10 or ISBN-13
+//	ean            EANValidator                  -            EAN-8 or EAN-13 check digit
+//	upc            UPCValidator                  -            UPC-A check digit
+//	luhn           LuhnValidator                 -            Luhn (mod 10) checksum
+//	mod11          Mod11Validator                -            weighted mod-11 checksum
+//	e164           E164Validator                 -            strict E.164 phone number
+//	phone          PhoneValidator                region,      phone number, national (with region)
+//	                                             normalize    or international; normalize rewrites
+//	                                                          the field in E.164
+//	semver         SemverValidator               constraint   SemVer 2.0.0 version, optionally
+//	                                                          satisfying a constraint (">=1.2 <2")
+//	slug           SlugValidator                 -            lowercase words joined by hyphens
+//	identifier     IdentifierValidator           -            Go identifier, not a keyword
+//	dnslabel       DNSLabelValidator             -            RFC 1123 DNS label
+//	k8sname        K8sNameValidator              -            Kubernetes resource name (DNS subdomain)
+//	k8slabel       K8sLabelValueValidator        -            Kubernetes label value
+//	envvar         EnvVarNameValidator           upper        environment variable name
+//	latlong        LatLongValidator              -            "lat,long" coordinate pair
+//	country        CountryValidator              alpha        ISO 3166-1 alpha-2 or alpha-3 code
+//	currency       CurrencyValidator             -            ISO 4217 currency code
+//	language       LanguageValidator             -            BCP 47 language tag
+//	timezone       TimezoneValidator             -            IANA time zone name (embedded tzdata)
+//	decimal        DecimalValidator              precision,   decimal string; SQL-style precision
+//	                                             scale, min,  and scale, exact bounds and step
+//	                                             max, step
 //	-- time.Time --
 //	!zerotime      NonZeroTimeValidator          -            not zero
 //	beforetime     TimeBeforeValidator           before       before the given RFC3339 time
 //	aftertime      TimeAfterValidator            after        after the given RFC3339 time
 //	betweentime    TimeBetweenValidator          start, end   within [start, end] (RFC3339)
+//	future         FutureValidator               -            after now
+//	past           PastValidator                 -            before now
+//	within         WithinValidator               d            within d of now, either direction
+//	notolderthan   NotOlderThanValidator         d            no more than d before now
+//	weekday        WeekdayValidator              days, loc    falls on one of the days
+//	timeofday      TimeOfDayValidator            start, end,  wall-clock time within [start, end)
+//	                                             loc
+//	businesshours  BusinessHoursValidator        days, start, within business hours (default
+//	                                             end, loc     Mon-Fri 09:00-17:00)
 //	-- time.Duration --
 //	posduration    PositiveDurationValidator     -            positive
 //	!zeroduration  NonZeroDurationValidator      -            not zero
@@ -81,9 +163,37 @@
 //	iprange        IPRangeValidator              start, end   within [start, end]
 //	-- url.URL --
 //	!zerourl       NonZeroURLValidator           -            not the zero value
+//	-- netip.Addr --
+//	addr           AddrValidator                 family       set, optionally IPv4 (4) or IPv6 (6)
+//	!zeroaddr      NonZeroAddrValidator          -            set and not unspecified
+//	rangeaddr      AddrRangeValidator            start, end   within [start, end]
+//	private        PrivateAddrValidator          -            RFC 1918 / unique local address
+//	public         PublicAddrValidator           -            globally routable unicast address
+//	loopback       LoopbackAddrValidator         -            loopback address
+//	multicast      MulticastAddrValidator        -            multicast address
+//	inprefix       InPrefixValidator             prefix       within a pipe-separated prefix list
+//	-- netip.Prefix --
+//	netprefix      NetPrefixValidator            family,      valid prefix; optional family,
+//	                                             minbits,     length bounds, and no host bits
+//	                                             maxbits,
+//	                                             masked
+//	-- netip.AddrPort --
+//	addrport       AddrPortValidator             family       set address, non-zero port
+//	-- json.RawMessage --
+//	rawjsonschema  RawJSONSchemaValidator        schema,      as jsonschema
+//	                                             maxbytes,
+//	                                             maxdepth
 //
 // Alongside the tag directives, the package also offers generic programmatic
 // validators that are not registered with the "val" tag: CmpRangeValidator and
 // NonZeroValidator implement valex.Validator directly, and CompositeValidator
-// chains several valex.Validator values into one.
+// chains several valex.Validator values into one. The zero-size rule types —
+// EmailRule, URLRule, UUIDRule, HostnameRule, SlugRule, SemverRule, E164Rule,
+// CountryRule, CurrencyRule, LanguageRule, TimezoneRule, and NonEmptyRule —
+// apply a directive's default check through a value receiver, so they can
+// serve as the validator parameter of valex.Refined:
+//
+//	type Contact struct {
+//		Email valex.Refined[string, validators.EmailRule] `json:"email"`
+//	}
 package validators
@@ -0,0 +1,104 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+// MXResolver looks up the mail exchangers of a domain. *net.Resolver
+// implements it; tests can stub it to make EmailValidator's MX check
+// deterministic.
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+type mxResolverKey struct{}
+
+// WithMXResolver returns a registry option that supplies r to the email
+// directives registered on that registry which have no Resolver of their own.
+func WithMXResolver(r MXResolver) valex.Option {
+	return valex.WithValue(mxResolverKey{}, r)
+}
+
+// checkEmailIDN applies the idn parameter to an email domain.
+func checkEmailIDN(domain, mode string) error {
+	switch mode {
+	case "allow":
+		for _, label := range strings.Split(domain, ".") {
+			if !isDomainLabel(label) {
+				return fmt.Errorf("email domain %q is not a valid domain name", domain)
+			}
+		}
+		return nil
+	case "reject":
+		if !allRunes(domain, func(r rune) bool { return r < utf8.RuneSelf }) {
+			return fmt.Errorf("email domain %q must be ASCII", domain)
+		}
+		return checkEmailIDN(domain, "allow")
+	}
+	return fmt.Errorf(`invalid idn mode %q, expected "allow" or "reject"`, mode)
+}
+
+// isDomainLabel reports whether label is a non-empty run of letters, digits,
+// and combining marks (any script) with inner hyphens, at most 63 bytes.
+func isDomainLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	return allRunes(label, func(r rune) bool {
+		return r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r)
+	})
+}
+
+// domainMatchesAny reports whether domain equals one of domains or is a
+// subdomain of one.
+func domainMatchesAny(domain string, domains []string) bool {
+	for _, d := range domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDisposableDomain reports whether domain or any of its parent domains is on
+// the list, so "mx.mailinator.com" matches an entry for "mailinator.com".
+func isDisposableDomain(list *Denylist, domain string) bool {
+	for d := domain; d != ""; {
+		if list.Contains(d) {
+			return true
+		}
+		_, parent, ok := strings.Cut(d, ".")
+		if !ok {
+			break
+		}
+		d = parent
+	}
+	return false
+}
+
+// checkMX rejects a domain that publishes no mail exchangers, or only a null
+// MX (RFC 7505) declaring it accepts no mail.
+func checkMX(r MXResolver, domain string) error {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	mxs, err := r.LookupMX(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("email domain %q has no mail exchanger: %w", domain, err)
+	}
+	for _, mx := range mxs {
+		if mx.Host != "." && mx.Host != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("email domain %q does not accept mail", domain)
+}
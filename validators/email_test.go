@@ -0,0 +1,122 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+// stubMX maps domains to MX hosts; unknown domains fail to resolve.
+type stubMX map[string][]string
+
+func (r stubMX) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	hosts, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	mxs := make([]*net.MX, len(hosts))
+	for i, h := range hosts {
+		mxs[i] = &net.MX{Host: h, Pref: uint16(10 * (i + 1))}
+	}
+	return mxs, nil
+}
+
+func TestEmailValidatorOptions(t *testing.T) {
+	disposable := NewDenylist("mailinator.com", "10minutemail.com")
+	tests := []struct {
+		v     *EmailValidator
+		input string
+		ok    bool
+	}{
+		{&EmailValidator{}, "Bob <bob@example.com>", true},
+		{&EmailValidator{Strict: true}, "Bob <bob@example.com>", false},
+		{&EmailValidator{Strict: true}, "<bob@example.com>", false},
+		{&EmailValidator{Strict: true}, "bob@example.com", true},
+		{&EmailValidator{MaxLocal: 64}, strings.Repeat("a", 64) + "@example.com", true},
+		{&EmailValidator{MaxLocal: 64}, strings.Repeat("a", 65) + "@example.com", false},
+		{&EmailValidator{MaxDomain: 12}, "bob@example.com", true},
+		{&EmailValidator{MaxDomain: 10}, "bob@example.com", false},
+		{&EmailValidator{IDN: "allow"}, "bob@bücher.example", true},
+		{&EmailValidator{IDN: "reject"}, "bob@bücher.example", false},
+		{&EmailValidator{IDN: "reject"}, "bob@xn--bcher-kva.example", true},
+		{&EmailValidator{IDN: "allow"}, "bob@-bad-.example", false},
+		{&EmailValidator{IDN: "sometimes"}, "bob@example.com", false},
+		{&EmailValidator{Domains: []string{"example.com"}}, "bob@Example.COM", true},
+		{&EmailValidator{Domains: []string{"example.com"}}, "bob@mail.example.com", true},
+		{&EmailValidator{Domains: []string{"example.com"}}, "bob@badexample.com", false},
+		{&EmailValidator{DenyDomains: []string{"example.org"}}, "bob@example.org", false},
+		{&EmailValidator{DenyDomains: []string{"example.org"}}, "bob@example.com", true},
+		{&EmailValidator{Disposable: disposable}, "bob@mailinator.com", false},
+		{&EmailValidator{Disposable: disposable}, "bob@eu.mailinator.com", false},
+		{&EmailValidator{Disposable: disposable}, "bob@example.com", true},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%+v(%q): expected ok=%v, got ok=%v (err: %v)", *tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestEmailValidatorMX(t *testing.T) {
+	v := &EmailValidator{MX: true, Resolver: stubMX{
+		"example.com": {"mx1.example.com.", "mx2.example.com."},
+		"nomail.test": {"."},
+	}}
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"bob@example.com", true},
+		{"bob@nomail.test", false},
+		{"bob@missing.test", false},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestEmailTag(t *testing.T) {
+	disposable, err := LoadDenylist(strings.NewReader("# providers\nmailinator.com\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &EmailValidator{Disposable: disposable})
+
+	type signup struct {
+		Email string `val:"email,strict=true,maxlocal=64,maxdomain=255,denydomains=example.org|example.net"`
+	}
+	if err := reg.ValidateStruct(&signup{"bob@example.com"}); err != nil {
+		t.Fatalf("expected valid email, got %v", err)
+	}
+	for _, email := range []string{"Bob <bob@example.com>", "bob@example.net", "bob@mailinator.com"} {
+		if err := reg.ValidateStruct(&signup{email}); err == nil {
+			t.Errorf("expected %q to be rejected", email)
+		}
+	}
+}
+
+func TestWithMXResolver(t *testing.T) {
+	reg := valex.NewRegistry(WithMXResolver(stubMX{"example.com": {"mx.example.com."}}))
+	valex.MustRegisterDirectiveTo(reg, &EmailValidator{})
+
+	type signup struct {
+		Email string `val:"email,mx=true"`
+	}
+	if err := reg.ValidateStruct(&signup{"bob@example.com"}); err != nil {
+		t.Fatalf("expected example.com to have MX via the registry resolver, got %v", err)
+	}
+	if err := reg.ValidateStruct(&signup{"bob@nomx.example"}); err == nil {
+		t.Fatal("expected a domain unknown to the registry resolver to fail")
+	}
+}
@@ -0,0 +1,116 @@
+package validators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// LatitudeValidator validates that a float64 is a latitude in decimal degrees,
+// within [-90, 90].
+type LatitudeValidator struct{}
+
+// Validate checks whether the value is a latitude.
+func (v *LatitudeValidator) Validate(val float64) error {
+	return validateRange(val, -90, 90, "latitude %g is out of range [%g, %g]")
+}
+
+// Name returns the directive identifier.
+func (v *LatitudeValidator) Name() string {
+	return "latitude"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *LatitudeValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *LatitudeValidator) Handle(val float64) (float64, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// LongitudeValidator validates that a float64 is a longitude in decimal
+// degrees, within [-180, 180].
+type LongitudeValidator struct{}
+
+// Validate checks whether the value is a longitude.
+func (v *LongitudeValidator) Validate(val float64) error {
+	return validateRange(val, -180, 180, "longitude %g is out of range [%g, %g]")
+}
+
+// Name returns the directive identifier.
+func (v *LongitudeValidator) Name() string {
+	return "longitude"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *LongitudeValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *LongitudeValidator) Handle(val float64) (float64, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// LatLongValidator validates a coordinate pair written as "lat,long" in
+// decimal degrees ("52.3676,4.9041"), optionally with spaces around the comma.
+type LatLongValidator struct{}
+
+// Validate checks whether the value is a latitude,longitude pair.
+func (v *LatLongValidator) Validate(val string) error {
+	_, _, err := ParseLatLong(val)
+	return err
+}
+
+// Name returns the directive identifier.
+func (v *LatLongValidator) Name() string {
+	return "latlong"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *LatLongValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *LatLongValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// ParseLatLong parses a "lat,long" coordinate pair in decimal degrees and
+// checks both ranges.
+func ParseLatLong(s string) (lat, long float64, err error) {
+	latStr, longStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("value %q is not a lat,long pair", s)
+	}
+	lat, err1 := parseDegrees(latStr)
+	long, err2 := parseDegrees(longStr)
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("value %q is not a lat,long pair", s)
+	}
+	if err := (&LatitudeValidator{}).Validate(lat); err != nil {
+		return 0, 0, err
+	}
+	if err := (&LongitudeValidator{}).Validate(long); err != nil {
+		return 0, 0, err
+	}
+	return lat, long, nil
+}
+
+// parseDegrees parses a plain decimal number, rejecting the forms ParseFloat
+// also accepts but no coordinate is written in: hex, exponents, Inf, and NaN.
+func parseDegrees(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || !allRunes(strings.TrimLeft(s, "+-"), func(r rune) bool { return r == '.' || r >= '0' && r <= '9' }) {
+		return 0, fmt.Errorf("invalid degrees %q", s)
+	}
+	return strconv.ParseFloat(s, 64)
+}
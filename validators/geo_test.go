@@ -0,0 +1,52 @@
+package validators
+
+import "testing"
+
+func TestGeoValidators(t *testing.T) {
+	tests := []struct {
+		v     interface{ Validate(float64) error }
+		input float64
+		ok    bool
+	}{
+		{&LatitudeValidator{}, 52.3676, true},
+		{&LatitudeValidator{}, -90, true},
+		{&LatitudeValidator{}, 90.0001, false},
+		{&LongitudeValidator{}, 180, true},
+		{&LongitudeValidator{}, -180.5, false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%g): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestLatLongValidator(t *testing.T) {
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"52.3676,4.9041", true},
+		{"-33.8688, 151.2093", true},
+		{"+90,-180", true},
+		{"91,0", false},
+		{"0,181", false},
+		{"52.3676", false},
+		{"NaN,0", false},
+		{"1e1,0", false},
+		{",", false},
+	}
+	for _, tc := range tests {
+		err := (&LatLongValidator{}).Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("LatLongValidator(%q): expected ok=%v, got ok=%v (err: %v)", tc.input, tc.ok, ok, err)
+		}
+	}
+	lat, long, err := ParseLatLong("52.3676, 4.9041")
+	if err != nil || lat != 52.3676 || long != 4.9041 {
+		t.Errorf("ParseLatLong = %g, %g, %v", lat, long, err)
+	}
+}
@@ -0,0 +1,194 @@
+package validators
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+var (
+	slugPattern     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	dnsLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	k8sNamePattern  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+	k8sLabelPattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9_.-]{0,61}[a-zA-Z0-9])?)?$`)
+	envVarPattern   = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// SlugValidator validates that a string is a URL slug: lowercase ASCII letters
+// and digits in words joined by single hyphens ("my-first-post").
+type SlugValidator struct{}
+
+// Validate checks whether the value is a slug.
+func (v *SlugValidator) Validate(val string) error {
+	if !slugPattern.MatchString(val) {
+		return fmt.Errorf("value %q is not a valid slug", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *SlugValidator) Name() string {
+	return "slug"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *SlugValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *SlugValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// IdentifierValidator validates that a string is a Go identifier: a letter or
+// underscore followed by letters, digits, and underscores, and not a keyword.
+type IdentifierValidator struct{}
+
+// Validate checks whether the value is a Go identifier.
+func (v *IdentifierValidator) Validate(val string) error {
+	if !token.IsIdentifier(val) {
+		return fmt.Errorf("value %q is not a valid identifier", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *IdentifierValidator) Name() string {
+	return "identifier"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *IdentifierValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *IdentifierValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// DNSLabelValidator validates that a string is an RFC 1123 DNS label: 1 to 63
+// ASCII letters, digits, and hyphens, not starting or ending with a hyphen.
+type DNSLabelValidator struct{}
+
+// Validate checks whether the value is a DNS label.
+func (v *DNSLabelValidator) Validate(val string) error {
+	if !dnsLabelPattern.MatchString(val) {
+		return fmt.Errorf("value %q is not a valid DNS label", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *DNSLabelValidator) Name() string {
+	return "dnslabel"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *DNSLabelValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *DNSLabelValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// K8sNameValidator validates that a string is a Kubernetes resource name in
+// the common DNS-subdomain form: at most 253 characters of lowercase RFC 1123
+// labels separated by dots ("web-0", "api.v1").
+type K8sNameValidator struct{}
+
+// Validate checks whether the value is a Kubernetes resource name.
+func (v *K8sNameValidator) Validate(val string) error {
+	if len(val) > 253 || !k8sNamePattern.MatchString(val) {
+		return fmt.Errorf("value %q is not a valid Kubernetes resource name", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *K8sNameValidator) Name() string {
+	return "k8sname"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *K8sNameValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *K8sNameValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// K8sLabelValueValidator validates that a string is a Kubernetes label value:
+// empty, or at most 63 ASCII letters, digits, '-', '_', and '.', beginning and
+// ending with a letter or digit.
+type K8sLabelValueValidator struct{}
+
+// Validate checks whether the value is a Kubernetes label value.
+func (v *K8sLabelValueValidator) Validate(val string) error {
+	if !k8sLabelPattern.MatchString(val) {
+		return fmt.Errorf("value %q is not a valid Kubernetes label value", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *K8sLabelValueValidator) Name() string {
+	return "k8slabel"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *K8sLabelValueValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *K8sLabelValueValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// EnvVarNameValidator validates that a string is a portable environment
+// variable name: an ASCII letter or underscore followed by ASCII letters,
+// digits, and underscores. Set Upper to also require uppercase letters only,
+// the usual convention ("envvar,upper=true").
+type EnvVarNameValidator struct {
+	Upper bool `param:"upper,required=false"`
+}
+
+// Validate checks whether the value is an environment variable name.
+func (v *EnvVarNameValidator) Validate(val string) error {
+	if !envVarPattern.MatchString(val) {
+		return fmt.Errorf("value %q is not a valid environment variable name", val)
+	}
+	if v.Upper && !allRunes(val, func(r rune) bool { return r < 'a' || r > 'z' }) {
+		return fmt.Errorf("environment variable name %q must be uppercase", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *EnvVarNameValidator) Name() string {
+	return "envvar"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *EnvVarNameValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *EnvVarNameValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
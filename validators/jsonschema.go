@@ -0,0 +1,924 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net/netip"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+)
+
+// JSONSchemaValidator validates that a string is a JSON document conforming to
+// a named schema. Schemas are compiled up front, typically from files embedded
+// with go:embed, and handed to the registry:
+//
+//	//go:embed schemas/*.json
+//	var schemaFS embed.FS
+//
+//	schemas, err := validators.LoadJSONSchemas(schemaFS, "schemas/*.json")
+//	reg := valex.NewRegistry(validators.WithJSONSchemas(schemas))
+//	valex.MustRegisterDirectiveTo(reg, &validators.JSONSchemaValidator{})
+//
+//	type Event struct {
+//		Payload string `val:"jsonschema,schema=event"` // schemas/event.json
+//	}
+//
+// Set Schemas on the directive to use a set of its own instead of the
+// registry's. MaxBytes and MaxDepth bound the document as for JSONValidator,
+// and are checked before it is decoded. A document that does not conform is
+// reported as a *JSONSchemaError listing every violation.
+type JSONSchemaValidator struct {
+	Schema   string `param:"schema"`
+	MaxBytes int    `param:"maxbytes,required=false"`
+	MaxDepth int    `param:"maxdepth,required=false"`
+	Schemas  JSONSchemas
+	env      *valex.Env
+}
+
+// SetEnv receives the registry's environment.
+func (v *JSONSchemaValidator) SetEnv(env *valex.Env) {
+	v.env = env
+}
+
+// Validate checks whether the value conforms to the named schema.
+func (v *JSONSchemaValidator) Validate(val string) error {
+	return validateJSONSchemaDoc([]byte(val), v.Schema, v.MaxBytes, v.MaxDepth, v.Schemas, v.env)
+}
+
+// Name returns the directive identifier.
+func (v *JSONSchemaValidator) Name() string {
+	return "jsonschema"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *JSONSchemaValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *JSONSchemaValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// RawJSONSchemaValidator is JSONSchemaValidator for json.RawMessage fields.
+type RawJSONSchemaValidator struct {
+	Schema   string `param:"schema"`
+	MaxBytes int    `param:"maxbytes,required=false"`
+	MaxDepth int    `param:"maxdepth,required=false"`
+	Schemas  JSONSchemas
+	env      *valex.Env
+}
+
+// SetEnv receives the registry's environment.
+func (v *RawJSONSchemaValidator) SetEnv(env *valex.Env) {
+	v.env = env
+}
+
+// Validate checks whether the value conforms to the named schema.
+func (v *RawJSONSchemaValidator) Validate(val json.RawMessage) error {
+	return validateJSONSchemaDoc(val, v.Schema, v.MaxBytes, v.MaxDepth, v.Schemas, v.env)
+}
+
+// Name returns the directive identifier.
+func (v *RawJSONSchemaValidator) Name() string {
+	return "rawjsonschema"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *RawJSONSchemaValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *RawJSONSchemaValidator) Handle(val json.RawMessage) (json.RawMessage, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+type jsonSchemasKey struct{}
+
+// WithJSONSchemas returns a registry option that supplies s to the jsonschema
+// and rawjsonschema directives registered on that registry which have no
+// Schemas of their own.
+func WithJSONSchemas(s JSONSchemas) valex.Option {
+	return valex.WithValue(jsonSchemasKey{}, s)
+}
+
+func validateJSONSchemaDoc(doc []byte, name string, maxBytes, maxDepth int, schemas JSONSchemas, env *valex.Env) error {
+	if schemas == nil {
+		schemas, _ = env.Value(jsonSchemasKey{}).(JSONSchemas)
+	}
+	schema, ok := schemas[name]
+	if !ok {
+		return fmt.Errorf("unknown JSON schema %q", name)
+	}
+	if err := checkJSONLimits(doc, maxBytes, maxDepth); err != nil {
+		return err
+	}
+	return schema.Validate(doc)
+}
+
+// JSONSchemas is a set of compiled schemas by name. Schemas in a set may refer
+// to each other by name in $ref.
+type JSONSchemas map[string]*JSONSchema
+
+// LoadJSONSchemas compiles the files in fsys matching pattern (see fs.Glob)
+// into a set, each named after its file without the ".json" extension. A
+// schema may $ref another in the set by that name, with or without the
+// extension and optionally followed by a fragment ("address.json#/$defs/zip").
+// Every schema and every reference is checked here, so a bad schema fails at
+// startup rather than on the first request.
+func LoadJSONSchemas(fsys fs.FS, pattern string) (JSONSchemas, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	set := make(JSONSchemas, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(path.Base(file), ".json")
+		if _, dup := set[name]; dup {
+			return nil, fmt.Errorf("duplicate JSON schema name %q (%s)", name, file)
+		}
+		s, err := parseJSONSchema(data)
+		if err != nil {
+			return nil, fmt.Errorf("JSON schema %s: %w", file, err)
+		}
+		s.name = name
+		s.set = set
+		set[name] = s
+	}
+	for _, s := range set {
+		if err := s.checkRefs(s.root); err != nil {
+			return nil, fmt.Errorf("JSON schema %s: %w", s.name, err)
+		}
+	}
+	return set, nil
+}
+
+// JSONSchema is a compiled JSON Schema. It implements the assertion keywords of
+// draft 2020-12 that apply to a single document, with the draft-07 spellings of
+// the array and dependency keywords also accepted:
+//
+//	type, enum, const, allOf, anyOf, oneOf, not, if/then/else, $ref, $defs
+//	minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//	minLength, maxLength, pattern, format
+//	prefixItems, items, additionalItems, contains, minContains, maxContains,
+//	minItems, maxItems, uniqueItems
+//	properties, patternProperties, additionalProperties, propertyNames,
+//	required, minProperties, maxProperties, dependentRequired,
+//	dependentSchemas, dependencies
+//
+// $ref resolves JSON pointers within the schema ("#/$defs/item") and, for
+// schemas loaded with LoadJSONSchemas, other schemas in the set by name; remote
+// references, $id, $anchor, $dynamicRef, and the unevaluated* keywords are not
+// supported and fail compilation. pattern uses Go's RE2 syntax. format asserts
+// date-time, date, time, duration, email, hostname, ipv4, ipv6, uri, uuid, and
+// regex, and ignores other formats. Unknown keywords are ignored, as the
+// specification requires.
+type JSONSchema struct {
+	name    string
+	root    any
+	set     JSONSchemas
+	regexps map[string]*regexp.Regexp
+}
+
+// CompileJSONSchema compiles a standalone schema document. Its $ref values may
+// only point within the document.
+func CompileJSONSchema(data []byte) (*JSONSchema, error) {
+	s, err := parseJSONSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRefs(s.root); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MustCompileJSONSchema is like CompileJSONSchema but panics on error.
+func MustCompileJSONSchema(data []byte) *JSONSchema {
+	s, err := CompileJSONSchema(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate checks that doc is JSON conforming to the schema. Violations are
+// reported together as a *JSONSchemaError.
+func (s *JSONSchema) Validate(doc []byte) error {
+	inst, err := decodeJSONNumbers(doc)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if errs := s.check(s.root, inst, "", 0); len(errs) > 0 {
+		return &JSONSchemaError{Violations: errs}
+	}
+	return nil
+}
+
+// JSONSchemaViolation is one way a document failed its schema. Path is the JSON
+// Pointer to the offending value ("" for the document itself, "/items/2/sku"),
+// and Keyword the schema keyword that failed.
+type JSONSchemaViolation struct {
+	Path    string
+	Keyword string
+	Msg     string
+}
+
+func (e *JSONSchemaViolation) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return e.Path + ": " + e.Msg
+}
+
+// JSONSchemaError reports every violation found in a document. Use errors.As
+// to reach it; Unwrap exposes the individual violations as well.
+type JSONSchemaError struct {
+	Violations []*JSONSchemaViolation
+}
+
+func (e *JSONSchemaError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return "JSON does not match schema: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual violations.
+func (e *JSONSchemaError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, v := range e.Violations {
+		errs[i] = v
+	}
+	return errs
+}
+
+// maxJSONSchemaRefChain bounds how many $ref hops may be followed without
+// descending into the instance, which is how a reference cycle shows up.
+const maxJSONSchemaRefChain = 64
+
+func parseJSONSchema(data []byte) (*JSONSchema, error) {
+	root, err := decodeJSONNumbers(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	s := &JSONSchema{root: root, regexps: map[string]*regexp.Regexp{}}
+	if err := s.compile(root); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func decodeJSONNumbers(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+// compile walks a schema node, checking that it is a schema and precompiling
+// its regular expressions.
+func (s *JSONSchema) compile(node any) error {
+	switch n := node.(type) {
+	case bool:
+		return nil
+	case map[string]any:
+		for _, kw := range []string{"$id", "$anchor", "$dynamicRef", "$dynamicAnchor", "$recursiveRef", "unevaluatedItems", "unevaluatedProperties"} {
+			if _, ok := n[kw]; ok {
+				return fmt.Errorf("unsupported keyword %q", kw)
+			}
+		}
+		var patterns []string
+		if p, ok := n["pattern"].(string); ok {
+			patterns = append(patterns, p)
+		}
+		if pp, ok := n["patternProperties"].(map[string]any); ok {
+			for p := range pp {
+				patterns = append(patterns, p)
+			}
+		}
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+			s.regexps[p] = re
+		}
+		for kw, sub := range n {
+			if err := s.compileSubschemas(kw, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("schema must be an object or boolean, got %s", jsonTypeName(node))
+}
+
+func (s *JSONSchema) compileSubschemas(kw string, sub any) error {
+	switch kw {
+	case "not", "if", "then", "else", "contains", "additionalItems", "additionalProperties", "propertyNames":
+		return s.compile(sub)
+	case "items":
+		if list, ok := sub.([]any); ok { // draft-07 tuple form
+			return s.compileEach(list)
+		}
+		return s.compile(sub)
+	case "allOf", "anyOf", "oneOf", "prefixItems":
+		list, ok := sub.([]any)
+		if !ok || len(list) == 0 {
+			return fmt.Errorf("%s must be a non-empty array", kw)
+		}
+		return s.compileEach(list)
+	case "$defs", "definitions", "properties", "patternProperties", "dependentSchemas":
+		m, ok := sub.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object", kw)
+		}
+		for _, v := range m {
+			if err := s.compile(v); err != nil {
+				return err
+			}
+		}
+	case "dependencies":
+		m, ok := sub.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object", kw)
+		}
+		for _, v := range m {
+			if _, isList := v.([]any); !isList {
+				if err := s.compile(v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *JSONSchema) compileEach(list []any) error {
+	for _, v := range list {
+		if err := s.compile(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRefs resolves every $ref reachable in node, so dangling references
+// fail at compile time.
+func (s *JSONSchema) checkRefs(node any) error {
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n["$ref"].(string); ok {
+			if _, _, err := s.resolve(ref); err != nil {
+				return err
+			}
+		}
+		for kw, v := range n {
+			if kw == "enum" || kw == "const" {
+				continue
+			}
+			if err := s.checkRefs(v); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, v := range n {
+			if err := s.checkRefs(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the schema document and node a $ref points to.
+func (s *JSONSchema) resolve(ref string) (*JSONSchema, any, error) {
+	doc, frag, _ := strings.Cut(ref, "#")
+	target := s
+	if doc != "" {
+		other, ok := s.set[strings.TrimSuffix(doc, ".json")]
+		if !ok {
+			return nil, nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		target = other
+	}
+	node, err := jsonPointer(target.root, frag)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unresolvable $ref %q: %w", ref, err)
+	}
+	return target, node, nil
+}
+
+// jsonPointer evaluates an RFC 6901 pointer (as a URI fragment) against doc.
+func jsonPointer(doc any, ptr string) (any, error) {
+	ptr, err := url.PathUnescape(ptr)
+	if err != nil {
+		return nil, err
+	}
+	if ptr == "" {
+		return doc, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("fragment %q is not a JSON pointer", ptr)
+	}
+	cur := doc
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+		switch c := cur.(type) {
+		case map[string]any:
+			next, ok := c[tok]
+			if !ok {
+				return nil, fmt.Errorf("no member %q", tok)
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("no element %q", tok)
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("cannot descend into %s", jsonTypeName(cur))
+		}
+	}
+	return cur, nil
+}
+
+func escapeJSONPointer(tok string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(tok, "~", "~0"), "/", "~1")
+}
+
+// check evaluates node against inst at path and returns the violations.
+// refChain counts $ref hops since the last descent into inst.
+func (s *JSONSchema) check(node, inst any, path string, refChain int) []*JSONSchemaViolation {
+	var errs []*JSONSchemaViolation
+	fail := func(kw, format string, args ...any) {
+		errs = append(errs, &JSONSchemaViolation{Path: path, Keyword: kw, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	n, ok := node.(map[string]any)
+	if !ok {
+		if node == false {
+			fail("false", "no value is allowed here")
+		}
+		return errs
+	}
+
+	if ref, ok := n["$ref"].(string); ok {
+		if refChain >= maxJSONSchemaRefChain {
+			fail("$ref", "$ref chain too long (reference cycle?)")
+			return errs
+		}
+		doc, target, err := s.resolve(ref)
+		if err != nil {
+			fail("$ref", "%v", err)
+			return errs
+		}
+		errs = append(errs, doc.check(target, inst, path, refChain+1)...)
+	}
+
+	if t, ok := n["type"]; ok {
+		var types []string
+		switch t := t.(type) {
+		case string:
+			types = []string{t}
+		case []any:
+			for _, x := range t {
+				if str, ok := x.(string); ok {
+					types = append(types, str)
+				}
+			}
+		}
+		if !slices.ContainsFunc(types, func(t string) bool { return jsonHasType(inst, t) }) {
+			fail("type", "expected %s, got %s", strings.Join(types, " or "), jsonTypeName(inst))
+		}
+	}
+	if enum, ok := n["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, inst) }) {
+			fail("enum", "value is not one of the allowed values")
+		}
+	}
+	if c, ok := n["const"]; ok && !jsonEqual(c, inst) {
+		fail("const", "value does not equal the required constant")
+	}
+
+	switch v := inst.(type) {
+	case json.Number:
+		s.checkNumber(n, v, fail)
+	case string:
+		s.checkString(n, v, fail)
+	case []any:
+		errs = append(errs, s.checkArray(n, v, path, fail)...)
+	case map[string]any:
+		errs = append(errs, s.checkObject(n, v, path, refChain, fail)...)
+	}
+
+	if all, ok := n["allOf"].([]any); ok {
+		for _, sub := range all {
+			errs = append(errs, s.check(sub, inst, path, refChain)...)
+		}
+	}
+	if anyOf, ok := n["anyOf"].([]any); ok {
+		if !slices.ContainsFunc(anyOf, func(sub any) bool { return len(s.check(sub, inst, path, refChain)) == 0 }) {
+			fail("anyOf", "value matches none of the anyOf schemas")
+		}
+	}
+	if oneOf, ok := n["oneOf"].([]any); ok {
+		matched := 0
+		for _, sub := range oneOf {
+			if len(s.check(sub, inst, path, refChain)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("oneOf", "value matches %d of the oneOf schemas, want exactly 1", matched)
+		}
+	}
+	if not, ok := n["not"]; ok && len(s.check(not, inst, path, refChain)) == 0 {
+		fail("not", "value matches a schema it must not match")
+	}
+	if cond, ok := n["if"]; ok {
+		if len(s.check(cond, inst, path, refChain)) == 0 {
+			if then, ok := n["then"]; ok {
+				errs = append(errs, s.check(then, inst, path, refChain)...)
+			}
+		} else if els, ok := n["else"]; ok {
+			errs = append(errs, s.check(els, inst, path, refChain)...)
+		}
+	}
+	return errs
+}
+
+type failFunc func(kw, format string, args ...any)
+
+func (s *JSONSchema) checkNumber(n map[string]any, num json.Number, fail failFunc) {
+	x, ok := jsonRat(num)
+	if !ok {
+		return
+	}
+	bound := func(kw string) (*big.Rat, bool) {
+		b, ok := n[kw].(json.Number)
+		if !ok {
+			return nil, false
+		}
+		return jsonRat(b)
+	}
+	if min, ok := bound("minimum"); ok {
+		if n["exclusiveMinimum"] == true { // draft-04 boolean form
+			if x.Cmp(min) <= 0 {
+				fail("exclusiveMinimum", "%s must be greater than %s", num, n["minimum"])
+			}
+		} else if x.Cmp(min) < 0 {
+			fail("minimum", "%s is less than minimum %s", num, n["minimum"])
+		}
+	}
+	if max, ok := bound("maximum"); ok {
+		if n["exclusiveMaximum"] == true {
+			if x.Cmp(max) >= 0 {
+				fail("exclusiveMaximum", "%s must be less than %s", num, n["maximum"])
+			}
+		} else if x.Cmp(max) > 0 {
+			fail("maximum", "%s is greater than maximum %s", num, n["maximum"])
+		}
+	}
+	if min, ok := bound("exclusiveMinimum"); ok && x.Cmp(min) <= 0 {
+		fail("exclusiveMinimum", "%s must be greater than %s", num, n["exclusiveMinimum"])
+	}
+	if max, ok := bound("exclusiveMaximum"); ok && x.Cmp(max) >= 0 {
+		fail("exclusiveMaximum", "%s must be less than %s", num, n["exclusiveMaximum"])
+	}
+	if m, ok := bound("multipleOf"); ok && m.Sign() > 0 {
+		if !new(big.Rat).Quo(x, m).IsInt() {
+			fail("multipleOf", "%s is not a multiple of %s", num, n["multipleOf"])
+		}
+	}
+}
+
+func (s *JSONSchema) checkString(n map[string]any, str string, fail failFunc) {
+	length := utf8.RuneCountInString(str)
+	if min, ok := jsonInt(n["minLength"]); ok && length < min {
+		fail("minLength", "length %d is less than minLength %d", length, min)
+	}
+	if max, ok := jsonInt(n["maxLength"]); ok && length > max {
+		fail("maxLength", "length %d is greater than maxLength %d", length, max)
+	}
+	if p, ok := n["pattern"].(string); ok && !s.regexps[p].MatchString(str) {
+		fail("pattern", "value %q does not match pattern %q", str, p)
+	}
+	if f, ok := n["format"].(string); ok && !jsonFormatOK(f, str) {
+		fail("format", "value %q is not a valid %s", str, f)
+	}
+}
+
+func (s *JSONSchema) checkArray(n map[string]any, arr []any, path string, fail failFunc) []*JSONSchemaViolation {
+	var errs []*JSONSchemaViolation
+	at := func(i int) string { return path + "/" + strconv.Itoa(i) }
+
+	// prefixItems with items (2020-12), or items as an array with
+	// additionalItems (draft-07).
+	prefix, _ := n["prefixItems"].([]any)
+	rest, hasRest := n["items"]
+	if tuple, ok := rest.([]any); ok {
+		prefix = tuple
+		rest, hasRest = n["additionalItems"]
+	}
+	for i, item := range arr {
+		switch {
+		case i < len(prefix):
+			errs = append(errs, s.check(prefix[i], item, at(i), 0)...)
+		case hasRest:
+			errs = append(errs, s.check(rest, item, at(i), 0)...)
+		}
+	}
+
+	if min, ok := jsonInt(n["minItems"]); ok && len(arr) < min {
+		fail("minItems", "array has %d items, fewer than minItems %d", len(arr), min)
+	}
+	if max, ok := jsonInt(n["maxItems"]); ok && len(arr) > max {
+		fail("maxItems", "array has %d items, more than maxItems %d", len(arr), max)
+	}
+	if n["uniqueItems"] == true {
+	unique:
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					fail("uniqueItems", "items %d and %d are equal", i, j)
+					break unique
+				}
+			}
+		}
+	}
+	if contains, ok := n["contains"]; ok {
+		matched := 0
+		for i, item := range arr {
+			if len(s.check(contains, item, at(i), 0)) == 0 {
+				matched++
+			}
+		}
+		min, hasMin := jsonInt(n["minContains"])
+		if !hasMin {
+			min = 1
+		}
+		if matched < min {
+			fail("contains", "array has %d items matching contains, want at least %d", matched, min)
+		}
+		if max, ok := jsonInt(n["maxContains"]); ok && matched > max {
+			fail("maxContains", "array has %d items matching contains, want at most %d", matched, max)
+		}
+	}
+	return errs
+}
+
+func (s *JSONSchema) checkObject(n map[string]any, obj map[string]any, path string, refChain int, fail failFunc) []*JSONSchemaViolation {
+	var errs []*JSONSchemaViolation
+	at := func(key string) string { return path + "/" + escapeJSONPointer(key) }
+
+	props, _ := n["properties"].(map[string]any)
+	patternProps, _ := n["patternProperties"].(map[string]any)
+	additional, hasAdditional := n["additionalProperties"]
+	names, hasNames := n["propertyNames"]
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys) // report violations in a stable order
+	for _, k := range keys {
+		val := obj[k]
+		matched := false
+		if sub, ok := props[k]; ok {
+			matched = true
+			errs = append(errs, s.check(sub, val, at(k), 0)...)
+		}
+		for p, sub := range patternProps {
+			if s.regexps[p].MatchString(k) {
+				matched = true
+				errs = append(errs, s.check(sub, val, at(k), 0)...)
+			}
+		}
+		if !matched && hasAdditional {
+			if additional == false {
+				fail("additionalProperties", "property %q is not allowed", k)
+			} else {
+				errs = append(errs, s.check(additional, val, at(k), 0)...)
+			}
+		}
+		if hasNames && len(s.check(names, k, at(k), 0)) > 0 {
+			fail("propertyNames", "property name %q is not allowed", k)
+		}
+	}
+
+	if req, ok := n["required"].([]any); ok {
+		for _, r := range req {
+			if name, ok := r.(string); ok {
+				if _, present := obj[name]; !present {
+					fail("required", "missing required property %q", name)
+				}
+			}
+		}
+	}
+	if min, ok := jsonInt(n["minProperties"]); ok && len(obj) < min {
+		fail("minProperties", "object has %d properties, fewer than minProperties %d", len(obj), min)
+	}
+	if max, ok := jsonInt(n["maxProperties"]); ok && len(obj) > max {
+		fail("maxProperties", "object has %d properties, more than maxProperties %d", len(obj), max)
+	}
+
+	deps := map[string]any{}
+	for _, kw := range []string{"dependencies", "dependentRequired", "dependentSchemas"} {
+		if m, ok := n[kw].(map[string]any); ok {
+			for k, v := range m {
+				deps[k] = v
+			}
+		}
+	}
+	depKeys := make([]string, 0, len(deps))
+	for k := range deps {
+		depKeys = append(depKeys, k)
+	}
+	slices.Sort(depKeys)
+	for _, k := range depKeys {
+		if _, present := obj[k]; !present {
+			continue
+		}
+		if list, ok := deps[k].([]any); ok {
+			for _, r := range list {
+				if name, ok := r.(string); ok {
+					if _, present := obj[name]; !present {
+						fail("dependentRequired", "property %q requires property %q", k, name)
+					}
+				}
+			}
+		} else {
+			errs = append(errs, s.check(deps[k], obj, path, refChain)...)
+		}
+	}
+	return errs
+}
+
+func jsonTypeName(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if jsonHasType(v, "integer") {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func jsonHasType(v any, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		r, ok := jsonRat(n)
+		return ok && r.IsInt()
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return false
+}
+
+func jsonRat(n json.Number) (*big.Rat, bool) {
+	return new(big.Rat).SetString(string(n))
+}
+
+func jsonInt(v any) (int, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(string(n))
+	return i, err == nil
+}
+
+// jsonEqual compares decoded JSON values, numbers by value (1 == 1.0).
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okA := jsonRat(a)
+		y, okB := jsonRat(b)
+		return okA && okB && x.Cmp(y) == 0
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, jsonEqual)
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, va := range a {
+			vb, ok := b[k]
+			if !ok || !jsonEqual(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+var jsonDurationPattern = regexp.MustCompile(`^P(?:\d+W|(?:\d+Y)?(?:\d+M)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+S)?)?)$`)
+
+func jsonFormatOK(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05.999999999Z07:00", s)
+		return err == nil
+	case "duration":
+		return jsonDurationPattern.MatchString(s) && !strings.HasSuffix(s, "T") && s != "P"
+	case "email":
+		return (&EmailValidator{Strict: true}).Validate(s) == nil
+	case "hostname":
+		return len(s) <= 253 && allLabels(strings.TrimSuffix(s, "."))
+	case "ipv4":
+		a, err := netip.ParseAddr(s)
+		return err == nil && a.Is4()
+	case "ipv6":
+		a, err := netip.ParseAddr(s)
+		return err == nil && a.Is6() && a.Zone() == ""
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "regex":
+		_, err := regexp.Compile(s)
+		return err == nil
+	}
+	return true
+}
+
+func allLabels(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		if !dnsLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,268 @@
+package validators
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestJSONSchemaKeywords(t *testing.T) {
+	tests := []struct {
+		schema string
+		doc    string
+		ok     bool
+	}{
+		{`true`, `{"anything": [1]}`, true},
+		{`false`, `null`, false},
+		{`{"type": "string"}`, `"x"`, true},
+		{`{"type": "string"}`, `1`, false},
+		{`{"type": ["string", "null"]}`, `null`, true},
+		{`{"type": "integer"}`, `3.0`, true},
+		{`{"type": "integer"}`, `3.5`, false},
+		{`{"enum": ["a", 1, {"b": [true]}]}`, `{"b": [true]}`, true},
+		{`{"enum": ["a", 1]}`, `1.0`, true},
+		{`{"enum": ["a", 1]}`, `"b"`, false},
+		{`{"const": {"x": 1}}`, `{"x": 1}`, true},
+		{`{"const": {"x": 1}}`, `{"x": 1, "y": 2}`, false},
+
+		{`{"minimum": 1, "maximum": 10}`, `10`, true},
+		{`{"minimum": 1, "maximum": 10}`, `10.5`, false},
+		{`{"exclusiveMinimum": 0}`, `0`, false},
+		{`{"exclusiveMaximum": 1}`, `0.999`, true},
+		{`{"minimum": 0, "exclusiveMinimum": true}`, `0`, false}, // draft-04
+		{`{"multipleOf": 0.1}`, `0.3`, true},
+		{`{"multipleOf": 0.1}`, `0.35`, false},
+		{`{"maximum": 100}`, `"1000"`, true}, // numeric keywords ignore strings
+
+		{`{"minLength": 3, "maxLength": 3}`, `"Zoë"`, true},
+		{`{"maxLength": 2}`, `"Zoë"`, false},
+		{`{"pattern": "^[A-Z]{3}$"}`, `"EUR"`, true},
+		{`{"pattern": "^[A-Z]{3}$"}`, `"eur"`, false},
+		{`{"format": "date-time"}`, `"2026-10-18T09:30:00Z"`, true},
+		{`{"format": "date-time"}`, `"2026-10-18"`, false},
+		{`{"format": "date"}`, `"2026-02-30"`, false},
+		{`{"format": "time"}`, `"09:30:00.5+02:00"`, true},
+		{`{"format": "duration"}`, `"P1DT12H"`, true},
+		{`{"format": "duration"}`, `"P1DT"`, false},
+		{`{"format": "email"}`, `"ada@example.com"`, true},
+		{`{"format": "email"}`, `"Ada <ada@example.com>"`, false},
+		{`{"format": "hostname"}`, `"api.example.com"`, true},
+		{`{"format": "hostname"}`, `"-bad.example"`, false},
+		{`{"format": "ipv4"}`, `"::1"`, false},
+		{`{"format": "ipv6"}`, `"::1"`, true},
+		{`{"format": "uri"}`, `"https://example.com/x"`, true},
+		{`{"format": "uri"}`, `"/relative"`, false},
+		{`{"format": "uuid"}`, `"123e4567-e89b-12d3-a456-426614174000"`, true},
+		{`{"format": "x-custom"}`, `"anything"`, true},
+
+		{`{"items": {"type": "integer"}, "minItems": 1, "maxItems": 3}`, `[1, 2]`, true},
+		{`{"items": {"type": "integer"}}`, `[1, "2"]`, false},
+		{`{"maxItems": 1}`, `[1, 2]`, false},
+		{`{"prefixItems": [{"type": "string"}], "items": false}`, `["a"]`, true},
+		{`{"prefixItems": [{"type": "string"}], "items": false}`, `["a", 1]`, false},
+		{`{"items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`, `["a", 1, 2]`, true},
+		{`{"items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`, `[1]`, false},
+		{`{"uniqueItems": true}`, `[1, "1", [1]]`, true},
+		{`{"uniqueItems": true}`, `[{"a": 1}, {"a": 1.0}]`, false},
+		{`{"contains": {"const": 3}}`, `[1, 3]`, true},
+		{`{"contains": {"const": 3}}`, `[1, 2]`, false},
+		{`{"contains": {"const": 3}, "maxContains": 1}`, `[3, 3]`, false},
+		{`{"contains": {"const": 3}, "minContains": 0}`, `[]`, true},
+
+		{`{"properties": {"a": {"type": "string"}}, "required": ["a"]}`, `{"a": "x"}`, true},
+		{`{"properties": {"a": {"type": "string"}}, "required": ["a"]}`, `{}`, false},
+		{`{"properties": {"a": {"type": "string"}}, "additionalProperties": false}`, `{"a": "x", "b": 1}`, false},
+		{`{"patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`, `{"x-id": "1"}`, true},
+		{`{"patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`, `{"x-id": 1}`, false},
+		{`{"additionalProperties": {"type": "integer"}}`, `{"a": 1, "b": 2}`, true},
+		{`{"propertyNames": {"maxLength": 3}}`, `{"abcd": 1}`, false},
+		{`{"minProperties": 1}`, `{}`, false},
+		{`{"maxProperties": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"dependentRequired": {"card": ["cvc"]}}`, `{"card": "x"}`, false},
+		{`{"dependentRequired": {"card": ["cvc"]}}`, `{"iban": "x"}`, true},
+		{`{"dependencies": {"card": {"required": ["cvc"]}}}`, `{"card": "x", "cvc": "1"}`, true},
+		{`{"dependentSchemas": {"card": {"required": ["cvc"]}}}`, `{"card": "x"}`, false},
+
+		{`{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, `3`, false},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 5}]}`, `7`, true},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 5}]}`, `3`, false},
+		{`{"oneOf": [{"type": "integer"}, {"minimum": 5}]}`, `3`, true},
+		{`{"oneOf": [{"type": "integer"}, {"minimum": 5}]}`, `7`, false},
+		{`{"not": {"type": "null"}}`, `null`, false},
+		{`{"if": {"properties": {"country": {"const": "NL"}}}, "then": {"required": ["postcode"]}, "else": {"required": ["zip"]}}`, `{"country": "NL", "postcode": "1234AB"}`, true},
+		{`{"if": {"properties": {"country": {"const": "NL"}}}, "then": {"required": ["postcode"]}, "else": {"required": ["zip"]}}`, `{"country": "US"}`, false},
+
+		{`{"$defs": {"pos": {"minimum": 0}}, "items": {"$ref": "#/$defs/pos"}}`, `[0, 1]`, true},
+		{`{"$defs": {"pos": {"minimum": 0}}, "items": {"$ref": "#/$defs/pos"}}`, `[0, -1]`, false},
+		{`{"definitions": {"a~b": {"type": "string"}}, "$ref": "#/definitions/a~0b"}`, `"x"`, true},
+		{`{"type": "object", "properties": {"next": {"$ref": "#"}}, "required": ["v"]}`, `{"v": 1, "next": {"v": 2, "next": {"v": 3}}}`, true},
+		{`{"type": "object", "properties": {"next": {"$ref": "#"}}, "required": ["v"]}`, `{"v": 1, "next": {"next": {"v": 3}}}`, false},
+		{`{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`, `1`, false}, // cycle
+
+		{`{"type": "string"}`, `not json`, false},
+		{`{"type": "string"}`, `"a" "b"`, false},
+	}
+	for _, tc := range tests {
+		s, err := CompileJSONSchema([]byte(tc.schema))
+		if err != nil {
+			t.Errorf("CompileJSONSchema(%s): %v", tc.schema, err)
+			continue
+		}
+		err = s.Validate([]byte(tc.doc))
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("schema %s, doc %s: expected ok=%v, got ok=%v (err: %v)", tc.schema, tc.doc, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	bad := []string{
+		`{`,
+		`"string"`,
+		`{"items": 5}`,
+		`{"allOf": []}`,
+		`{"pattern": "("}`,
+		`{"patternProperties": {"(": {}}}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"$ref": "other.json"}`,
+		`{"$id": "https://example.com/s"}`,
+		`{"unevaluatedProperties": false}`,
+	}
+	for _, schema := range bad {
+		if _, err := CompileJSONSchema([]byte(schema)); err == nil {
+			t.Errorf("CompileJSONSchema(%s): expected error", schema)
+		}
+	}
+}
+
+func TestJSONSchemaError(t *testing.T) {
+	s := MustCompileJSONSchema([]byte(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"tags": {"items": {"type": "string"}},
+			"a/b": {"type": "integer"}
+		}
+	}`))
+	err := s.Validate([]byte(`{"tags": ["x", 2], "a/b": "no"}`))
+	var schemaErr *JSONSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected *JSONSchemaError, got %T: %v", err, err)
+	}
+	got := map[string]string{}
+	for _, v := range schemaErr.Violations {
+		got[v.Path] = v.Keyword
+	}
+	want := map[string]string{"": "required", "/tags/1": "type", "/a~1b": "type"}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %v, want %v", schemaErr.Violations, want)
+	}
+	for path, kw := range want {
+		if got[path] != kw {
+			t.Errorf("violation at %q: got keyword %q, want %q", path, got[path], kw)
+		}
+	}
+	var v *JSONSchemaViolation
+	if !errors.As(err, &v) {
+		t.Error("expected errors.As to reach a *JSONSchemaViolation")
+	}
+}
+
+var testSchemaFS = fstest.MapFS{
+	"schemas/address.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["city"],
+		"properties": {"city": {"type": "string", "minLength": 1}},
+		"$defs": {"zip": {"pattern": "^[0-9]{5}$"}}
+	}`)},
+	"schemas/user.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["name", "address"],
+		"properties": {
+			"name": {"type": "string"},
+			"address": {"$ref": "address.json"},
+			"zip": {"$ref": "address#/$defs/zip"}
+		}
+	}`)},
+	"other/ignored.json": {Data: []byte(`{`)},
+}
+
+func TestLoadJSONSchemas(t *testing.T) {
+	set, err := LoadJSONSchemas(testSchemaFS, "schemas/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 || set["user"] == nil || set["address"] == nil {
+		t.Fatalf("unexpected set: %v", set)
+	}
+	user := set["user"]
+	if err := user.Validate([]byte(`{"name": "Ada", "address": {"city": "London"}, "zip": "12345"}`)); err != nil {
+		t.Errorf("expected valid user, got %v", err)
+	}
+	if err := user.Validate([]byte(`{"name": "Ada", "address": {"city": ""}, "zip": "1234"}`)); err == nil {
+		t.Error("expected invalid address and zip to fail")
+	}
+
+	if _, err := LoadJSONSchemas(testSchemaFS, "other/*.json"); err == nil {
+		t.Error("expected a malformed schema to fail loading")
+	}
+	dangling := fstest.MapFS{"a.json": {Data: []byte(`{"$ref": "b.json"}`)}}
+	if _, err := LoadJSONSchemas(dangling, "*.json"); err == nil {
+		t.Error("expected a dangling cross-schema $ref to fail loading")
+	}
+}
+
+func TestJSONSchemaTags(t *testing.T) {
+	set, err := LoadJSONSchemas(testSchemaFS, "schemas/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := valex.NewRegistry(WithJSONSchemas(set))
+	valex.MustRegisterDirectiveTo(reg, &JSONSchemaValidator{})
+	valex.MustRegisterDirectiveTo(reg, &RawJSONSchemaValidator{})
+
+	type request struct {
+		User    string          `val:"jsonschema,schema=user,maxbytes=1024"`
+		Address json.RawMessage `val:"rawjsonschema,schema=address,maxdepth=2"`
+	}
+	good := request{
+		User:    `{"name": "Ada", "address": {"city": "London"}}`,
+		Address: json.RawMessage(`{"city": "Paris"}`),
+	}
+	if err := reg.ValidateStruct(&good); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+	bad := good
+	bad.User = `{"name": "Ada"}`
+	if err := reg.ValidateStruct(&bad); err == nil {
+		t.Fatal("expected a user without an address to fail")
+	}
+	deep := good
+	deep.Address = json.RawMessage(`{"city": "Paris", "extra": {"a": {"b": 1}}}`)
+	if err := reg.ValidateStruct(&deep); err == nil {
+		t.Fatal("expected maxdepth to reject a deeply nested address")
+	}
+
+	type unknown struct {
+		Doc string `val:"jsonschema,schema=order"`
+	}
+	if err := reg.ValidateStruct(&unknown{`{}`}); err == nil {
+		t.Fatal("expected an unknown schema name to fail")
+	}
+
+	// A directive's own Schemas takes precedence over the registry's.
+	own := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(own, &JSONSchemaValidator{Schemas: JSONSchemas{
+		"user": MustCompileJSONSchema([]byte(`{"type": "object"}`)),
+	}})
+	type loose struct {
+		User string `val:"jsonschema,schema=user"`
+	}
+	if err := own.ValidateStruct(&loose{`{}`}); err != nil {
+		t.Fatalf("expected the directive's own schema to apply, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package validators
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Length units accepted by the "unit" parameter of the min, max, and len
+// directives.
+const (
+	UnitBytes     = "bytes"
+	UnitRunes     = "runes"
+	UnitGraphemes = "graphemes"
+)
+
+// stringLength measures val in the given unit. An empty unit means bytes, which
+// keeps the historical len(val) behavior of the length directives.
+func stringLength(val, unit string) (int, error) {
+	switch unit {
+	case "", UnitBytes:
+		return len(val), nil
+	case UnitRunes:
+		return utf8.RuneCountInString(val), nil
+	case UnitGraphemes:
+		return graphemeCount(val), nil
+	}
+	return 0, fmt.Errorf(`invalid unit %q, expected "bytes", "runes", or "graphemes"`, unit)
+}
+
+// unitSuffix names unit after a length in an error message. The default unit
+// adds nothing, keeping the messages of tags that do not set one.
+func unitSuffix(unit string) string {
+	if unit == "" {
+		return ""
+	}
+	return " " + unit
+}
+
+// graphemeCount counts user-perceived characters in s. It approximates the
+// extended grapheme cluster rules of UAX #29 without pulling in golang.org/x/text:
+// CR LF stays together; combining marks, variation selectors, emoji modifiers,
+// and ZWJ attach to the preceding character; a ZWJ followed by a pictograph
+// joins it (emoji ZWJ sequences); regional indicators pair into flags; and
+// Hangul jamo sequences form one syllable. Invalid UTF-8 counts one per byte.
+func graphemeCount(s string) int {
+	count := 0
+	prev := gcbNone
+	riRun := 0 // consecutive regional indicators in the current cluster
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		cur := graphemeBreakClass(r)
+		if count == 0 || graphemeBreak(prev, cur, riRun) {
+			count++
+			riRun = 0
+		}
+		if cur == gcbRegional {
+			riRun++
+		} else {
+			riRun = 0
+		}
+		prev = cur
+	}
+	return count
+}
+
+type gcbClass int
+
+const (
+	gcbNone gcbClass = iota
+	gcbOther
+	gcbCR
+	gcbLF
+	gcbControl
+	gcbExtend
+	gcbZWJ
+	gcbRegional
+	gcbPictographic
+	gcbL
+	gcbV
+	gcbT
+	gcbLV
+	gcbLVT
+)
+
+// graphemeBreak reports whether a cluster boundary falls between a rune of
+// class prev and one of class cur. riRun is the number of regional indicators
+// already in the current cluster.
+func graphemeBreak(prev, cur gcbClass, riRun int) bool {
+	switch {
+	case prev == gcbCR && cur == gcbLF:
+		return false
+	case prev == gcbCR || prev == gcbLF || prev == gcbControl:
+		return true
+	case cur == gcbCR || cur == gcbLF || cur == gcbControl:
+		return true
+	case prev == gcbL && (cur == gcbL || cur == gcbV || cur == gcbLV || cur == gcbLVT):
+		return false
+	case (prev == gcbLV || prev == gcbV) && (cur == gcbV || cur == gcbT):
+		return false
+	case (prev == gcbLVT || prev == gcbT) && cur == gcbT:
+		return false
+	case cur == gcbExtend || cur == gcbZWJ:
+		return false
+	case prev == gcbZWJ && cur == gcbPictographic:
+		return false
+	case prev == gcbRegional && cur == gcbRegional:
+		return riRun%2 == 0
+	}
+	return true
+}
+
+func graphemeBreakClass(r rune) gcbClass {
+	switch {
+	case r == '\r':
+		return gcbCR
+	case r == '\n':
+		return gcbLF
+	case r == 0x200D:
+		return gcbZWJ
+	case r == utf8.RuneError:
+		return gcbOther
+	case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), unicode.Is(unicode.Mc, r),
+		r >= 0xFE00 && r <= 0xFE0F, // variation selectors
+		r >= 0xE0100 && r <= 0xE01EF,
+		r >= 0x1F3FB && r <= 0x1F3FF, // emoji skin-tone modifiers
+		r >= 0xE0020 && r <= 0xE007F: // tag characters (subdivision flags)
+		return gcbExtend
+	case unicode.IsControl(r), r == 0x2028, r == 0x2029:
+		return gcbControl
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gcbRegional
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gcbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gcbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gcbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gcbLV
+		}
+		return gcbLVT
+	case isPictographic(r):
+		return gcbPictographic
+	}
+	return gcbOther
+}
+
+// isPictographic approximates the Extended_Pictographic property with the
+// blocks that hold emoji.
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139:
+		return true
+	case r >= 0x2194 && r <= 0x21AA,
+		r >= 0x231A && r <= 0x23FF,
+		r >= 0x25AA && r <= 0x27BF,
+		r >= 0x2934 && r <= 0x2935,
+		r >= 0x2B05 && r <= 0x2B55,
+		r >= 0x3030 && r <= 0x303D,
+		r >= 0x1F000 && r <= 0x1F0FF,
+		r >= 0x1F10D && r <= 0x1F1AD,
+		r >= 0x1F201 && r <= 0x1F64F,
+		r >= 0x1F680 && r <= 0x1FAFF:
+		return true
+	}
+	return false
+}
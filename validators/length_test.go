@@ -0,0 +1,108 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"Zoë", 3},       // precomposed ë
+		{"Zoe\u0308", 3}, // e + combining diaeresis
+		{"\r\n", 1},      // CR LF is one cluster
+		{"\n\n", 2},      // but two LFs are two
+		{"日本語", 3},       // CJK
+		{"🇳🇱🇧🇪", 2},      // two flags (regional indicator pairs)
+		{"👍🏽", 1},        // emoji + skin-tone modifier
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467", 1}, // ZWJ family sequence
+		{"\u2764\ufe0f", 1},       // emoji + variation selector
+		{"\u1100\u1161\u11a8", 1}, // Hangul L V T jamo
+		{"한국", 2},
+		{"\xff\xfe", 2}, // invalid UTF-8: one per byte
+	}
+	for _, tc := range tests {
+		if got := graphemeCount(tc.input); got != tc.want {
+			t.Errorf("graphemeCount(%q) = %d, want %d", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestLengthUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     valex.Validator[string]
+		input string
+		ok    bool
+	}{
+		{"bytes by default", &MaxLengthValidator{Size: 3}, "Zoë", false},
+		{"explicit bytes", &MaxLengthValidator{Size: 3, Unit: UnitBytes}, "Zoë", false},
+		{"runes", &MaxLengthValidator{Size: 3, Unit: UnitRunes}, "Zoë", true},
+		{"runes count combining marks", &MaxLengthValidator{Size: 3, Unit: UnitRunes}, "Zoë", false},
+		{"graphemes", &MaxLengthValidator{Size: 3, Unit: UnitGraphemes}, "Zoë", true},
+		{"cjk runes", &MaxLengthValidator{Size: 3, Unit: UnitRunes}, "日本語", true},
+		{"cjk bytes", &MaxLengthValidator{Size: 3}, "日本語", false},
+		{"min runes", &MinLengthValidator{Size: 3, Unit: UnitRunes}, "日本", false},
+		{"min graphemes", &MinLengthValidator{Size: 2, Unit: UnitGraphemes}, "🇳🇱🇧🇪", true},
+		{"range runes", &LengthRangeValidator{Min: 2, Max: 3, Unit: UnitRunes}, "日本語", true},
+		{"range graphemes", &LengthRangeValidator{Min: 2, Max: 3, Unit: UnitGraphemes}, "\U0001F468\u200d\U0001F469\u200d\U0001F467", false},
+		{"unknown unit", &MaxLengthValidator{Size: 3, Unit: "words"}, "abc", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.v.Validate(tc.input)
+			if ok := err == nil; ok != tc.ok {
+				t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+			}
+		})
+	}
+}
+
+func TestLengthUnitErrors(t *testing.T) {
+	tests := []struct {
+		v     valex.Validator[string]
+		input string
+		want  string
+	}{
+		{&MaxLengthValidator{Size: 4, Unit: UnitRunes}, "Zoëys", "exceeds maximum length 4 runes"},
+		{&MaxLengthValidator{Size: 4, Unit: UnitBytes}, "Zoëy", "exceeds maximum length 4 bytes"},
+		{&MaxLengthValidator{Size: 4}, "Zoëy", "exceeds maximum length 4"},
+		{&MinLengthValidator{Size: 3, Unit: UnitGraphemes}, "🇳🇱🇧🇪", "shorter than minimum length 3 graphemes"},
+		{&LengthRangeValidator{Min: 2, Max: 3, Unit: UnitRunes}, "日本語です", "with length 5 runes is not in range [2, 3]"},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		if err == nil || !strings.HasSuffix(err.Error(), tc.want) {
+			t.Errorf("%T(%q) = %v, want an error ending %q", tc.v, tc.input, err, tc.want)
+		}
+	}
+}
+
+func TestLengthUnitTag(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &MaxLengthValidator{})
+
+	type Profile struct {
+		Name string `val:"max,size=4,unit=runes"`
+	}
+	if err := reg.ValidateStruct(&Profile{Name: "Zoë"}); err != nil {
+		t.Fatalf("expected Zoë to fit in 4 runes, got %v", err)
+	}
+	if err := reg.ValidateStruct(&Profile{Name: "Zoëys"}); err == nil {
+		t.Fatal("expected 5 runes to exceed max 4")
+	}
+
+	type Bad struct {
+		Name string `val:"max,size=4,unit=words"`
+	}
+	err := reg.ValidateStruct(&Bad{Name: "x"})
+	if err == nil || !strings.Contains(err.Error(), `invalid unit "words"`) {
+		t.Fatalf("expected invalid unit error, got %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // embed the IANA database so timezone works without system zoneinfo
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// CountryValidator validates an ISO 3166-1 country code against an embedded
+// table of the officially assigned codes. Alpha selects the form: 2 for alpha-2
+// ("NL"), 3 for alpha-3 ("NLD"), or 0 (the default) for either. Codes must be
+// uppercase.
+type CountryValidator struct {
+	Alpha int `param:"alpha,required=false"`
+}
+
+// Validate checks whether the value is an assigned country code.
+func (v *CountryValidator) Validate(val string) error {
+	if v.Alpha != 0 && v.Alpha != 2 && v.Alpha != 3 {
+		return fmt.Errorf("alpha must be 2 or 3, got %d", v.Alpha)
+	}
+	if (v.Alpha == 0 || v.Alpha == len(val)) && isCountry(val) {
+		return nil
+	}
+	if v.Alpha != 0 {
+		return fmt.Errorf("value %q is not an ISO 3166-1 alpha-%d country code", val, v.Alpha)
+	}
+	return fmt.Errorf("value %q is not an ISO 3166-1 country code", val)
+}
+
+// Name returns the directive identifier.
+func (v *CountryValidator) Name() string {
+	return "country"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *CountryValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *CountryValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// CurrencyValidator validates an ISO 4217 alphabetic currency code ("EUR")
+// against an embedded table of the active codes, including funds and precious
+// metals. Codes must be uppercase.
+type CurrencyValidator struct{}
+
+// Validate checks whether the value is an active currency code.
+func (v *CurrencyValidator) Validate(val string) error {
+	if len(val) != 3 || !isUpperAlpha(val) || !strings.Contains(currencyCodes, " "+val+" ") {
+		return fmt.Errorf("value %q is not an ISO 4217 currency code", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *CurrencyValidator) Name() string {
+	return "currency"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *CurrencyValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *CurrencyValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// LanguageValidator validates a BCP 47 language tag ("en", "pt-BR",
+// "zh-Hant-TW", "sr-Latn-RS", "de-CH-1996", "x-klingon"). The tag must be
+// well-formed per RFC 5646, matched case-insensitively; a two-letter primary
+// language must be an ISO 639-1 code and a two-letter region an ISO 3166-1 code
+// (or one of the BCP 47 additions such as "EU" and "419"-style UN M.49 areas).
+// Longer primary languages and other subtags are checked for form only, and
+// irregular grandfathered tags ("i-klingon") are not accepted.
+type LanguageValidator struct{}
+
+// Validate checks whether the value is a language tag.
+func (v *LanguageValidator) Validate(val string) error {
+	if err := checkLanguageTag(val); err != nil {
+		return fmt.Errorf("value %q is not a valid BCP 47 language tag: %w", val, err)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *LanguageValidator) Name() string {
+	return "language"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *LanguageValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *LanguageValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// TimezoneValidator validates an IANA time zone name ("Europe/Amsterdam",
+// "UTC") with time.LoadLocation. The package embeds the time zone database, so
+// the result does not depend on the host's zoneinfo. The empty string and
+// "Local", which LoadLocation also accepts, are rejected.
+type TimezoneValidator struct{}
+
+// Validate checks whether the value is a time zone name.
+func (v *TimezoneValidator) Validate(val string) error {
+	if val == "" || val == "Local" {
+		return fmt.Errorf("value %q is not an IANA time zone name", val)
+	}
+	if _, err := time.LoadLocation(val); err != nil {
+		return fmt.Errorf("value %q is not an IANA time zone name", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *TimezoneValidator) Name() string {
+	return "timezone"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *TimezoneValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *TimezoneValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// checkLanguageTag checks tag against the RFC 5646 langtag and privateuse
+// productions:
+//
+//	language ["-" script] ["-" region] *("-" variant) *("-" extension) ["-" privateuse]
+func checkLanguageTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("empty tag")
+	}
+	subtags := strings.Split(strings.ToLower(tag), "-")
+	for _, s := range subtags {
+		if s == "" || len(s) > 8 || !allRunes(s, isASCIIAlnum) {
+			return fmt.Errorf("malformed subtag %q", s)
+		}
+	}
+	if subtags[0] == "x" {
+		return checkPrivateUse(subtags[1:])
+	}
+
+	// Primary language: 2-3 letters with up to three extlang subtags, or a
+	// reserved/registered 4-8 letter subtag.
+	lang := subtags[0]
+	i := 1
+	switch {
+	case !isASCIIAlpha(lang) || len(lang) < 2:
+		return fmt.Errorf("invalid language subtag %q", lang)
+	case len(lang) == 2:
+		if !strings.Contains(languageCodes, " "+lang+" ") {
+			return fmt.Errorf("unknown ISO 639-1 language %q", lang)
+		}
+	}
+	if len(lang) <= 3 {
+		for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isASCIIAlpha(subtags[i]); n++ {
+			i++
+		}
+	}
+	if i < len(subtags) && len(subtags[i]) == 4 && isASCIIAlpha(subtags[i]) {
+		i++ // script
+	}
+	if i < len(subtags) {
+		switch r := subtags[i]; {
+		case len(r) == 2 && isASCIIAlpha(r):
+			if !isLanguageRegion(strings.ToUpper(r)) {
+				return fmt.Errorf("unknown region %q", r)
+			}
+			i++
+		case len(r) == 3 && isDigits(r):
+			i++
+		}
+	}
+	for i < len(subtags) && isVariant(subtags[i]) {
+		i++
+	}
+	seen := map[string]bool{}
+	for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+		singleton := subtags[i]
+		if seen[singleton] {
+			return fmt.Errorf("duplicate extension %q", singleton)
+		}
+		seen[singleton] = true
+		i++
+		start := i
+		for i < len(subtags) && len(subtags[i]) >= 2 {
+			i++
+		}
+		if i == start {
+			return fmt.Errorf("empty extension %q", singleton)
+		}
+	}
+	if i < len(subtags) && subtags[i] == "x" {
+		return checkPrivateUse(subtags[i+1:])
+	}
+	if i < len(subtags) {
+		return fmt.Errorf("unexpected subtag %q", subtags[i])
+	}
+	return nil
+}
+
+func checkPrivateUse(subtags []string) error {
+	if len(subtags) == 0 {
+		return fmt.Errorf("empty private use section")
+	}
+	return nil
+}
+
+// isVariant reports whether s is a variant subtag: 5-8 alphanumerics, or a
+// digit followed by three alphanumerics.
+func isVariant(s string) bool {
+	return len(s) >= 5 || len(s) == 4 && s[0] >= '0' && s[0] <= '9'
+}
+
+// isLanguageRegion reports whether r, uppercase, is a two-letter region BCP 47
+// accepts: an ISO 3166-1 code, an exceptionally reserved code in the subtag
+// registry, or a private-use code (AA, QM-QZ, XA-XZ, ZZ).
+func isLanguageRegion(r string) bool {
+	switch {
+	case isCountry(r), strings.Contains(" AC CP DG EA EU EZ IC TA UN ", " "+r+" "):
+		return true
+	case r == "AA", r == "ZZ", r[0] == 'Q' && r[1] >= 'M', r[0] == 'X':
+		return true
+	}
+	return false
+}
+
+func isCountry(code string) bool {
+	return (len(code) == 2 || len(code) == 3) && isUpperAlpha(code) && strings.Contains(countryCodes, " "+code+" ")
+}
+
+func isUpperAlpha(s string) bool {
+	return allRunes(s, func(r rune) bool { return r >= 'A' && r <= 'Z' })
+}
+
+func isASCIIAlpha(s string) bool {
+	return allRunes(s, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' })
+}
+
+func isASCIIAlnum(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// countryCodes lists the officially assigned ISO 3166-1 codes as alpha-2 and
+// alpha-3 pairs, space-delimited so a code is present iff " "+code+" " is.
+const countryCodes = " " +
+	"AF AFG AX ALA AL ALB DZ DZA AS ASM AD AND AO AGO AI AIA AQ ATA AG ATG " +
+	"AR ARG AM ARM AW ABW AU AUS AT AUT AZ AZE BS BHS BH BHR BD BGD BB BRB " +
+	"BY BLR BE BEL BZ BLZ BJ BEN BM BMU BT BTN BO BOL BQ BES BA BIH BW BWA " +
+	"BV BVT BR BRA IO IOT BN BRN BG BGR BF BFA BI BDI CV CPV KH KHM CM CMR " +
+	"CA CAN KY CYM CF CAF TD TCD CL CHL CN CHN CX CXR CC CCK CO COL KM COM " +
+	"CG COG CD COD CK COK CR CRI CI CIV HR HRV CU CUB CW CUW CY CYP CZ CZE " +
+	"DK DNK DJ DJI DM DMA DO DOM EC ECU EG EGY SV SLV GQ GNQ ER ERI EE EST " +
+	"SZ SWZ ET ETH FK FLK FO FRO FJ FJI FI FIN FR FRA GF GUF PF PYF TF ATF " +
+	"GA GAB GM GMB GE GEO DE DEU GH GHA GI GIB GR GRC GL GRL GD GRD GP GLP " +
+	"GU GUM GT GTM GG GGY GN GIN GW GNB GY GUY HT HTI HM HMD VA VAT HN HND " +
+	"HK HKG HU HUN IS ISL IN IND ID IDN IR IRN IQ IRQ IE IRL IM IMN IL ISR " +
+	"IT ITA JM JAM JP JPN JE JEY JO JOR KZ KAZ KE KEN KI KIR KP PRK KR KOR " +
+	"KW KWT KG KGZ LA LAO LV LVA LB LBN LS LSO LR LBR LY LBY LI LIE LT LTU " +
+	"LU LUX MO MAC MG MDG MW MWI MY MYS MV MDV ML MLI MT MLT MH MHL MQ MTQ " +
+	"MR MRT MU MUS YT MYT MX MEX FM FSM MD MDA MC MCO MN MNG ME MNE MS MSR " +
+	"MA MAR MZ MOZ MM MMR NA NAM NR NRU NP NPL NL NLD NC NCL NZ NZL NI NIC " +
+	"NE NER NG NGA NU NIU NF NFK MK MKD MP MNP NO NOR OM OMN PK PAK PW PLW " +
+	"PS PSE PA PAN PG PNG PY PRY PE PER PH PHL PN PCN PL POL PT PRT PR PRI " +
+	"QA QAT RE REU RO ROU RU RUS RW RWA BL BLM SH SHN KN KNA LC LCA MF MAF " +
+	"PM SPM VC VCT WS WSM SM SMR ST STP SA SAU SN SEN RS SRB SC SYC SL SLE " +
+	"SG SGP SX SXM SK SVK SI SVN SB SLB SO SOM ZA ZAF GS SGS SS SSD ES ESP " +
+	"LK LKA SD SDN SR SUR SJ SJM SE SWE CH CHE SY SYR TW TWN TJ TJK TZ TZA " +
+	"TH THA TL TLS TG TGO TK TKL TO TON TT TTO TN TUN TR TUR TM TKM TC TCA " +
+	"TV TUV UG UGA UA UKR AE ARE GB GBR US USA UM UMI UY URY UZ UZB VU VUT " +
+	"VE VEN VN VNM VG VGB VI VIR WF WLF EH ESH YE YEM ZM ZMB ZW ZWE "
+
+// currencyCodes lists the active ISO 4217 alphabetic codes, space-delimited.
+const currencyCodes = " " +
+	"AED AFN ALL AMD AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB " +
+	"BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC " +
+	"CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD " +
+	"GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS " +
+	"KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK " +
+	"MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB " +
+	"PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP " +
+	"SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH " +
+	"UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF XAG XAU XBA XBB XBC " +
+	"XBD XCD XCG XDR XOF XPD XPF XPT XSU XTS XUA XXX YER ZAR ZMW ZWG "
+
+// languageCodes lists the ISO 639-1 two-letter language codes, space-delimited.
+const languageCodes = " " +
+	"aa ab ae af ak am an ar as av ay az ba be bg bi bm bn bo br bs ca ce ch " +
+	"co cr cs cu cv cy da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy " +
+	"ga gd gl gn gu gv ha he hi ho hr ht hu hy hz ia id ie ig ii ik io is it " +
+	"iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb lg li ln lo " +
+	"lt lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny " +
+	"oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd se sg si sk sl " +
+	"sm sn so sq sr ss st su sv sw ta te tg th ti tk tl tn to tr ts tt tw ty " +
+	"ug uk ur uz ve vi vo wa wo xh yi yo za zh zu "
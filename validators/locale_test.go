@@ -0,0 +1,110 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestLocaleTables(t *testing.T) {
+	if n := len(strings.Fields(countryCodes)); n != 2*249 {
+		t.Errorf("countryCodes: expected 249 pairs, got %d codes", n)
+	}
+	if n := len(strings.Fields(languageCodes)); n != 183 {
+		t.Errorf("languageCodes: expected 183 codes, got %d", n)
+	}
+	seen := map[string]bool{}
+	for _, c := range strings.Fields(countryCodes + currencyCodes + languageCodes) {
+		if seen[c] && len(c) != 3 { // alpha-3 countries and currencies may coincide
+			t.Errorf("duplicate code %q", c)
+		}
+		seen[c] = true
+	}
+}
+
+func TestLocaleValidators(t *testing.T) {
+	tests := []struct {
+		v     interface{ Validate(string) error }
+		input string
+		ok    bool
+	}{
+		{&CountryValidator{}, "NL", true},
+		{&CountryValidator{}, "NLD", true},
+		{&CountryValidator{}, "nl", false},
+		{&CountryValidator{}, "XX", false},
+		{&CountryValidator{}, "AF AFG", false},
+		{&CountryValidator{Alpha: 2}, "DE", true},
+		{&CountryValidator{Alpha: 2}, "DEU", false},
+		{&CountryValidator{Alpha: 3}, "DEU", true},
+		{&CountryValidator{Alpha: 3}, "DE", false},
+		{&CountryValidator{Alpha: 4}, "DE", false},
+		{&CurrencyValidator{}, "EUR", true},
+		{&CurrencyValidator{}, "XAU", true},
+		{&CurrencyValidator{}, "eur", false},
+		{&CurrencyValidator{}, "ABC", false},
+		{&CurrencyValidator{}, "D A", false},
+		{&LanguageValidator{}, "en", true},
+		{&LanguageValidator{}, "pt-BR", true},
+		{&LanguageValidator{}, "EN-us", true},
+		{&LanguageValidator{}, "zh-Hant-TW", true},
+		{&LanguageValidator{}, "sr-Latn-RS", true},
+		{&LanguageValidator{}, "es-419", true},
+		{&LanguageValidator{}, "de-CH-1996", true},
+		{&LanguageValidator{}, "sl-rozaj-biske", true},
+		{&LanguageValidator{}, "zh-yue-HK", true},
+		{&LanguageValidator{}, "fil-PH", true},
+		{&LanguageValidator{}, "en-US-u-ca-gregory", true},
+		{&LanguageValidator{}, "de-x-private", true},
+		{&LanguageValidator{}, "x-klingon", true},
+		{&LanguageValidator{}, "", false},
+		{&LanguageValidator{}, "qq", false},
+		{&LanguageValidator{}, "en-JJ", false},
+		{&LanguageValidator{}, "en-QQ", true}, // private use
+		{&LanguageValidator{}, "en_US", false},
+		{&LanguageValidator{}, "en-", false},
+		{&LanguageValidator{}, "en-u", false},
+		{&LanguageValidator{}, "en-a-bbb-a-ccc", false},
+		{&LanguageValidator{}, "en-US-toolongsubtag", false},
+		{&LanguageValidator{}, "e", false},
+		{&LanguageValidator{}, "i-klingon", false},
+		{&TimezoneValidator{}, "Europe/Amsterdam", true},
+		{&TimezoneValidator{}, "America/Argentina/Buenos_Aires", true},
+		{&TimezoneValidator{}, "UTC", true},
+		{&TimezoneValidator{}, "", false},
+		{&TimezoneValidator{}, "Local", false},
+		{&TimezoneValidator{}, "Mars/Olympus_Mons", false},
+		{&TimezoneValidator{}, "../etc/passwd", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestLocaleTags(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &LatitudeValidator{})
+	valex.MustRegisterDirectiveTo(reg, &CountryValidator{})
+	valex.MustRegisterDirectiveTo(reg, &CurrencyValidator{})
+	valex.MustRegisterDirectiveTo(reg, &TimezoneValidator{})
+
+	type account struct {
+		Lat      float64 `val:"latitude"`
+		Country  string  `val:"country,alpha=2"`
+		Currency string  `val:"currency"`
+		TZ       string  `val:"timezone"`
+	}
+	if err := reg.ValidateStruct(&account{52.37, "NL", "EUR", "Europe/Amsterdam"}); err != nil {
+		t.Fatalf("expected valid account, got %v", err)
+	}
+	if err := reg.ValidateStruct(&account{52.37, "NLD", "EUR", "Europe/Amsterdam"}); err == nil {
+		t.Fatal("expected an alpha-3 code to fail alpha=2")
+	}
+	if err := reg.ValidateStruct(&account{95, "NL", "EUR", "Europe/Amsterdam"}); err == nil {
+		t.Fatal("expected latitude 95 to fail")
+	}
+}
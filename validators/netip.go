@@ -0,0 +1,398 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// AddrValidator validates that a netip.Addr is valid (not the zero Addr). If
+// Family is 4 or 6, the address must be of that family; an IPv4-mapped IPv6
+// address counts as IPv4.
+type AddrValidator struct {
+	Family int `param:"family,required=false"`
+}
+
+// Validate checks whether the value is a valid address of the configured family.
+func (v *AddrValidator) Validate(val netip.Addr) error {
+	if !val.IsValid() {
+		return errors.New("ip address is not set")
+	}
+	return checkAddrFamily(val, v.Family)
+}
+
+// Name returns the directive identifier.
+func (v *AddrValidator) Name() string {
+	return "addr"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *AddrValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *AddrValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// NonZeroAddrValidator validates that a netip.Addr is set and not the
+// unspecified address (0.0.0.0 or ::).
+type NonZeroAddrValidator struct{}
+
+// Validate checks whether the value is non-zero.
+func (v *NonZeroAddrValidator) Validate(val netip.Addr) error {
+	if !val.IsValid() || val.IsUnspecified() {
+		return fmt.Errorf("ip is zero")
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *NonZeroAddrValidator) Name() string {
+	return "!zeroaddr"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *NonZeroAddrValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *NonZeroAddrValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// AddrRangeValidator validates that a netip.Addr is within an inclusive range.
+type AddrRangeValidator struct {
+	Start netip.Addr `param:"start"`
+	End   netip.Addr `param:"end"`
+}
+
+// Validate checks whether the value is within the configured range.
+func (v *AddrRangeValidator) Validate(val netip.Addr) error {
+	start, end, value := v.Start.Unmap(), v.End.Unmap(), val.Unmap()
+	if start.BitLen() != end.BitLen() {
+		return errors.New(`"start" and "end" must be same IP family`)
+	}
+	if start.Compare(end) > 0 {
+		return errors.New(`"start" must be less than or equal to "end"`)
+	}
+	if !value.IsValid() {
+		return fmt.Errorf("invalid IP")
+	}
+	if value.BitLen() != start.BitLen() {
+		return errors.New("ip family mismatch")
+	}
+	if value.Compare(start) < 0 || value.Compare(end) > 0 {
+		return fmt.Errorf("ip %v is not in range [%v, %v]", val, v.Start, v.End)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *AddrRangeValidator) Name() string {
+	return "rangeaddr"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *AddrRangeValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the start/end parameters.
+func (v *AddrRangeValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	if fieldValue.Type() != reflect.TypeOf(netip.Addr{}) {
+		return tagex.NewConversionError(field, raw, "netip.Addr")
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid ip %q", raw)
+	}
+	fieldValue.Set(reflect.ValueOf(addr))
+	return nil
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *AddrRangeValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// PrivateAddrValidator validates that a netip.Addr is a private address:
+// RFC 1918 IPv4 (10/8, 172.16/12, 192.168/16) or an RFC 4193 IPv6 unique local
+// address (fc00::/7).
+type PrivateAddrValidator struct{}
+
+// Validate checks whether the value is a private address.
+func (v *PrivateAddrValidator) Validate(val netip.Addr) error {
+	if !val.Unmap().IsPrivate() {
+		return fmt.Errorf("ip %v is not a private address", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *PrivateAddrValidator) Name() string {
+	return "private"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *PrivateAddrValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *PrivateAddrValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// PublicAddrValidator validates that a netip.Addr is a globally routable
+// unicast address: not private, loopback, link-local, multicast, unspecified,
+// or in another special-purpose range. It is the check behind the url
+// directive's ssrfsafe mode.
+type PublicAddrValidator struct{}
+
+// Validate checks whether the value is a public address.
+func (v *PublicAddrValidator) Validate(val netip.Addr) error {
+	if !isPublicAddr(val) {
+		return fmt.Errorf("ip %v is not a public address", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *PublicAddrValidator) Name() string {
+	return "public"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *PublicAddrValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *PublicAddrValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// LoopbackAddrValidator validates that a netip.Addr is a loopback address
+// (127.0.0.0/8 or ::1).
+type LoopbackAddrValidator struct{}
+
+// Validate checks whether the value is a loopback address.
+func (v *LoopbackAddrValidator) Validate(val netip.Addr) error {
+	if !val.Unmap().IsLoopback() {
+		return fmt.Errorf("ip %v is not a loopback address", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *LoopbackAddrValidator) Name() string {
+	return "loopback"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *LoopbackAddrValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *LoopbackAddrValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// MulticastAddrValidator validates that a netip.Addr is a multicast address.
+type MulticastAddrValidator struct{}
+
+// Validate checks whether the value is a multicast address.
+func (v *MulticastAddrValidator) Validate(val netip.Addr) error {
+	if !val.Unmap().IsMulticast() {
+		return fmt.Errorf("ip %v is not a multicast address", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *MulticastAddrValidator) Name() string {
+	return "multicast"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *MulticastAddrValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *MulticastAddrValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// InPrefixValidator validates that a netip.Addr falls within at least one of
+// the configured prefixes (pipe-separated; quote the value so the list reads
+// as one parameter):
+//
+//	val:"inprefix,prefix='10.0.0.0/8|192.168.0.0/16'"
+type InPrefixValidator struct {
+	Prefixes []netip.Prefix `param:"prefix"`
+}
+
+// Validate checks whether the value is within one of the configured prefixes.
+func (v *InPrefixValidator) Validate(val netip.Addr) error {
+	if len(v.Prefixes) == 0 {
+		return errors.New(`value of parameter "prefix" cannot be empty`)
+	}
+	addr := val.Unmap()
+	for _, p := range v.Prefixes {
+		if p.Contains(addr) {
+			return nil
+		}
+	}
+	return fmt.Errorf("ip %v is not in an allowed prefix", val)
+}
+
+// Name returns the directive identifier.
+func (v *InPrefixValidator) Name() string {
+	return "inprefix"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *InPrefixValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the prefix parameter.
+func (v *InPrefixValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return parsePipeList(v, field, fieldValue, raw, func(item string) (netip.Prefix, error) {
+		p, err := netip.ParsePrefix(item)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid prefix %q", item)
+		}
+		return p.Masked(), nil
+	})
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *InPrefixValidator) Handle(val netip.Addr) (netip.Addr, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// NetPrefixValidator validates that a netip.Prefix is valid. Family restricts it
+// to IPv4 (4) or IPv6 (6); MinBits and MaxBits bound the prefix length; and
+// Masked requires the canonical form with no host bits set ("10.0.0.0/8", not
+// "10.1.2.3/8").
+type NetPrefixValidator struct {
+	Family  int  `param:"family,required=false"`
+	MinBits int  `param:"minbits,required=false"`
+	MaxBits int  `param:"maxbits,required=false"`
+	Masked  bool `param:"masked,required=false"`
+}
+
+// Validate checks whether the value is a valid prefix within the configured bounds.
+func (v *NetPrefixValidator) Validate(val netip.Prefix) error {
+	if !val.IsValid() {
+		return errors.New("prefix is not set")
+	}
+	if err := checkAddrFamily(val.Addr(), v.Family); err != nil {
+		return err
+	}
+	if v.Masked && val.Masked() != val {
+		return fmt.Errorf("prefix %v has host bits set, expected %v", val, val.Masked())
+	}
+	return checkPrefixBits(val.Bits(), v.MinBits, v.MaxBits)
+}
+
+// Name returns the directive identifier.
+func (v *NetPrefixValidator) Name() string {
+	return "netprefix"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *NetPrefixValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *NetPrefixValidator) Handle(val netip.Prefix) (netip.Prefix, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// AddrPortValidator validates that a netip.AddrPort has a valid address and a
+// non-zero port. Family restricts the address as in AddrValidator.
+type AddrPortValidator struct {
+	Family int `param:"family,required=false"`
+}
+
+// Validate checks whether the value is a valid address and port.
+func (v *AddrPortValidator) Validate(val netip.AddrPort) error {
+	if !val.Addr().IsValid() {
+		return errors.New("ip address is not set")
+	}
+	if val.Port() == 0 {
+		return fmt.Errorf("port of %v is zero", val)
+	}
+	return checkAddrFamily(val.Addr(), v.Family)
+}
+
+// Name returns the directive identifier.
+func (v *AddrPortValidator) Name() string {
+	return "addrport"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *AddrPortValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *AddrPortValidator) Handle(val netip.AddrPort) (netip.AddrPort, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+func checkAddrFamily(addr netip.Addr, family int) error {
+	switch family {
+	case 0:
+		return nil
+	case 4:
+		if !addr.Unmap().Is4() {
+			return fmt.Errorf("ip %v is not an IPv4 address", addr)
+		}
+		return nil
+	case 6:
+		if !addr.Is6() || addr.Is4In6() {
+			return fmt.Errorf("ip %v is not an IPv6 address", addr)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid IP family %d, expected 4 or 6", family)
+}
+
+func checkPrefixBits(bits, minBits, maxBits int) error {
+	if minBits < 0 || maxBits < 0 {
+		return errors.New(`"minbits" and "maxbits" cannot be negative`)
+	}
+	if maxBits > 0 && minBits > maxBits {
+		return errors.New(`"minbits" cannot exceed "maxbits"`)
+	}
+	if bits < minBits {
+		return fmt.Errorf("prefix length /%d is shorter than /%d", bits, minBits)
+	}
+	if maxBits > 0 && bits > maxBits {
+		return fmt.Errorf("prefix length /%d is longer than /%d", bits, maxBits)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package validators
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestAddrClassValidators(t *testing.T) {
+	tests := []struct {
+		v     interface{ Validate(netip.Addr) error }
+		input string
+		ok    bool
+	}{
+		{&PrivateAddrValidator{}, "10.1.2.3", true},
+		{&PrivateAddrValidator{}, "192.168.0.1", true},
+		{&PrivateAddrValidator{}, "fd00::1", true},
+		{&PrivateAddrValidator{}, "::ffff:172.16.0.1", true},
+		{&PrivateAddrValidator{}, "8.8.8.8", false},
+		{&PublicAddrValidator{}, "8.8.8.8", true},
+		{&PublicAddrValidator{}, "2606:4700::1111", true},
+		{&PublicAddrValidator{}, "10.0.0.1", false},
+		{&PublicAddrValidator{}, "169.254.169.254", false},
+		{&PublicAddrValidator{}, "100.64.0.1", false},
+		{&LoopbackAddrValidator{}, "127.0.0.53", true},
+		{&LoopbackAddrValidator{}, "::1", true},
+		{&LoopbackAddrValidator{}, "10.0.0.1", false},
+		{&MulticastAddrValidator{}, "224.0.0.1", true},
+		{&MulticastAddrValidator{}, "ff02::1", true},
+		{&MulticastAddrValidator{}, "10.0.0.1", false},
+		{&NonZeroAddrValidator{}, "10.0.0.1", true},
+		{&NonZeroAddrValidator{}, "0.0.0.0", false},
+		{&NonZeroAddrValidator{}, "::", false},
+		{&AddrValidator{Family: 4}, "10.0.0.1", true},
+		{&AddrValidator{Family: 4}, "::ffff:10.0.0.1", true},
+		{&AddrValidator{Family: 4}, "fd00::1", false},
+		{&AddrValidator{Family: 6}, "fd00::1", true},
+		{&AddrValidator{Family: 6}, "::ffff:10.0.0.1", false},
+		{&AddrValidator{Family: 5}, "10.0.0.1", false},
+		{&AddrRangeValidator{Start: netip.MustParseAddr("10.0.0.1"), End: netip.MustParseAddr("10.0.0.10")}, "10.0.0.5", true},
+		{&AddrRangeValidator{Start: netip.MustParseAddr("10.0.0.1"), End: netip.MustParseAddr("10.0.0.10")}, "10.0.0.11", false},
+		{&AddrRangeValidator{Start: netip.MustParseAddr("10.0.0.1"), End: netip.MustParseAddr("10.0.0.10")}, "fd00::1", false},
+		{&InPrefixValidator{Prefixes: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}, "10.9.9.9", true},
+		{&InPrefixValidator{Prefixes: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}, "::ffff:10.9.9.9", true},
+		{&InPrefixValidator{Prefixes: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}, "11.0.0.1", false},
+		{&InPrefixValidator{}, "10.0.0.1", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(netip.MustParseAddr(tc.input))
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+	if err := (&AddrValidator{}).Validate(netip.Addr{}); err == nil {
+		t.Error("expected zero netip.Addr to be rejected")
+	}
+}
+
+func TestNetPrefixValidator(t *testing.T) {
+	tests := []struct {
+		v     *NetPrefixValidator
+		input string
+		ok    bool
+	}{
+		{&NetPrefixValidator{}, "10.0.0.0/8", true},
+		{&NetPrefixValidator{Family: 6}, "10.0.0.0/8", false},
+		{&NetPrefixValidator{MinBits: 16}, "10.0.0.0/8", false},
+		{&NetPrefixValidator{MinBits: 16}, "10.1.0.0/16", true},
+		{&NetPrefixValidator{MaxBits: 24}, "10.1.2.0/28", false},
+		{&NetPrefixValidator{Masked: true}, "10.1.2.3/8", false},
+		{&NetPrefixValidator{Masked: true}, "2001:db8::/32", true},
+		{&NetPrefixValidator{MinBits: 24, MaxBits: 16}, "10.1.2.0/24", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(netip.MustParsePrefix(tc.input))
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%+v(%q): expected ok=%v, got ok=%v (err: %v)", *tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestAddrPortValidator(t *testing.T) {
+	tests := []struct {
+		v     *AddrPortValidator
+		input string
+		ok    bool
+	}{
+		{&AddrPortValidator{}, "10.0.0.1:8080", true},
+		{&AddrPortValidator{}, "10.0.0.1:0", false},
+		{&AddrPortValidator{Family: 6}, "[::1]:443", true},
+		{&AddrPortValidator{Family: 4}, "[::1]:443", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(netip.MustParseAddrPort(tc.input))
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%+v(%q): expected ok=%v, got ok=%v (err: %v)", *tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestIPCIDRValidatorBits(t *testing.T) {
+	v := &IPCIDRValidator{MinBits: 16, MaxBits: 28}
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"10.1.0.0/16", true},
+		{"10.0.0.0/8", false},
+		{"10.1.2.0/29", false},
+		{"2001:db8::/24", true},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestNetipTags(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &InPrefixValidator{})
+	valex.MustRegisterDirectiveTo(reg, &PublicAddrValidator{})
+	valex.MustRegisterDirectiveTo(reg, &NetPrefixValidator{})
+	valex.MustRegisterDirectiveTo(reg, &AddrPortValidator{})
+	valex.MustRegisterDirectiveTo(reg, &AddrRangeValidator{})
+
+	type server struct {
+		Internal netip.Addr     `val:"inprefix,prefix='10.0.0.0/8|192.168.0.0/16'"`
+		External netip.Addr     `val:"public"`
+		Subnet   netip.Prefix   `val:"netprefix,family=4,minbits=16,masked=true"`
+		Listen   netip.AddrPort `val:"addrport"`
+		Gateway  netip.Addr     `val:"rangeaddr,start=10.0.0.1,end=10.0.0.10"`
+	}
+	s := &server{
+		Internal: netip.MustParseAddr("192.168.1.20"),
+		External: netip.MustParseAddr("8.8.8.8"),
+		Subnet:   netip.MustParsePrefix("10.20.0.0/16"),
+		Listen:   netip.MustParseAddrPort("0.0.0.0:8080"),
+		Gateway:  netip.MustParseAddr("10.0.0.1"),
+	}
+	if err := reg.ValidateStruct(s); err != nil {
+		t.Fatalf("expected valid server, got %v", err)
+	}
+
+	bad := *s
+	bad.Internal = netip.MustParseAddr("172.16.0.1")
+	if err := reg.ValidateStruct(&bad); err == nil {
+		t.Fatal("expected 172.16.0.1 to be outside the allowed prefixes")
+	}
+
+	type badPrefix struct {
+		Internal netip.Addr `val:"inprefix,prefix=10.0.0.0"`
+	}
+	if err := reg.ValidateStruct(&badPrefix{netip.MustParseAddr("10.0.0.1")}); err == nil {
+		t.Fatal("expected invalid prefix parameter to fail")
+	}
+}
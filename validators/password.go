@@ -0,0 +1,267 @@
+package validators
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// PasswordValidator validates a password against a configurable policy. Every
+// parameter is optional; a zero value disables that rule.
+//
+//	val:"password,min=12,upper=1,digit=1,symbol=1"
+//	val:"password,min=8,entropy=60"
+//
+// Min and Max count characters (runes), not bytes. Upper, Lower, Digit, and
+// Symbol set the minimum number of characters of each class; a symbol is any
+// character that is not a letter, digit, or control character. Entropy sets a
+// minimum strength in bits, estimated as length × log2(pool), where the pool is
+// the combined size of the character classes the password draws from — a coarse
+// guard against short or single-class passwords, not a cracking-time model.
+//
+// Denylist, when set, rejects common or breached passwords (case-insensitively).
+// It is not a tag parameter: set it on the directive you register, and every
+// field using the "password" tag shares it:
+//
+//	common, err := validators.LoadDenylist(file)
+//	valex.MustRegisterDirective(&validators.PasswordValidator{Denylist: common})
+//
+// Validate reports every unmet rule at once as a *PasswordError, so a form can
+// render the whole list instead of one complaint per submit.
+type PasswordValidator struct {
+	Min      int     `param:"min,required=false"`
+	Max      int     `param:"max,required=false"`
+	Upper    int     `param:"upper,required=false"`
+	Lower    int     `param:"lower,required=false"`
+	Digit    int     `param:"digit,required=false"`
+	Symbol   int     `param:"symbol,required=false"`
+	Entropy  float64 `param:"entropy,required=false"`
+	Denylist *Denylist
+}
+
+// PasswordRuleError is a single password rule the value failed. Rule names the
+// tag parameter behind it ("min", "max", "upper", "lower", "digit", "symbol",
+// "entropy") or "denylist".
+type PasswordRuleError struct {
+	Rule string
+	Msg  string
+}
+
+func (e *PasswordRuleError) Error() string {
+	return e.Msg
+}
+
+// PasswordError reports every rule a password failed, in a fixed order. Use
+// errors.As to reach it and range over Rules to render each one; Unwrap exposes
+// them to errors.Is / errors.As as well.
+type PasswordError struct {
+	Rules []*PasswordRuleError
+}
+
+func (e *PasswordError) Error() string {
+	msgs := make([]string, len(e.Rules))
+	for i, r := range e.Rules {
+		msgs[i] = r.Msg
+	}
+	return "password does not meet policy: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual rule errors.
+func (e *PasswordError) Unwrap() []error {
+	errs := make([]error, len(e.Rules))
+	for i, r := range e.Rules {
+		errs[i] = r
+	}
+	return errs
+}
+
+// Validate checks the value against every configured rule.
+func (v *PasswordValidator) Validate(val string) error {
+	if err := v.checkPolicy(); err != nil {
+		return err
+	}
+
+	var upper, lower, digit, symbol, length int
+	for _, r := range val {
+		length++
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsDigit(r):
+			digit++
+		case unicode.IsLetter(r), unicode.IsControl(r):
+		default:
+			symbol++
+		}
+	}
+
+	var rules []*PasswordRuleError
+	fail := func(rule, format string, args ...any) {
+		rules = append(rules, &PasswordRuleError{Rule: rule, Msg: fmt.Sprintf(format, args...)})
+	}
+	if v.Min > 0 && length < v.Min {
+		fail("min", "must be at least %d characters", v.Min)
+	}
+	if v.Max > 0 && length > v.Max {
+		fail("max", "must be at most %d characters", v.Max)
+	}
+	if upper < v.Upper {
+		fail("upper", "must contain at least %d uppercase %s", v.Upper, plural(v.Upper, "letter"))
+	}
+	if lower < v.Lower {
+		fail("lower", "must contain at least %d lowercase %s", v.Lower, plural(v.Lower, "letter"))
+	}
+	if digit < v.Digit {
+		fail("digit", "must contain at least %d %s", v.Digit, plural(v.Digit, "digit"))
+	}
+	if symbol < v.Symbol {
+		fail("symbol", "must contain at least %d %s", v.Symbol, plural(v.Symbol, "symbol"))
+	}
+	if v.Entropy > 0 && PasswordEntropy(val) < v.Entropy {
+		fail("entropy", "is too weak (estimated strength below %g bits)", v.Entropy)
+	}
+	if v.Denylist.Contains(val) {
+		fail("denylist", "is too common")
+	}
+
+	if len(rules) > 0 {
+		return &PasswordError{Rules: rules}
+	}
+	return nil
+}
+
+// checkPolicy rejects a policy that cannot be satisfied or checks nothing.
+func (v *PasswordValidator) checkPolicy() error {
+	if v.Min < 0 || v.Max < 0 || v.Upper < 0 || v.Lower < 0 || v.Digit < 0 || v.Symbol < 0 || v.Entropy < 0 {
+		return errors.New("password policy values cannot be negative")
+	}
+	if v.Max > 0 && v.Min > v.Max {
+		return errors.New(`"min" cannot exceed "max"`)
+	}
+	if v.Min == 0 && v.Max == 0 && v.Upper == 0 && v.Lower == 0 && v.Digit == 0 && v.Symbol == 0 && v.Entropy == 0 && v.Denylist == nil {
+		return errors.New("password policy has no rules")
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *PasswordValidator) Name() string {
+	return "password"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *PasswordValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *PasswordValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// PasswordEntropy estimates the strength of a password in bits as
+// length × log2(pool), where pool sums the sizes of the character classes
+// present: 26 lowercase, 26 uppercase, 10 digits, 33 ASCII symbols, and 100 for
+// any other character. It is the estimate behind the "entropy" rule.
+func PasswordEntropy(val string) float64 {
+	var hasLower, hasUpper, hasDigit, hasSymbol, hasOther bool
+	for _, r := range val {
+		switch {
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r < utf8.RuneSelf && unicode.IsPrint(r):
+			hasSymbol = true
+		default:
+			hasOther = true
+		}
+	}
+	pool := 0
+	for _, c := range []struct {
+		has  bool
+		size int
+	}{{hasLower, 26}, {hasUpper, 26}, {hasDigit, 10}, {hasSymbol, 33}, {hasOther, 100}} {
+		if c.has {
+			pool += c.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(utf8.RuneCountInString(val)) * math.Log2(float64(pool))
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// Denylist is a case-insensitive set of forbidden values, such as a
+// common-passwords file. A nil *Denylist contains nothing.
+type Denylist struct {
+	set map[string]struct{}
+}
+
+// NewDenylist returns a Denylist holding values.
+func NewDenylist(values ...string) *Denylist {
+	d := &Denylist{set: make(map[string]struct{}, len(values))}
+	for _, val := range values {
+		d.add(val)
+	}
+	return d
+}
+
+// LoadDenylist reads a Denylist from r, one entry per line. Surrounding
+// whitespace is trimmed, and blank lines and lines starting with '#' are
+// skipped.
+func LoadDenylist(r io.Reader) (*Denylist, error) {
+	d := NewDenylist()
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		d.add(line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading denylist: %w", err)
+	}
+	return d, nil
+}
+
+func (d *Denylist) add(val string) {
+	d.set[strings.ToLower(val)] = struct{}{}
+}
+
+// Contains reports whether val is on the list, ignoring case.
+func (d *Denylist) Contains(val string) bool {
+	if d == nil {
+		return false
+	}
+	_, ok := d.set[strings.ToLower(val)]
+	return ok
+}
+
+// Len returns the number of entries on the list.
+func (d *Denylist) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.set)
+}
@@ -0,0 +1,123 @@
+package validators
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestPasswordValidator(t *testing.T) {
+	v := &PasswordValidator{Min: 12, Upper: 1, Digit: 1, Symbol: 1}
+	tests := []struct {
+		input string
+		ok    bool
+		rules []string
+	}{
+		{"Correct-Horse-9", true, nil},
+		{"short", false, []string{"min", "upper", "digit", "symbol"}},
+		{"alllowercaseletters", false, []string{"upper", "digit", "symbol"}},
+		{"Zoë-Ünïcödé-7", true, nil},
+		{"NoSymbolsHere12", false, []string{"symbol"}},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+			continue
+		}
+		if ok {
+			continue
+		}
+		var pe *PasswordError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%q: expected *PasswordError, got %T", tc.input, err)
+		}
+		var got []string
+		for _, r := range pe.Rules {
+			got = append(got, r.Rule)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.rules, ",") {
+			t.Errorf("%q: expected unmet rules %v, got %v", tc.input, tc.rules, got)
+		}
+	}
+}
+
+func TestPasswordValidatorEntropy(t *testing.T) {
+	v := &PasswordValidator{Entropy: 60}
+	if err := v.Validate("aaaaaaaa"); err == nil {
+		t.Error("expected 8 lowercase letters to fall below 60 bits")
+	}
+	if err := v.Validate("tr0ub4dor&3-Plus-More"); err != nil {
+		t.Errorf("expected long mixed password to pass, got %v", err)
+	}
+	if got := PasswordEntropy(""); got != 0 {
+		t.Errorf("PasswordEntropy(\"\") = %g, want 0", got)
+	}
+}
+
+func TestPasswordValidatorDenylist(t *testing.T) {
+	common, err := LoadDenylist(strings.NewReader("# common passwords\npassword1\n\n  Qwerty123  \n"))
+	if err != nil {
+		t.Fatalf("LoadDenylist: %v", err)
+	}
+	if common.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", common.Len())
+	}
+
+	v := &PasswordValidator{Min: 8, Denylist: common}
+	err = v.Validate("QWERTY123")
+	var rule *PasswordRuleError
+	if !errors.As(err, &rule) || rule.Rule != "denylist" {
+		t.Fatalf("expected denylist rule error, got %v", err)
+	}
+	if err := v.Validate("something-rare"); err != nil {
+		t.Fatalf("expected pass, got %v", err)
+	}
+}
+
+func TestPasswordValidatorPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		v    *PasswordValidator
+	}{
+		{"no rules", &PasswordValidator{}},
+		{"negative", &PasswordValidator{Min: -1}},
+		{"min exceeds max", &PasswordValidator{Min: 10, Max: 5}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.v.Validate("whatever")
+			var pe *PasswordError
+			if err == nil || errors.As(err, &pe) {
+				t.Fatalf("expected a policy error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPasswordTag(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &PasswordValidator{Denylist: NewDenylist("Password-123")})
+
+	type Signup struct {
+		Password string `val:"password,min=12,upper=1,digit=1,symbol=1"`
+	}
+	if err := reg.ValidateStruct(&Signup{Password: "Correct-Horse-9"}); err != nil {
+		t.Fatalf("expected pass, got %v", err)
+	}
+
+	err := reg.ValidateStruct(&Signup{Password: "abc"})
+	var pe *PasswordError
+	if !errors.As(err, &pe) || len(pe.Rules) != 4 {
+		t.Fatalf("expected 4 unmet rules through the tag, got %v", err)
+	}
+
+	// The denylist set on the registered template reaches every field.
+	err = reg.ValidateStruct(&Signup{Password: "password-123"})
+	if !errors.As(err, &pe) || pe.Rules[len(pe.Rules)-1].Rule != "denylist" {
+		t.Fatalf("expected denylist failure, got %v", err)
+	}
+}
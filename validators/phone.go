@@ -0,0 +1,277 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+)
+
+// E164Validator validates a phone number in strict E.164 form: a '+', a
+// country code, and at most 15 digits in total, with no separators
+// ("+31201234567").
+type E164Validator struct{}
+
+// Validate checks whether the value is an E.164 number.
+func (v *E164Validator) Validate(val string) error {
+	if !isE164(val) {
+		return fmt.Errorf("value %q is not an E.164 phone number", val)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *E164Validator) Name() string {
+	return "e164"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *E164Validator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *E164Validator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// PhoneValidator validates a phone number as people type it, allowing spaces,
+// hyphens, dots, and parentheses between digits.
+//
+//	val:"phone"                          // international: "+31 20 123 4567"
+//	val:"phone,region=NL"                // also national: "020 123 4567"
+//	val:"phone,region=NL,normalize=true" // rewrites the field to "+31201234567"
+//
+// Without Region the number must be international (leading '+'). With Region
+// (an ISO 3166-1 alpha-2 code, see PhoneRegions) a national number is read in
+// that region, and an international one must carry the region's country code.
+// Either way, when the country is in the embedded numbering metadata the
+// national number's length and leading digit are checked against it; other
+// country codes are held to E.164's 15-digit limit only.
+//
+// The metadata is deliberately small — lengths and leading digits, not full
+// numbering plans — so it catches typos and wrong-country input, not numbers
+// that are well-formed but unassigned.
+//
+// With Normalize set the directive runs in tagex.MutMode and writes the number
+// back in E.164 form, so stored data is canonical.
+type PhoneValidator struct {
+	Region    string `param:"region,required=false"`
+	Normalize bool   `param:"normalize,required=false"`
+}
+
+// Validate checks whether the value is a valid phone number.
+func (v *PhoneValidator) Validate(val string) error {
+	_, err := NormalizePhone(val, v.Region)
+	return err
+}
+
+// Name returns the directive identifier.
+func (v *PhoneValidator) Name() string {
+	return "phone"
+}
+
+// Mode returns the directive evaluation mode: tagex.MutMode when Normalize is
+// set, tagex.EvalMode otherwise.
+func (v *PhoneValidator) Mode() tagex.DirectiveMode {
+	if v.Normalize {
+		return tagex.MutMode
+	}
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it in E.164 form when Normalize is
+// set, unchanged otherwise.
+func (v *PhoneValidator) Handle(val string) (string, error) {
+	e164, err := NormalizePhone(val, v.Region)
+	if err != nil || !v.Normalize {
+		return val, err
+	}
+	return e164, nil
+}
+
+// phoneRegion is the numbering metadata for one region: the country calling
+// code, the national trunk prefix dropped in international form, the valid
+// lengths of the national significant number, and the digits it may start with
+// (empty for any).
+type phoneRegion struct {
+	code    string
+	trunk   string
+	lengths []int
+	leading string
+}
+
+func (m *phoneRegion) valid(national string) bool {
+	if !slices.Contains(m.lengths, len(national)) {
+		return false
+	}
+	return m.leading == "" || strings.IndexByte(m.leading, national[0]) >= 0
+}
+
+var phoneRegions = map[string]phoneRegion{
+	"AE": {"971", "0", []int{8, 9}, "234679"},
+	"AT": {"43", "0", []int{4, 5, 6, 7, 8, 9, 10, 11, 12, 13}, "12345678"},
+	"AU": {"61", "0", []int{9}, "2345789"},
+	"BE": {"32", "0", []int{8, 9}, "123456789"},
+	"BR": {"55", "0", []int{10, 11}, "123456789"},
+	"CA": {"1", "1", []int{10}, "23456789"},
+	"CH": {"41", "0", []int{9}, "2345789"},
+	"CN": {"86", "0", []int{10, 11}, "123456789"},
+	"DE": {"49", "0", []int{6, 7, 8, 9, 10, 11, 12, 13}, "123456789"},
+	"DK": {"45", "", []int{8}, "2345678"},
+	"ES": {"34", "", []int{9}, "56789"},
+	"FI": {"358", "0", []int{5, 6, 7, 8, 9, 10, 11, 12}, "123456789"},
+	"FR": {"33", "0", []int{9}, "123456789"},
+	"GB": {"44", "0", []int{9, 10}, "12378"},
+	"HK": {"852", "", []int{8}, "234569"},
+	"IE": {"353", "0", []int{7, 8, 9}, "1245679"},
+	"IL": {"972", "0", []int{8, 9}, "234589"},
+	"IN": {"91", "0", []int{10}, "123456789"},
+	"IT": {"39", "", []int{6, 7, 8, 9, 10, 11}, "03"},
+	"JP": {"81", "0", []int{9, 10}, "123456789"},
+	"KR": {"82", "0", []int{8, 9, 10}, "123456789"},
+	"MX": {"52", "", []int{10}, "123456789"},
+	"NL": {"31", "0", []int{9}, "123456789"},
+	"NO": {"47", "", []int{8}, "23456789"},
+	"NZ": {"64", "0", []int{8, 9, 10}, "2345679"},
+	"PL": {"48", "", []int{9}, "123456789"},
+	"PT": {"351", "", []int{9}, "29"},
+	"RU": {"7", "8", []int{10}, "3489"},
+	"SE": {"46", "0", []int{7, 8, 9}, "123456789"},
+	"SG": {"65", "", []int{8}, "3689"},
+	"US": {"1", "1", []int{10}, "23456789"},
+	"ZA": {"27", "0", []int{9}, "12345678"},
+}
+
+// PhoneRegions returns the region codes with embedded numbering metadata,
+// sorted.
+func PhoneRegions() []string {
+	regions := make([]string, 0, len(phoneRegions))
+	for r := range phoneRegions {
+		regions = append(regions, r)
+	}
+	slices.Sort(regions)
+	return regions
+}
+
+// NormalizePhone validates number and returns it in E.164 form. region is an
+// ISO 3166-1 alpha-2 code used to read national numbers; pass "" to accept
+// international numbers only. The rules are those of PhoneValidator.
+func NormalizePhone(number, region string) (string, error) {
+	var meta *phoneRegion
+	if region != "" {
+		m, ok := phoneRegions[strings.ToUpper(region)]
+		if !ok {
+			return "", fmt.Errorf("unsupported phone region %q", region)
+		}
+		meta = &m
+	}
+
+	digits, international, ok := phoneDigits(number)
+	if !ok {
+		return "", fmt.Errorf("value %q is not a valid phone number", number)
+	}
+
+	var code, national string
+	switch {
+	case international:
+		code, national = splitCountryCode(digits)
+		if meta != nil && code != meta.code {
+			return "", fmt.Errorf("phone number %q is not a %s number", number, strings.ToUpper(region))
+		}
+		if meta == nil {
+			if m, ok := phoneRegionByCode(code); ok {
+				meta = &m
+			}
+		}
+	case meta != nil:
+		code = meta.code
+		national = strings.TrimPrefix(digits, meta.trunk)
+	default:
+		return "", fmt.Errorf("phone number %q must start with + and a country code", number)
+	}
+
+	e164 := "+" + code + national
+	if !isE164(e164) {
+		return "", fmt.Errorf("value %q is not a valid phone number", number)
+	}
+	if meta != nil && !meta.valid(national) {
+		return "", fmt.Errorf("value %q is not a valid phone number for country code +%s", number, code)
+	}
+	return e164, nil
+}
+
+// phoneDigits strips the separators people type from a phone number and
+// reports whether it was written in international form.
+func phoneDigits(s string) (digits string, international, ok bool) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "+") {
+		international = true
+		s = s[1:]
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			b.WriteByte(c)
+		case c == ' ', c == '-', c == '.', c == '(', c == ')':
+		default:
+			return "", false, false
+		}
+	}
+	if b.Len() == 0 {
+		return "", false, false
+	}
+	return b.String(), international, true
+}
+
+// splitCountryCode splits international digits into a country calling code and
+// the rest. Codes in the metadata are matched by prefix; otherwise ITU codes
+// are assumed to be one digit for zones 1 and 7 and three digits elsewhere,
+// except for a short list of two-digit codes.
+func splitCountryCode(digits string) (code, rest string) {
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		if _, ok := phoneRegionByCode(digits[:n]); ok {
+			return digits[:n], digits[n:]
+		}
+	}
+	n := 3
+	switch {
+	case digits[0] == '1' || digits[0] == '7':
+		n = 1
+	case len(digits) >= 2 && twoDigitCountryCodes[digits[:2]]:
+		n = 2
+	}
+	if n > len(digits) {
+		n = len(digits)
+	}
+	return digits[:n], digits[n:]
+}
+
+var twoDigitCountryCodes = map[string]bool{
+	"20": true, "27": true, "30": true, "31": true, "32": true, "33": true, "34": true,
+	"36": true, "39": true, "40": true, "41": true, "43": true, "44": true, "45": true,
+	"46": true, "47": true, "48": true, "49": true, "51": true, "52": true, "53": true,
+	"54": true, "55": true, "56": true, "57": true, "58": true, "60": true, "61": true,
+	"62": true, "63": true, "64": true, "65": true, "66": true, "81": true, "82": true,
+	"84": true, "86": true, "90": true, "91": true, "92": true, "93": true, "94": true,
+	"95": true, "98": true,
+}
+
+func phoneRegionByCode(code string) (phoneRegion, bool) {
+	for _, m := range phoneRegions {
+		if m.code == code {
+			return m, true
+		}
+	}
+	return phoneRegion{}, false
+}
+
+func isE164(s string) bool {
+	if len(s) < 3 || len(s) > 16 || s[0] != '+' || s[1] == '0' {
+		return false
+	}
+	return isDigits(s[1:])
+}
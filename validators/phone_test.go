@@ -0,0 +1,100 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestE164Validator(t *testing.T) {
+	v := &E164Validator{}
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"+31201234567", true},
+		{"+12125550123", true},
+		{"+31 20 123 4567", false},
+		{"0201234567", false},
+		{"+0201234567", false},
+		{"+1234567890123456", false},
+		{"+1", false},
+	}
+	for _, tc := range tests {
+		err := v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", *v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		input  string
+		region string
+		want   string // "" means invalid
+	}{
+		{"+31 20 123 4567", "", "+31201234567"},
+		{"+1 (212) 555-0123", "", "+12125550123"},
+		{"020 123 4567", "NL", "+31201234567"},
+		{"06-12345678", "nl", "+31612345678"},
+		{"(212) 555-0123", "US", "+12125550123"},
+		{"1 212 555 0123", "US", "+12125550123"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"06 1234 5678", "IT", "+390612345678"},
+		{"+44 20 7946 0958", "NL", ""}, // wrong country for region
+		{"020 123 4567", "", ""},       // national without region
+		{"020 123 456", "NL", ""},      // too short for NL
+		{"(012) 555-0123", "US", ""},   // NANP area codes cannot start with 0 or 1
+		{"+31 20 123 4567 ext 2", "", ""},
+		{"+999 1234 5678", "", "+99912345678"}, // unknown code: E.164 length only
+		{"020 123 4567", "XX", ""},
+	}
+	for _, tc := range tests {
+		got, err := NormalizePhone(tc.input, tc.region)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("NormalizePhone(%q, %q) = %q, expected error", tc.input, tc.region, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("NormalizePhone(%q, %q) = %q, %v; want %q", tc.input, tc.region, got, err, tc.want)
+		}
+	}
+}
+
+func TestPhoneTag(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &PhoneValidator{})
+	valex.MustRegisterDirectiveTo(reg, &E164Validator{})
+
+	type signup struct {
+		Raw    string `val:"phone,region=NL"`
+		Stored string `val:"phone,region=NL,normalize=true"`
+		Strict string `val:"e164"`
+	}
+	s := &signup{Raw: "020 123 4567", Stored: "06 1234 5678", Strict: "+31201234567"}
+	if err := reg.ValidateStruct(s); err != nil {
+		t.Fatalf("expected valid signup, got %v", err)
+	}
+	if s.Raw != "020 123 4567" {
+		t.Errorf("expected Raw unchanged, got %q", s.Raw)
+	}
+	if s.Stored != "+31612345678" {
+		t.Errorf("expected Stored normalized to E.164, got %q", s.Stored)
+	}
+
+	bad := &signup{Raw: "+44 20 7946 0958", Stored: "+31612345678", Strict: "+31201234567"}
+	if err := reg.ValidateStruct(bad); err == nil {
+		t.Fatal("expected a GB number to fail region=NL")
+	}
+}
+
+func TestPhoneRegions(t *testing.T) {
+	regions := PhoneRegions()
+	if len(regions) == 0 || regions[0] != "AE" {
+		t.Fatalf("expected sorted regions starting with AE, got %v", regions)
+	}
+}
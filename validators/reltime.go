@@ -0,0 +1,413 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+)
+
+// The directives in this file compare against "now". Registered on a Registry,
+// they read it from the registry's clock (see valex.WithClock); used
+// programmatically, they fall back to time.Now.
+
+// FutureValidator validates that a time.Time is after now.
+type FutureValidator struct {
+	env *valex.Env
+}
+
+// SetEnv receives the registry's environment.
+func (v *FutureValidator) SetEnv(env *valex.Env) {
+	v.env = env
+}
+
+// Validate checks whether the value is in the future.
+func (v *FutureValidator) Validate(val time.Time) error {
+	if now := v.env.Now(); !val.After(now) {
+		return fmt.Errorf("time %s is not in the future", val.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *FutureValidator) Name() string {
+	return "future"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *FutureValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *FutureValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// PastValidator validates that a time.Time is before now.
+type PastValidator struct {
+	env *valex.Env
+}
+
+// SetEnv receives the registry's environment.
+func (v *PastValidator) SetEnv(env *valex.Env) {
+	v.env = env
+}
+
+// Validate checks whether the value is in the past.
+func (v *PastValidator) Validate(val time.Time) error {
+	if now := v.env.Now(); !val.Before(now) {
+		return fmt.Errorf("time %s is not in the past", val.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *PastValidator) Name() string {
+	return "past"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *PastValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *PastValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// WithinValidator validates that a time.Time is within D of now, in either
+// direction ("within,d=720h").
+type WithinValidator struct {
+	D   time.Duration `param:"d"`
+	env *valex.Env
+}
+
+// SetEnv receives the registry's environment.
+func (v *WithinValidator) SetEnv(env *valex.Env) {
+	v.env = env
+}
+
+// Validate checks whether the value is within D of now.
+func (v *WithinValidator) Validate(val time.Time) error {
+	if v.D <= 0 {
+		return errors.New(`"d" must be positive`)
+	}
+	now := v.env.Now()
+	if val.Before(now.Add(-v.D)) || val.After(now.Add(v.D)) {
+		return fmt.Errorf("time %s is not within %s of now", val.Format(time.RFC3339), v.D)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *WithinValidator) Name() string {
+	return "within"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *WithinValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the d parameter.
+func (v *WithinValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return setDurationParam(field, fieldValue, raw)
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *WithinValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// NotOlderThanValidator validates that a time.Time is no more than D before
+// now ("notolderthan,d=24h"). Times in the future pass; chain "past" to
+// exclude them.
+type NotOlderThanValidator struct {
+	D   time.Duration `param:"d"`
+	env *valex.Env
+}
+
+// SetEnv receives the registry's environment.
+func (v *NotOlderThanValidator) SetEnv(env *valex.Env) {
+	v.env = env
+}
+
+// Validate checks whether the value is no older than D.
+func (v *NotOlderThanValidator) Validate(val time.Time) error {
+	if v.D <= 0 {
+		return errors.New(`"d" must be positive`)
+	}
+	if val.Before(v.env.Now().Add(-v.D)) {
+		return fmt.Errorf("time %s is older than %s", val.Format(time.RFC3339), v.D)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *NotOlderThanValidator) Name() string {
+	return "notolderthan"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *NotOlderThanValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the d parameter.
+func (v *NotOlderThanValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return setDurationParam(field, fieldValue, raw)
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *NotOlderThanValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// WeekdayValidator validates that a time.Time falls on one of the configured
+// days, pipe-separated as three-letter or full English names
+// ("weekday,days=mon|tue|wed|thu|fri,loc=Europe/Amsterdam"). The day is taken
+// in Loc, an IANA zone name; without it, in the value's own location.
+type WeekdayValidator struct {
+	Days []time.Weekday `param:"days"`
+	Loc  *time.Location `param:"loc,required=false"`
+}
+
+// Validate checks whether the value falls on an allowed day.
+func (v *WeekdayValidator) Validate(val time.Time) error {
+	if len(v.Days) == 0 {
+		return errors.New(`value of parameter "days" cannot be empty`)
+	}
+	return checkWeekday(inLocation(val, v.Loc), v.Days)
+}
+
+// Name returns the directive identifier.
+func (v *WeekdayValidator) Name() string {
+	return "weekday"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *WeekdayValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the days and loc parameters.
+func (v *WeekdayValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return convertCalendarParam(v, field, fieldValue, raw)
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *WeekdayValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// TimeOfDayValidator validates that a time.Time's wall-clock time falls within
+// [Start, End), given as "15:04" or "15:04:05" in the tag
+// ("timeofday,start=09:00,end=17:30,loc=Europe/Amsterdam"). Start and End are
+// offsets from midnight; when Start is after End the window wraps past
+// midnight ("start=22:00,end=06:00"). The clock is read in Loc, or in the
+// value's own location when Loc is unset.
+type TimeOfDayValidator struct {
+	Start time.Duration  `param:"start"`
+	End   time.Duration  `param:"end"`
+	Loc   *time.Location `param:"loc,required=false"`
+}
+
+// Validate checks whether the value falls within the time-of-day window.
+func (v *TimeOfDayValidator) Validate(val time.Time) error {
+	return checkTimeOfDay(inLocation(val, v.Loc), v.Start, v.End)
+}
+
+// Name returns the directive identifier.
+func (v *TimeOfDayValidator) Name() string {
+	return "timeofday"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *TimeOfDayValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the start, end, and loc parameters.
+func (v *TimeOfDayValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return convertCalendarParam(v, field, fieldValue, raw)
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *TimeOfDayValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// BusinessHoursValidator validates that a time.Time falls within business
+// hours: on Days (Monday to Friday when unset) between Start and End (09:00 and
+// 17:00 when both are unset), read in Loc or the value's own location. Each
+// part takes the same form as in WeekdayValidator and TimeOfDayValidator:
+//
+//	val:"businesshours,loc=Europe/Amsterdam"
+//	val:"businesshours,days=mon|tue|wed|thu,start=08:30,end=16:00,loc=America/New_York"
+type BusinessHoursValidator struct {
+	Days  []time.Weekday `param:"days,required=false"`
+	Start time.Duration  `param:"start,required=false"`
+	End   time.Duration  `param:"end,required=false"`
+	Loc   *time.Location `param:"loc,required=false"`
+}
+
+// Validate checks whether the value falls within business hours.
+func (v *BusinessHoursValidator) Validate(val time.Time) error {
+	days := v.Days
+	if len(days) == 0 {
+		days = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	start, end := v.Start, v.End
+	if start == 0 && end == 0 {
+		start, end = 9*time.Hour, 17*time.Hour
+	}
+	val = inLocation(val, v.Loc)
+	if err := checkWeekday(val, days); err != nil {
+		return err
+	}
+	return checkTimeOfDay(val, start, end)
+}
+
+// Name returns the directive identifier.
+func (v *BusinessHoursValidator) Name() string {
+	return "businesshours"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *BusinessHoursValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the days, start, end, and loc parameters.
+func (v *BusinessHoursValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	return convertCalendarParam(v, field, fieldValue, raw)
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *BusinessHoursValidator) Handle(val time.Time) (time.Time, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+func checkWeekday(t time.Time, days []time.Weekday) error {
+	if !slices.Contains(days, t.Weekday()) {
+		return fmt.Errorf("time %s falls on a %s, which is not allowed", t.Format(time.RFC3339), t.Weekday())
+	}
+	return nil
+}
+
+func checkTimeOfDay(t time.Time, start, end time.Duration) error {
+	const day = 24 * time.Hour
+	if start < 0 || start >= day || end < 0 || end > day {
+		return errors.New(`"start" and "end" must be times of day`)
+	}
+	if start == end {
+		return errors.New(`"start" and "end" cannot be equal`)
+	}
+	h, m, s := t.Clock()
+	tod := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	in := tod >= start && tod < end
+	if start > end { // wraps past midnight
+		in = tod >= start || tod < end
+	}
+	if !in {
+		return fmt.Errorf("time %s is outside %s-%s", t.Format(time.RFC3339), formatTimeOfDay(start), formatTimeOfDay(end))
+	}
+	return nil
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	if d == 24*time.Hour {
+		return "24:00"
+	}
+	return time.Time{}.Add(d).Format("15:04")
+}
+
+// convertCalendarParam converts the days, start/end, and loc parameters shared
+// by the weekday, timeofday, and businesshours directives.
+func convertCalendarParam(directive interface{ Name() string }, field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	switch fieldValue.Type() {
+	case reflect.TypeOf([]time.Weekday(nil)):
+		return parsePipeList(directive, field, fieldValue, raw, parseWeekday)
+	case reflect.TypeOf(time.Duration(0)):
+		tod, err := parseTimeOfDay(raw)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(tod))
+		return nil
+	case reflect.TypeOf((*time.Location)(nil)):
+		loc, err := valex.CachedParam(directive, paramName(field), strings.TrimSpace(raw), time.LoadLocation)
+		if err != nil {
+			return fmt.Errorf("invalid location %q: %v", raw, err)
+		}
+		fieldValue.Set(reflect.ValueOf(loc))
+		return nil
+	}
+	return tagex.DefaultConvert(fieldValue, raw, paramName(field))
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func parseWeekday(item string) (time.Weekday, error) {
+	name := strings.ToLower(item)
+	if d, ok := weekdayNames[name]; ok {
+		return d, nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", item)
+}
+
+// parseTimeOfDay parses "15:04" or "15:04:05" as an offset from midnight.
+// "24:00" is accepted as the end of the day.
+func parseTimeOfDay(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.Sub(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time of day %q, expected HH:MM or HH:MM:SS", raw)
+}
+
+func setDurationParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	if fieldValue.Type() != reflect.TypeOf(time.Duration(0)) {
+		return tagex.NewConversionError(field, raw, "time.Duration")
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v", raw, err)
+	}
+	fieldValue.Set(reflect.ValueOf(d))
+	return nil
+}
@@ -0,0 +1,152 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+// fixedNow is Wednesday 2024-05-15 12:00 UTC.
+var fixedNow = time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+
+func fixedEnv() *valex.Env {
+	return valex.NewRegistry(valex.WithClock(valex.ClockFunc(func() time.Time { return fixedNow }))).Env()
+}
+
+func TestRelativeTimeValidators(t *testing.T) {
+	env := fixedEnv()
+	future := &FutureValidator{}
+	future.SetEnv(env)
+	past := &PastValidator{}
+	past.SetEnv(env)
+	within := &WithinValidator{D: 720 * time.Hour}
+	within.SetEnv(env)
+	notOlder := &NotOlderThanValidator{D: 24 * time.Hour}
+	notOlder.SetEnv(env)
+
+	tests := []struct {
+		v     interface{ Validate(time.Time) error }
+		input time.Time
+		ok    bool
+	}{
+		{future, fixedNow.Add(time.Second), true},
+		{future, fixedNow, false},
+		{future, fixedNow.Add(-time.Hour), false},
+		{past, fixedNow.Add(-time.Second), true},
+		{past, fixedNow, false},
+		{within, fixedNow.Add(-719 * time.Hour), true},
+		{within, fixedNow.Add(720 * time.Hour), true},
+		{within, fixedNow.Add(721 * time.Hour), false},
+		{within, fixedNow.Add(-721 * time.Hour), false},
+		{notOlder, fixedNow.Add(-23 * time.Hour), true},
+		{notOlder, fixedNow.Add(time.Hour), true},
+		{notOlder, fixedNow.Add(-25 * time.Hour), false},
+		{&WithinValidator{}, fixedNow, false}, // d must be positive
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%v): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+
+	// Without an environment the directives use the system clock.
+	if err := (&PastValidator{}).Validate(time.Now().Add(-time.Minute)); err != nil {
+		t.Errorf("expected a minute ago to be past on the system clock, got %v", err)
+	}
+}
+
+func TestCalendarValidators(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	saturday := time.Date(2024, 5, 18, 10, 0, 0, 0, time.UTC)
+	// 23:30 UTC on Friday is 01:30 Saturday in Amsterdam (CEST, UTC+2).
+	lateFriday := time.Date(2024, 5, 17, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		v     interface{ Validate(time.Time) error }
+		input time.Time
+		ok    bool
+	}{
+		{&WeekdayValidator{Days: weekdays}, fixedNow, true},
+		{&WeekdayValidator{Days: weekdays}, saturday, false},
+		{&WeekdayValidator{Days: weekdays}, lateFriday, true},
+		{&WeekdayValidator{Days: weekdays, Loc: amsterdam}, lateFriday, false},
+		{&WeekdayValidator{}, fixedNow, false},
+		{&TimeOfDayValidator{Start: 9 * time.Hour, End: 17 * time.Hour}, fixedNow, true},
+		{&TimeOfDayValidator{Start: 9 * time.Hour, End: 17 * time.Hour}, fixedNow.Add(-270 * time.Minute), false},
+		{&TimeOfDayValidator{Start: 9 * time.Hour, End: 17 * time.Hour, Loc: amsterdam}, fixedNow.Add(-270 * time.Minute), true},
+		{&TimeOfDayValidator{Start: 22 * time.Hour, End: 6 * time.Hour}, lateFriday, true},
+		{&TimeOfDayValidator{Start: 22 * time.Hour, End: 6 * time.Hour}, fixedNow, false},
+		{&TimeOfDayValidator{Start: 9 * time.Hour, End: 9 * time.Hour}, fixedNow, false},
+		{&BusinessHoursValidator{}, fixedNow, true},
+		{&BusinessHoursValidator{}, saturday, false},
+		{&BusinessHoursValidator{}, fixedNow.Add(6 * time.Hour), false},
+		{&BusinessHoursValidator{Loc: amsterdam}, fixedNow.Add(-270 * time.Minute), true},
+		{&BusinessHoursValidator{Days: []time.Weekday{time.Saturday}}, saturday, true},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%v): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestRelativeTimeTags(t *testing.T) {
+	reg := valex.NewRegistry(valex.WithClock(valex.ClockFunc(func() time.Time { return fixedNow })))
+	valex.MustRegisterDirectiveTo(reg, &FutureValidator{})
+	valex.MustRegisterDirectiveTo(reg, &PastValidator{})
+	valex.MustRegisterDirectiveTo(reg, &WithinValidator{})
+	valex.MustRegisterDirectiveTo(reg, &NotOlderThanValidator{})
+	valex.MustRegisterDirectiveTo(reg, &WeekdayValidator{})
+	valex.MustRegisterDirectiveTo(reg, &TimeOfDayValidator{})
+	valex.MustRegisterDirectiveTo(reg, &BusinessHoursValidator{})
+
+	type booking struct {
+		Start     time.Time `val:"future;within,d=720h"`
+		CreatedAt time.Time `val:"past;notolderthan,d=24h"`
+		Pickup    time.Time `val:"weekday,days=mon|tue|wed|thu|friday,loc=UTC;timeofday,start=08:30,end=18:00"`
+		Call      time.Time `val:"businesshours,loc=UTC"`
+	}
+	b := &booking{
+		Start:     fixedNow.Add(48 * time.Hour),
+		CreatedAt: fixedNow.Add(-time.Hour),
+		Pickup:    fixedNow,
+		Call:      fixedNow,
+	}
+	if err := reg.ValidateStruct(b); err != nil {
+		t.Fatalf("expected valid booking, got %v", err)
+	}
+
+	stale := *b
+	stale.CreatedAt = fixedNow.Add(-48 * time.Hour)
+	if err := reg.ValidateStruct(&stale); err == nil {
+		t.Fatal("expected a two-day-old CreatedAt to fail notolderthan,d=24h")
+	}
+
+	for _, tag := range []any{
+		&struct {
+			T time.Time `val:"within,d=soon"`
+		}{},
+		&struct {
+			T time.Time `val:"weekday,days=funday"`
+		}{},
+		&struct {
+			T time.Time `val:"timeofday,start=9am,end=17:00"`
+		}{},
+		&struct {
+			T time.Time `val:"businesshours,loc=Nowhere/Special"`
+		}{},
+	} {
+		if err := reg.ValidateStruct(tag); err == nil {
+			t.Errorf("%T: expected an invalid parameter to fail", tag)
+		}
+	}
+}
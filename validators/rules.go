@@ -0,0 +1,110 @@
+package validators
+
+// The rule types below are zero-size, value-receiver Validators for use as the
+// type parameter of valex.Refined, which can only call a validator it can
+// construct as a zero value:
+//
+//	type Contact struct {
+//		Email valex.Refined[string, validators.EmailRule] `json:"email"`
+//	}
+//
+// Each applies the corresponding directive with no parameters.
+
+// EmailRule validates an email address, like the email directive.
+type EmailRule struct{}
+
+// Validate checks whether the value is an email address.
+func (EmailRule) Validate(val string) error {
+	return (&EmailValidator{}).Validate(val)
+}
+
+// URLRule validates a URL, like the url directive.
+type URLRule struct{}
+
+// Validate checks whether the value is a URL.
+func (URLRule) Validate(val string) error {
+	return (&UrlValidator{}).Validate(val)
+}
+
+// UUIDRule validates a version 4 UUID, like the uuid directive.
+type UUIDRule struct{}
+
+// Validate checks whether the value is a UUID.
+func (UUIDRule) Validate(val string) error {
+	return (&UUIDValidator{}).Validate(val)
+}
+
+// HostnameRule validates a hostname, like the hostname directive.
+type HostnameRule struct{}
+
+// Validate checks whether the value is a hostname.
+func (HostnameRule) Validate(val string) error {
+	return (&HostnameValidator{}).Validate(val)
+}
+
+// SlugRule validates a URL slug, like the slug directive.
+type SlugRule struct{}
+
+// Validate checks whether the value is a slug.
+func (SlugRule) Validate(val string) error {
+	return (&SlugValidator{}).Validate(val)
+}
+
+// SemverRule validates a semantic version, like the semver directive.
+type SemverRule struct{}
+
+// Validate checks whether the value is a semantic version.
+func (SemverRule) Validate(val string) error {
+	return (&SemverValidator{}).Validate(val)
+}
+
+// E164Rule validates a phone number in E.164 form, like the e164 directive.
+type E164Rule struct{}
+
+// Validate checks whether the value is an E.164 phone number.
+func (E164Rule) Validate(val string) error {
+	return (&E164Validator{}).Validate(val)
+}
+
+// CountryRule validates an ISO 3166-1 alpha-2 or alpha-3 country code, like
+// the country directive.
+type CountryRule struct{}
+
+// Validate checks whether the value is a country code.
+func (CountryRule) Validate(val string) error {
+	return (&CountryValidator{}).Validate(val)
+}
+
+// CurrencyRule validates an ISO 4217 currency code, like the currency
+// directive.
+type CurrencyRule struct{}
+
+// Validate checks whether the value is a currency code.
+func (CurrencyRule) Validate(val string) error {
+	return (&CurrencyValidator{}).Validate(val)
+}
+
+// LanguageRule validates a BCP 47 language tag, like the language directive.
+type LanguageRule struct{}
+
+// Validate checks whether the value is a language tag.
+func (LanguageRule) Validate(val string) error {
+	return (&LanguageValidator{}).Validate(val)
+}
+
+// TimezoneRule validates an IANA time zone name, like the timezone directive.
+type TimezoneRule struct{}
+
+// Validate checks whether the value is a time zone name.
+func (TimezoneRule) Validate(val string) error {
+	return (&TimezoneValidator{}).Validate(val)
+}
+
+// NonEmptyRule validates that a string is not empty, like the !empty
+// directive.
+type NonEmptyRule struct{}
+
+// Validate checks whether the value is non-empty.
+func (NonEmptyRule) Validate(val string) error {
+	return (&NonEmptyStringValidator{}).Validate(val)
+}
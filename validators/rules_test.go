@@ -0,0 +1,46 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		v     valex.Validator[string]
+		input string
+		ok    bool
+	}{
+		{EmailRule{}, "gopher@example.com", true},
+		{EmailRule{}, "gopher", false},
+		{URLRule{}, "https://example.com/a", true},
+		{URLRule{}, "://", false},
+		{UUIDRule{}, "f47ac10b-58cc-4372-a567-0e02b2c3d479", true},
+		{UUIDRule{}, "123e4567", false},
+		{HostnameRule{}, "example.com", true},
+		{HostnameRule{}, "-bad-.com", false},
+		{SlugRule{}, "my-first-post", true},
+		{SlugRule{}, "My Post", false},
+		{SemverRule{}, "1.2.3", true},
+		{SemverRule{}, "1.2", false},
+		{E164Rule{}, "+31612345678", true},
+		{E164Rule{}, "0612345678", false},
+		{CountryRule{}, "NL", true},
+		{CountryRule{}, "XX", false},
+		{CurrencyRule{}, "EUR", true},
+		{CurrencyRule{}, "EURO", false},
+		{LanguageRule{}, "en-GB", true},
+		{LanguageRule{}, "en_GB", false},
+		{TimezoneRule{}, "Europe/Amsterdam", true},
+		{TimezoneRule{}, "Mars/Olympus", false},
+		{NonEmptyRule{}, "x", true},
+		{NonEmptyRule{}, "", false},
+	}
+	for _, tt := range tests {
+		err := tt.v.Validate(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tt.v, tt.input, tt.ok, err == nil, err)
+		}
+	}
+}
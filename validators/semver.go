@@ -0,0 +1,374 @@
+package validators
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+)
+
+// SemverValidator validates that a string is a Semantic Versioning 2.0.0
+// version ("1.4.2", "2.0.0-rc.1+build.5"), without a "v" prefix. If Constraint
+// is set, the version must also satisfy it:
+//
+//	val:"semver,constraint='>=1.2 <2'"
+//
+// See ParseSemverConstraint for the constraint syntax.
+type SemverValidator struct {
+	Constraint *SemverConstraint `param:"constraint,required=false"`
+}
+
+// Validate checks whether the value is a SemVer version satisfying Constraint.
+func (v *SemverValidator) Validate(val string) error {
+	ver, err := ParseSemver(val)
+	if err != nil {
+		return err
+	}
+	if v.Constraint != nil && !v.Constraint.Check(ver) {
+		return fmt.Errorf("version %s does not satisfy %q", val, v.Constraint)
+	}
+	return nil
+}
+
+// Name returns the directive identifier.
+func (v *SemverValidator) Name() string {
+	return "semver"
+}
+
+// Mode returns the directive evaluation mode.
+func (v *SemverValidator) Mode() tagex.DirectiveMode {
+	return tagex.EvalMode
+}
+
+// ConvertParam parses the constraint parameter.
+func (v *SemverValidator) ConvertParam(field reflect.StructField, fieldValue reflect.Value, raw string) error {
+	if fieldValue.Type() != reflect.TypeOf((*SemverConstraint)(nil)) {
+		return tagex.NewConversionError(field, raw, "*validators.SemverConstraint")
+	}
+	c, err := valex.CachedParam(v, paramName(field), raw, ParseSemverConstraint)
+	if err != nil {
+		return err
+	}
+	fieldValue.Set(reflect.ValueOf(c))
+	return nil
+}
+
+// Handle validates the value and returns it unchanged.
+func (v *SemverValidator) Handle(val string) (string, error) {
+	err := v.Validate(val)
+	return val, err
+}
+
+// Semver is a parsed Semantic Versioning 2.0.0 version.
+type Semver struct {
+	Major, Minor, Patch uint64
+	Prerelease          []string // dot-separated identifiers after '-', if any
+	Build               []string // dot-separated identifiers after '+', if any
+}
+
+// ParseSemver parses s as a SemVer 2.0.0 version. Numeric parts must not have
+// leading zeros, and a "v" prefix is not accepted.
+func ParseSemver(s string) (Semver, error) {
+	v, err := parseSemver(s, false)
+	if err != nil {
+		return Semver{}, err
+	}
+	return v.Semver, nil
+}
+
+// String returns the version in canonical form.
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0, or +1 as v has lower, equal, or higher precedence
+// than w. Build metadata is ignored, and a pre-release sorts before the
+// release it precedes ("1.0.0-rc.1" < "1.0.0").
+func (v Semver) Compare(w Semver) int {
+	if c := cmp.Compare(v.Major, w.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+	switch {
+	case len(v.Prerelease) == 0 && len(w.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(w.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(w.Prerelease); i++ {
+		if c := comparePrerelease(v.Prerelease[i], w.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.Prerelease), len(w.Prerelease))
+}
+
+// comparePrerelease orders two pre-release identifiers: numeric ones
+// numerically and below alphanumeric ones, alphanumeric ones in ASCII order.
+func comparePrerelease(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// partialSemver is a version that may omit trailing components, as written in
+// constraints ("1.2" has parts 2).
+type partialSemver struct {
+	Semver
+	parts int
+}
+
+// parseSemver parses a version. With partial set, as in constraints, a "v"
+// prefix and missing minor or patch components are allowed.
+func parseSemver(s string, partial bool) (partialSemver, error) {
+	invalid := func() (partialSemver, error) {
+		return partialSemver{}, fmt.Errorf("value %q is not a valid semantic version", s)
+	}
+	rest := s
+	if partial {
+		rest = strings.TrimPrefix(rest, "v")
+	}
+	var v partialSemver
+	if core, build, ok := strings.Cut(rest, "+"); ok {
+		if !validSemverIdents(build, false) {
+			return invalid()
+		}
+		v.Build = strings.Split(build, ".")
+		rest = core
+	}
+	if core, pre, ok := strings.Cut(rest, "-"); ok {
+		if !validSemverIdents(pre, true) {
+			return invalid()
+		}
+		v.Prerelease = strings.Split(pre, ".")
+		rest = core
+	}
+	nums := strings.Split(rest, ".")
+	if len(nums) > 3 || (!partial && len(nums) != 3) || (len(nums) < 3 && len(v.Prerelease) > 0) {
+		return invalid()
+	}
+	for i, n := range nums {
+		if !isDigits(n) || (len(n) > 1 && n[0] == '0') {
+			return invalid()
+		}
+		x, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return invalid()
+		}
+		switch i {
+		case 0:
+			v.Major = x
+		case 1:
+			v.Minor = x
+		case 2:
+			v.Patch = x
+		}
+	}
+	v.parts = len(nums)
+	return v, nil
+}
+
+func validSemverIdents(s string, noLeadingZeros bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for i := 0; i < len(id); i++ {
+			c := id[i]
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return false
+			}
+		}
+		if noLeadingZeros && len(id) > 1 && id[0] == '0' && isDigits(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// SemverConstraint is a parsed version constraint; see ParseSemverConstraint.
+type SemverConstraint struct {
+	raw    string
+	groups [][]semverRange // OR of ANDs
+}
+
+// semverRange is the set of versions in [lo, hi), either bound optional,
+// or its complement when negate is set.
+type semverRange struct {
+	lo, hi *Semver
+	negate bool
+}
+
+// ParseSemverConstraint parses a version constraint. A constraint is one or
+// more comparators separated by spaces or commas, all of which must hold;
+// alternatives are separated by "||". A comparator is an operator and a
+// version, which may be partial ("1.2") or carry a "v" prefix:
+//
+//	=1.2.3 or 1.2.3   exactly that version; "1.2" means any 1.2.x
+//	!=1.2.3           any other version
+//	>, >=, <, <=      ordered by SemVer precedence; ">1.2" means >=1.3.0
+//	~1.2.3            patch updates: >=1.2.3 <1.3.0
+//	^1.2.3            compatible updates: >=1.2.3 <2.0.0 (<0.3.0 for ^0.2.3)
+//
+// For example ">=1.2 <2", "^1.4 || ^2.1", or "~0.9.0, !=0.9.3". Pre-release
+// versions are compared by plain precedence, so "<2" admits "2.0.0-rc.1".
+func ParseSemverConstraint(s string) (*SemverConstraint, error) {
+	c := &SemverConstraint{raw: strings.TrimSpace(s)}
+	for _, alt := range strings.Split(s, "||") {
+		fields := strings.Fields(strings.ReplaceAll(alt, ",", " "))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q", s)
+		}
+		var group []semverRange
+		for i := 0; i < len(fields); i++ {
+			tok := fields[i]
+			// Allow a space between the operator and the version (">= 1.2").
+			if strings.Trim(tok, "=!<>~^") == "" && i+1 < len(fields) {
+				i++
+				tok += fields[i]
+			}
+			r, err := parseSemverComparator(tok)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+			}
+			group = append(group, r)
+		}
+		c.groups = append(c.groups, group)
+	}
+	return c, nil
+}
+
+func parseSemverComparator(tok string) (semverRange, error) {
+	op := tok[:len(tok)-len(strings.TrimLeft(tok, "=!<>~^"))]
+	p, err := parseSemver(tok[len(op):], true)
+	if err != nil {
+		return semverRange{}, err
+	}
+	lo := p.Semver
+	lo.Build = nil
+	next := p.next()
+	switch op {
+	case "", "=":
+		if p.parts == 3 {
+			return semverRange{lo: &lo, hi: lo.successor()}, nil
+		}
+		return semverRange{lo: &lo, hi: next}, nil
+	case "!=":
+		r, _ := parseSemverComparator("=" + tok[2:])
+		r.negate = true
+		return r, nil
+	case ">=":
+		return semverRange{lo: &lo}, nil
+	case ">":
+		if p.parts == 3 {
+			return semverRange{lo: lo.successor()}, nil
+		}
+		return semverRange{lo: next}, nil
+	case "<":
+		return semverRange{hi: &lo}, nil
+	case "<=":
+		if p.parts == 3 {
+			return semverRange{hi: lo.successor()}, nil
+		}
+		return semverRange{hi: next}, nil
+	case "~":
+		if p.parts == 3 {
+			p.parts = 2
+		}
+		return semverRange{lo: &lo, hi: p.next()}, nil
+	case "^":
+		// Bump the first non-zero component among those given, or the last
+		// given one when all are zero.
+		switch {
+		case lo.Major > 0 || p.parts == 1:
+			p.parts = 1
+		case lo.Minor > 0 || p.parts == 2:
+			p.parts = 2
+		}
+		return semverRange{lo: &lo, hi: p.next()}, nil
+	}
+	return semverRange{}, fmt.Errorf("unknown operator %q", op)
+}
+
+// next returns the lowest version above every version matching p's given
+// components ("1.2" -> 1.3.0-0), so "< next" excludes 1.3.0's pre-releases too.
+func (p partialSemver) next() *Semver {
+	n := Semver{Major: p.Major, Minor: p.Minor, Patch: p.Patch}
+	switch p.parts {
+	case 1:
+		n = Semver{Major: p.Major + 1}
+	case 2:
+		n = Semver{Major: p.Major, Minor: p.Minor + 1}
+	default:
+		n.Patch++
+	}
+	n.Prerelease = []string{"0"}
+	return &n
+}
+
+// successor returns the lowest version with higher precedence than v.
+func (v Semver) successor() *Semver {
+	s := v
+	if len(v.Prerelease) == 0 {
+		s = Semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, Prerelease: []string{"0"}}
+		return &s
+	}
+	s.Prerelease = append(append([]string(nil), v.Prerelease...), "0")
+	return &s
+}
+
+func (r semverRange) contains(v Semver) bool {
+	in := (r.lo == nil || v.Compare(*r.lo) >= 0) && (r.hi == nil || v.Compare(*r.hi) < 0)
+	return in != r.negate
+}
+
+// Check reports whether v satisfies the constraint.
+func (c *SemverConstraint) Check(v Semver) bool {
+	for _, group := range c.groups {
+		ok := true
+		for _, r := range group {
+			if !r.contains(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as it was written.
+func (c *SemverConstraint) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.raw
+}
@@ -0,0 +1,191 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input string
+		ok    bool
+	}{
+		{"1.4.2", true},
+		{"0.0.0", true},
+		{"2.0.0-rc.1+build.5", true},
+		{"1.0.0-alpha-1.x-y", true},
+		{"1.0.0+20130313144700", true},
+		{"v1.4.2", false},
+		{"1.4", false},
+		{"01.4.2", false},
+		{"1.4.2-01", false},
+		{"1.4.2-", false},
+		{"1.4.2+", false},
+		{"1.4.2-rc..1", false},
+		{"1.4.2-rc_1", false},
+		{"99999999999999999999.0.0", false},
+	}
+	for _, tc := range tests {
+		_, err := ParseSemver(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("ParseSemver(%q): expected ok=%v, got ok=%v (err: %v)", tc.input, tc.ok, ok, err)
+		}
+	}
+	v, _ := ParseSemver("2.0.0-rc.1+build.5")
+	if v.String() != "2.0.0-rc.1+build.5" {
+		t.Errorf("String() = %q", v.String())
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	// The SemVer 2.0.0 specification's precedence example, in ascending order.
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseSemver(ordered[i])
+		b, _ := ParseSemver(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+	a, _ := ParseSemver("1.0.0+a")
+	b, _ := ParseSemver("1.0.0+b")
+	if a.Compare(b) != 0 {
+		t.Error("expected build metadata to be ignored")
+	}
+}
+
+func TestSemverConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		ok         bool
+	}{
+		{">=1.2 <2", "1.2.0", true},
+		{">=1.2 <2", "1.9.9", true},
+		{">=1.2 <2", "2.0.0", false},
+		{">=1.2 <2", "1.1.9", false},
+		{">= 1.2, < 2", "1.5.0", true},
+		{"1.2", "1.2.7", true},
+		{"1.2", "1.3.0", false},
+		{"=1.2.3", "1.2.3+build", true},
+		{"=1.2.3", "1.2.4", false},
+		{"!=1.2.3", "1.2.4", true},
+		{"!=1.2.3", "1.2.3", false},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{">1.2.3", "1.2.4-0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<=1.2", "1.3.0", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"^1.4 || ^2.1", "2.5.0", true},
+		{"^1.4 || ^2.1", "2.0.0", false},
+		{"v1.x", "1.0.0", false}, // wildcards are not supported; parse fails below
+	}
+	for _, tc := range tests {
+		c, err := ParseSemverConstraint(tc.constraint)
+		if err != nil {
+			if tc.ok {
+				t.Errorf("ParseSemverConstraint(%q): %v", tc.constraint, err)
+			}
+			continue
+		}
+		v, err := ParseSemver(tc.version)
+		if err != nil {
+			t.Fatalf("ParseSemver(%q): %v", tc.version, err)
+		}
+		if got := c.Check(v); got != tc.ok {
+			t.Errorf("%q.Check(%s) = %v, want %v", tc.constraint, tc.version, got, tc.ok)
+		}
+	}
+	for _, bad := range []string{"", "||", ">=", "=>1.2", "1.2.3.4", "~>1.2"} {
+		if _, err := ParseSemverConstraint(bad); err == nil {
+			t.Errorf("ParseSemverConstraint(%q): expected error", bad)
+		}
+	}
+}
+
+func TestIdentifierValidators(t *testing.T) {
+	tests := []struct {
+		v     interface{ Validate(string) error }
+		input string
+		ok    bool
+	}{
+		{&SlugValidator{}, "my-first-post", true},
+		{&SlugValidator{}, "post2", true},
+		{&SlugValidator{}, "My-Post", false},
+		{&SlugValidator{}, "double--hyphen", false},
+		{&SlugValidator{}, "-leading", false},
+		{&SlugValidator{}, "", false},
+		{&IdentifierValidator{}, "userID", true},
+		{&IdentifierValidator{}, "_x9", true},
+		{&IdentifierValidator{}, "größe", true},
+		{&IdentifierValidator{}, "9lives", false},
+		{&IdentifierValidator{}, "func", false},
+		{&IdentifierValidator{}, "a-b", false},
+		{&DNSLabelValidator{}, "web-01", true},
+		{&DNSLabelValidator{}, "Web01", true},
+		{&DNSLabelValidator{}, "-web", false},
+		{&DNSLabelValidator{}, "web.example", false},
+		{&DNSLabelValidator{}, string(make([]byte, 64)), false},
+		{&K8sNameValidator{}, "api.v1", true},
+		{&K8sNameValidator{}, "web-0", true},
+		{&K8sNameValidator{}, "Web-0", false},
+		{&K8sNameValidator{}, "web_0", false},
+		{&K8sNameValidator{}, "web.", false},
+		{&K8sLabelValueValidator{}, "", true},
+		{&K8sLabelValueValidator{}, "v1.2_beta-3", true},
+		{&K8sLabelValueValidator{}, "_hidden", false},
+		{&K8sLabelValueValidator{}, "has space", false},
+		{&EnvVarNameValidator{}, "DATABASE_URL", true},
+		{&EnvVarNameValidator{}, "_private1", true},
+		{&EnvVarNameValidator{}, "1ST", false},
+		{&EnvVarNameValidator{}, "MY-VAR", false},
+		{&EnvVarNameValidator{Upper: true}, "Path", false},
+		{&EnvVarNameValidator{Upper: true}, "PATH_2", true},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
+func TestSemverAndIdentifierTags(t *testing.T) {
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &SemverValidator{})
+	valex.MustRegisterDirectiveTo(reg, &SlugValidator{})
+	valex.MustRegisterDirectiveTo(reg, &K8sNameValidator{})
+
+	type component struct {
+		Version string `val:"semver,constraint='>=1.2 <2'"`
+		Slug    string `val:"slug"`
+		Deploy  string `val:"k8sname"`
+	}
+	if err := reg.ValidateStruct(&component{"1.4.0", "billing-api", "billing-api.v1"}); err != nil {
+		t.Fatalf("expected valid component, got %v", err)
+	}
+	if err := reg.ValidateStruct(&component{"2.0.0", "billing-api", "billing-api"}); err == nil {
+		t.Fatal("expected 2.0.0 to violate the constraint")
+	}
+
+	type badConstraint struct {
+		Version string `val:"semver,constraint='>>1'"`
+	}
+	if err := reg.ValidateStruct(&badConstraint{"1.0.0"}); err == nil {
+		t.Fatal("expected an invalid constraint to fail")
+	}
+}
@@ -0,0 +1,174 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tedla-brandsema/valex"
+)
+
+// HostResolver looks up the addresses of a host. *net.Resolver implements it;
+// tests can stub it to make UrlValidator's resolve mode deterministic.
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+type hostResolverKey struct{}
+
+// WithHostResolver returns a registry option that supplies r to the url
+// directives registered on that registry which have no Resolver of their own:
+//
+//	reg := valex.NewRegistry(validators.WithHostResolver(stub))
+func WithHostResolver(r HostResolver) valex.Option {
+	return valex.WithValue(hostResolverKey{}, r)
+}
+
+// resolveTimeout bounds a single host lookup in UrlValidator's resolve mode.
+const resolveTimeout = 5 * time.Second
+
+func parsePortParam(item string) (int, error) {
+	port, err := strconv.Atoi(item)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", item)
+	}
+	return port, nil
+}
+
+// checkURLPort checks the URL's port, or its scheme's default port when none is
+// given, against an allowlist and a denylist. Either may be empty.
+func checkURLPort(u *url.URL, allow, deny []int) error {
+	port := 0
+	if p := u.Port(); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return fmt.Errorf("invalid url port %q", p)
+		}
+		port = n
+	} else {
+		switch strings.ToLower(u.Scheme) {
+		case "http", "ws":
+			port = 80
+		case "https", "wss":
+			port = 443
+		}
+	}
+	if len(allow) > 0 && !slices.Contains(allow, port) {
+		return fmt.Errorf("url port %d is not allowed", port)
+	}
+	if slices.Contains(deny, port) {
+		return fmt.Errorf("url port %d is not allowed", port)
+	}
+	return nil
+}
+
+// checkPublicHost rejects a host that names the local machine or a private
+// network without any DNS lookup: non-public IP literals, numeric hosts that
+// some clients read as IPv4 shorthand, and reserved local names.
+func checkPublicHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("url host %s is not a public address", host)
+		}
+		return nil
+	}
+	if isNumericHost(host) {
+		return fmt.Errorf("url host %q is an ambiguous numeric address", host)
+	}
+	if host == "localhost" {
+		return errors.New("url host must not be localhost")
+	}
+	for _, suffix := range []string{".localhost", ".local", ".internal"} {
+		if strings.HasSuffix(host, suffix) {
+			return fmt.Errorf("url host %q is a local name", host)
+		}
+	}
+	return nil
+}
+
+// resolvePublicHost resolves host and rejects it unless every address is
+// public. IP literals were already checked by checkPublicHost.
+func resolvePublicHost(r HostResolver, host string) error {
+	if _, err := netip.ParseAddr(host); err == nil {
+		return nil
+	}
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	addrs, err := r.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("url host %q could not be resolved: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("url host %q has no addresses", host)
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return fmt.Errorf("url host %q resolves to non-public address %s", host, addr)
+		}
+	}
+	return nil
+}
+
+// nonPublicPrefixes are special-purpose ranges not covered by the netip.Addr
+// predicates used in isPublicAddr.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+}
+
+// isPublicAddr reports whether addr is a globally routable unicast address.
+// IPv4-mapped IPv6 addresses are judged by their IPv4 form.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumericHost reports whether host looks like an IPv4 address in a form
+// netip rejects but some resolvers accept: a bare integer, fewer than four
+// parts, or octal/hex parts ("2130706433", "127.1", "0x7f.0.0.1").
+func isNumericHost(host string) bool {
+	for _, part := range strings.Split(host, ".") {
+		if part == "" {
+			return false
+		}
+		if p, ok := strings.CutPrefix(part, "0x"); ok {
+			if p != "" && strings.Trim(p, "0123456789abcdef") == "" {
+				continue
+			}
+			return false
+		}
+		if !isDigits(part) {
+			return false
+		}
+	}
+	return true
+}
@@ -1130,6 +1130,48 @@ func TestAlphaNumericValidator(t *testing.T) {
 	}
 }
 
+func TestCharacterClassValidators(t *testing.T) {
+	tests := []struct {
+		v     valex.Validator[string]
+		input string
+		ok    bool
+	}{
+		{&AlphaValidator{}, "abcXYZ", true},
+		{&AlphaValidator{}, "abc1", false},
+		{&AlphaValidator{}, "Zoë", false},
+		{&AlphaValidator{}, "", false},
+		{&NumericValidator{}, "0123", true},
+		{&NumericValidator{}, "12.3", false},
+		{&NumericValidator{}, "١٢٣", false}, // Arabic-Indic digits are not ASCII
+		{&NumericValidator{}, "", false},
+		{&UnicodeLetterValidator{}, "Zoë", true},
+		{&UnicodeLetterValidator{}, "Zoe\u0308", true},
+		{&UnicodeLetterValidator{}, "日本語", true},
+		{&UnicodeLetterValidator{}, "Zoë 2", false},
+		{&UnicodeLetterValidator{}, "\xff", false},
+		{&UnicodeLetterValidator{}, "", false},
+		{&PrintableValidator{}, "Hello, Zoë!", true},
+		{&PrintableValidator{}, "", true},
+		{&PrintableValidator{}, "tab\there", false},
+		{&PrintableValidator{}, "\xff", false},
+		{&ASCIIValidator{}, "plain ascii ~", true},
+		{&ASCIIValidator{}, "", true},
+		{&ASCIIValidator{}, "Zoë", false},
+		{&NoControlCharValidator{}, "Zoë", true},
+		{&NoControlCharValidator{}, "", true},
+		{&NoControlCharValidator{}, "line\nbreak", false},
+		{&NoControlCharValidator{}, "nul\x00", false},
+		{&NoControlCharValidator{}, "\xff", false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v (err: %v)", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
 func TestMACAddressValidator(t *testing.T) {
 	v := &MACAddressValidator{}
 	tests := []struct {
@@ -1217,6 +1259,25 @@ func TestIPv6Validator(t *testing.T) {
 	}
 }
 
+func TestRegexParamCached(t *testing.T) {
+	before := valex.ParamCacheStats()
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &RegexValidator{})
+	type code struct {
+		V string `val:"regex,pattern=^[A-Z]{3}-[0-9]{4}$"`
+	}
+	for _, v := range []string{"ABC-1234", "XYZ-0000", "bad"} {
+		err := reg.ValidateStruct(&code{v})
+		if ok := err == nil; ok != (v != "bad") {
+			t.Errorf("regex(%q): got err %v", v, err)
+		}
+	}
+	s := valex.ParamCacheStats()
+	if misses, lookups := s.Misses-before.Misses, s.Hits+s.Misses-before.Hits-before.Misses; misses > 1 || lookups != 3 {
+		t.Errorf("expected the pattern to compile once, stats = %+v before, %+v after", before, s)
+	}
+}
+
 func TestXMLValidator(t *testing.T) {
 	v := &XMLValidator{}
 	tests := []struct {
@@ -1288,6 +1349,35 @@ func TestJSONValidator(t *testing.T) {
 	}
 }
 
+func TestStructuredLimits(t *testing.T) {
+	deepJSON := strings.Repeat("[", 20) + strings.Repeat("]", 20)
+	deepXML := strings.Repeat("<a>", 20) + strings.Repeat("</a>", 20)
+	tests := []struct {
+		v     interface{ Validate(string) error }
+		input string
+		ok    bool
+	}{
+		{&JSONValidator{MaxDepth: 20}, deepJSON, true},
+		{&JSONValidator{MaxDepth: 19}, deepJSON, false},
+		{&JSONValidator{MaxDepth: 1}, `{"a": "[[[{{{"}`, true}, // brackets inside strings don't count
+		{&JSONValidator{MaxDepth: 1}, `{"a": "\"[", "b": [1]}`, false},
+		{&JSONValidator{MaxBytes: 7}, `[1,2,3]`, true},
+		{&JSONValidator{MaxBytes: 6}, `[1,2,3]`, false},
+		{&XMLValidator{MaxDepth: 20}, deepXML, true},
+		{&XMLValidator{MaxDepth: 19}, deepXML, false},
+		{&XMLValidator{MaxDepth: 2}, `<a><b/><b/><b/></a>`, true},
+		{&XMLValidator{MaxBytes: 8}, `<a></a>`, true},
+		{&XMLValidator{MaxBytes: 6}, `<a></a>`, false},
+	}
+	for _, tc := range tests {
+		err := tc.v.Validate(tc.input)
+		ok := err == nil
+		if ok != tc.ok {
+			t.Errorf("%T(%q): expected ok=%v, got ok=%v, error: %v", tc.v, tc.input, tc.ok, ok, err)
+		}
+	}
+}
+
 func TestCompositeValidator_String(t *testing.T) {
 	nonEmpty := &NonEmptyStringValidator{}
 	minLength := &MinLengthValidator{Size: 3}
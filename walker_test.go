@@ -0,0 +1,203 @@
+package valex_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tedla-brandsema/tagex"
+	"github.com/tedla-brandsema/valex"
+	"github.com/tedla-brandsema/valex/validators"
+)
+
+// valex walks "val" itself (see process.go); these tests pin its output to
+// tagex's walker, with and without extra tags, so the two cannot drift apart
+// unnoticed.
+
+type walkLeaf struct {
+	S string `val:"min,size=1"`
+}
+
+type walkTree struct {
+	Name string `val:"min,size=1"`
+	Leaf walkLeaf
+	Ptr  *walkLeaf
+	List []walkLeaf
+	Arr  [2]walkLeaf
+	Map  map[string]walkLeaf
+}
+
+type walkCycle struct {
+	Next *walkCycle
+	S    string `val:"min,size=1"`
+}
+
+// walkers returns the same "val" directive set as a tagex tag and a Registry.
+func walkers(t *testing.T) (*tagex.Tag, *valex.Registry) {
+	t.Helper()
+	tag := tagex.NewTag("val")
+	tagex.MustRegisterDirective(tag, &validators.MinLengthValidator{})
+	reg := valex.NewRegistry()
+	valex.MustRegisterDirectiveTo(reg, &validators.MinLengthValidator{})
+	return tag, reg
+}
+
+func errorStrings(err error) map[string]string {
+	m := make(map[string]string)
+	for path, e := range valex.FieldErrors(err) {
+		m[path] = e.Error()
+	}
+	return m
+}
+
+func TestWalkerMatchesTagex(t *testing.T) {
+	tag, reg := walkers(t)
+	newTree := func() *walkTree {
+		return &walkTree{
+			Ptr:  &walkLeaf{},
+			List: []walkLeaf{{S: "ok"}, {}},
+			Map:  map[string]walkLeaf{"k": {}},
+		}
+	}
+
+	want := errorStrings(tagex.ProcessStructAll(newTree(), tag))
+	got := errorStrings(reg.ValidateStructAll(newTree()))
+	if len(want) == 0 || !reflect.DeepEqual(got, want) {
+		t.Fatalf("ValidateStructAll errors = %v, tagex = %v", got, want)
+	}
+
+	one := func() *walkTree { return &walkTree{Name: "ok", Map: map[string]walkLeaf{"k": {}}} }
+	wantErr, gotErr := tagex.ProcessStruct(one(), tag), reg.ValidateStruct(one())
+	if wantErr == nil || gotErr == nil || gotErr.Error() != wantErr.Error() {
+		t.Fatalf("ValidateStruct = %v, tagex = %v", gotErr, wantErr)
+	}
+}
+
+func TestWalkerDepthMatchesTagex(t *testing.T) {
+	tag, reg := walkers(t)
+	ring := func() *walkCycle {
+		c := &walkCycle{S: "ok"}
+		c.Next = c
+		return c
+	}
+	wantErr, gotErr := tagex.ProcessStruct(ring(), tag), reg.ValidateStruct(ring())
+	if wantErr == nil || gotErr == nil || gotErr.Error() != wantErr.Error() {
+		t.Fatalf("ValidateStruct = %v, tagex = %v", gotErr, wantErr)
+	}
+}
+
+type walkMixed struct {
+	A    string `val:"min,size=5"`
+	B    string `chk:"min,size=5"`
+	C    string `chk:"upper" val:"min,size=5"`
+	N    int    `chk:"min,size=1"`
+	Leaf walkLeaf
+	List []walkChk
+}
+
+type walkChk struct {
+	S string `chk:"min,size=1" val:"min,size=1"`
+}
+
+// upperDirective upper-cases a string in tagex.MutMode.
+type upperDirective struct{}
+
+func (d *upperDirective) Name() string              { return "upper" }
+func (d *upperDirective) Mode() tagex.DirectiveMode { return tagex.MutMode }
+func (d *upperDirective) Handle(s string) (string, error) {
+	return strings.ToUpper(s), nil
+}
+
+// chkTag returns an extra tag with the same "min" directive as walkers.
+func chkTag() *tagex.Tag {
+	chk := tagex.NewTag("chk")
+	tagex.MustRegisterDirective(chk, &validators.MinLengthValidator{})
+	tagex.MustRegisterDirective(chk, &upperDirective{})
+	return chk
+}
+
+func TestWalkerExtraTagsMatchTagex(t *testing.T) {
+	tag, reg := walkers(t)
+	chk := chkTag()
+	newMixed := func() *walkMixed {
+		return &walkMixed{C: "abcdef", List: []walkChk{{S: "ok"}, {}}}
+	}
+
+	want, got := newMixed(), newMixed()
+	wantErr, gotErr := tagex.ProcessStruct(want, chk, tag), reg.ValidateStruct(got, chk)
+	if wantErr == nil || gotErr == nil || gotErr.Error() != wantErr.Error() {
+		t.Fatalf("ValidateStruct = %v, tagex = %v", gotErr, wantErr)
+	}
+
+	want, got = newMixed(), newMixed()
+	wantErr, gotErr = tagex.ProcessStructAll(want, chk, tag), reg.ValidateStructAll(got, chk)
+	if wantErr == nil || gotErr == nil || gotErr.Error() != wantErr.Error() {
+		t.Fatalf("ValidateStructAll =\n%v\ntagex =\n%v", gotErr, wantErr)
+	}
+	if !reflect.DeepEqual(got, want) || got.C != "ABCDEF" {
+		t.Fatalf("ValidateStructAll left %+v, tagex %+v", got, want)
+	}
+}
+
+// The tests below pin where the walker differs from tagex on purpose (see
+// process.go).
+
+func TestWalkerResolvesRegistryNames(t *testing.T) {
+	tag, reg := walkers(t)
+	reg.MustAlias("name", "min,size=$n")
+	type T struct {
+		A string `val:"omitempty;min,size=3"`
+		B string `val:"create:min,size=3"`
+		C string `val:"name,n=1"`
+	}
+	if err := tagex.ProcessStruct(&T{C: "x"}, tag); err == nil {
+		t.Fatal("tagex accepted omitempty, a group prefix, and an alias")
+	}
+	if err := reg.ValidateStruct(&T{C: "x"}); err != nil {
+		t.Fatalf("ValidateStruct: %v", err)
+	}
+}
+
+func TestWalkerFieldMask(t *testing.T) {
+	_, reg := walkers(t)
+	err := reg.ValidateStructAllWith(&walkTree{List: []walkLeaf{{}}}, valex.Fields("List"))
+	got := errorStrings(err)
+	if _, ok := got["List[0].S"]; len(got) != 1 || !ok {
+		t.Fatalf("ValidateStructAllWith(Fields(List)) errors = %v", got)
+	}
+}
+
+type walkStringer struct {
+	S string `chk:"min,size=1"`
+}
+
+func (s walkStringer) String() string { return s.S }
+
+type stringerDirective struct{}
+
+func (d *stringerDirective) Name() string              { return "stringer" }
+func (d *stringerDirective) Mode() tagex.DirectiveMode { return tagex.EvalMode }
+func (d *stringerDirective) Handle(s fmt.Stringer) (fmt.Stringer, error) {
+	return s, nil
+}
+
+func TestWalkerExtraTagInterfaceDirective(t *testing.T) {
+	tag, reg := walkers(t)
+	chk := chkTag()
+	tagex.MustRegisterDirective(chk, &stringerDirective{})
+	type T struct {
+		V walkStringer `chk:"stringer"`
+	}
+	var mismatch *valex.TypeMismatchError
+	if err := tagex.ProcessStruct(&T{V: walkStringer{S: "x"}}, chk, tag); !errors.As(err, &mismatch) {
+		t.Fatalf("tagex = %v, want a type mismatch", err)
+	}
+	if err := reg.ValidateStruct(&T{V: walkStringer{S: "x"}}, chk); err != nil {
+		t.Fatalf("ValidateStruct: %v", err)
+	}
+	if err := reg.ValidateStruct(&T{}, chk); err == nil {
+		t.Fatal("ValidateStruct skipped the nested chk field")
+	}
+}